@@ -0,0 +1,130 @@
+// Command reef prints weather, forecasts and alerts for a North American
+// location, for smoke-testing the feeds and for terminal dashboards.
+//
+//	reef [flags] weather|forecast|alerts COUNTRY
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"reef-na/feeds"
+)
+
+// errUsage marks errors caused by bad arguments, which exit with status 2
+var errUsage = errors.New("usage")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "reef:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses arguments and prints the requested data to stdout
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("reef", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	units := fs.String("units", string(feeds.UnitsMetric), "unit system: metric or imperial")
+	format := fs.String("o", formatTable, "output format: table, json or yaml")
+	provider := fs.String("provider", "open-meteo", "current conditions provider: open-meteo, nws (US) or eccc (CA), or a comma-separated fallback chain such as nws,open-meteo")
+	city := fs.String("city", "", "city within the country, for weather")
+	days := fs.Int("days", 7, "forecast days (1-7)")
+	lang := fs.String("lang", string(feeds.LanguageEnglish), "language of descriptions: en, es or fr")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: reef [flags] weather|forecast|alerts COUNTRY")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errUsage
+	}
+	command, country := fs.Arg(0), strings.ToUpper(fs.Arg(1))
+
+	out, err := newPrinter(*format, feeds.UnitSystem(*units), stdout)
+	if err != nil {
+		return err
+	}
+	language, err := feeds.ParseLanguage(*lang)
+	if err != nil {
+		return err
+	}
+	client, err := newClient(*provider, feeds.UnitSystem(*units), language)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "weather":
+		var w *feeds.WeatherData
+		if *city != "" {
+			w, err = client.FetchWeatherForCity(country, *city)
+		} else {
+			w, err = client.FetchWeather(country)
+		}
+		if err != nil {
+			return err
+		}
+		return out.weather(w)
+	case "forecast":
+		f, err := client.FetchForecast(country, *days)
+		if err != nil {
+			return err
+		}
+		return out.forecast(f)
+	case "alerts":
+		alerts, err := client.FetchWeatherAlerts(country)
+		if err != nil {
+			return err
+		}
+		return out.alerts(alerts)
+	default:
+		return fmt.Errorf("unknown command %q, want weather, forecast or alerts", command)
+	}
+}
+
+// providerTimeout bounds each provider of a fallback chain
+const providerTimeout = 10 * time.Second
+
+// newClient creates a Client for a provider name, or comma-separated chain
+// of names, a unit system and a language
+func newClient(provider string, units feeds.UnitSystem, lang feeds.Language) (*feeds.Client, error) {
+	if units != feeds.UnitsMetric && units != feeds.UnitsImperial {
+		return nil, fmt.Errorf("unknown units %q, want metric or imperial", units)
+	}
+	options := []feeds.Option{feeds.WithUnits(units), feeds.WithLanguage(lang), feeds.WithUserAgent("reef-cli")}
+	var chain []feeds.WeatherProvider
+	for _, name := range strings.Split(provider, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "open-meteo", "openmeteo":
+			chain = append(chain, feeds.NewOpenMeteoProvider(options...))
+		case "nws":
+			chain = append(chain, feeds.NewNWSProvider(options...))
+		case "eccc":
+			chain = append(chain, feeds.NewECCCProvider(options...))
+		default:
+			return nil, fmt.Errorf("unknown provider %q, want open-meteo, nws or eccc", name)
+		}
+	}
+	if len(chain) == 1 {
+		if _, ok := chain[0].(*feeds.OpenMeteoProvider); ok {
+			return feeds.NewClient(options...), nil // the Client's own, sharing its cache
+		}
+		return feeds.NewClient(append(options, feeds.WithProvider(chain[0]))...), nil
+	}
+	return feeds.NewClient(append(options, feeds.WithProvider(feeds.NewFallbackProvider(providerTimeout, chain...)))...), nil
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"reef-na/feeds"
+)
+
+// Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// printer writes results in one output format
+type printer struct {
+	format string
+	units  feeds.UnitSystem
+	w      io.Writer
+}
+
+// newPrinter creates a printer for a format name
+func newPrinter(format string, units feeds.UnitSystem, w io.Writer) (*printer, error) {
+	switch format {
+	case formatTable, formatJSON, formatYAML:
+		return &printer{format: format, units: units, w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, want table, json or yaml", format)
+	}
+}
+
+// encode writes v as JSON or YAML and reports whether the format was one of them
+func (p *printer) encode(v any) (bool, error) {
+	switch p.format {
+	case formatJSON:
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return true, enc.Encode(v)
+	case formatYAML:
+		return true, writeYAML(p.w, v)
+	}
+	return false, nil
+}
+
+func (p *printer) weather(w *feeds.WeatherData) error {
+	if ok, err := p.encode(w); ok {
+		return err
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	row := func(label, format string, args ...any) {
+		fmt.Fprintf(tw, "%s\t"+format+"\n", append([]any{label}, args...)...)
+	}
+	if w.ConditionSeverity != "" {
+		row("Conditions", "%s (%s)", w.Summary, w.ConditionSeverity)
+	} else {
+		row("Conditions", "%s", w.Summary)
+	}
+	if p.units == feeds.UnitsImperial {
+		imp := w.ToImperial()
+		if w.Imperial != nil {
+			imp = *w.Imperial
+		}
+		row("Temperature", "%.1f °F (feels like %.1f °F)", imp.TemperatureF, imp.FeelsLikeF)
+		row("Wind", "%.0f mph from %.0f° (gusts %.0f mph)", imp.WindSpeedMph, w.WindDirectionDeg, imp.WindGustsMph)
+		row("Precipitation", "%.2f in", imp.PrecipitationIn)
+		if imp.PressureInHg > 0 {
+			row("Pressure", "%.2f inHg", imp.PressureInHg)
+		}
+	} else {
+		row("Temperature", "%.1f °C (feels like %.1f °C)", w.TemperatureC, w.FeelsLikeC)
+		row("Wind", "%.0f km/h from %.0f° (gusts %.0f km/h)", w.WindSpeedKmh, w.WindDirectionDeg, w.WindGustsKmh)
+		row("Precipitation", "%.1f mm", w.PrecipitationMM)
+		if w.PressureHPa > 0 {
+			row("Pressure", "%.0f hPa", w.PressureHPa)
+		}
+	}
+	row("Humidity", "%.0f%%", w.HumidityPct)
+	row("Cloud cover", "%.0f%%", w.CloudCoverPct)
+	if w.UVIndex != nil {
+		row("UV index", "%.1f (%s)", *w.UVIndex, w.UVBand)
+	}
+	row("Location", "%.4f, %.4f (%s)", w.Latitude, w.Longitude, w.Source)
+	if !w.ObservedAt.IsZero() {
+		row("Observed", "%s (%s)", w.ObservedAt.Format(time.DateTime+" MST"), w.Timezone)
+	}
+	row("Provider", "%s", w.Provider)
+	return tw.Flush()
+}
+
+func (p *printer) forecast(f *feeds.ForecastData) error {
+	if ok, err := p.encode(f); ok {
+		return err
+	}
+	unit, temp := "°C", func(c float64) float64 { return c }
+	if p.units == feeds.UnitsImperial {
+		unit, temp = "°F", feeds.CelsiusToFahrenheit
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "DATE\tCONDITIONS\tHIGH\tLOW\tPRECIP\n")
+	for _, d := range f.Days {
+		precip := "-"
+		if d.PrecipitationProbability != nil {
+			precip = fmt.Sprintf("%d%%", *d.PrecipitationProbability)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.0f %s\t%.0f %s\t%s\n",
+			d.Date.Format("Mon Jan 2"), d.Summary, temp(d.HighC), unit, temp(d.LowC), unit, precip)
+	}
+	return tw.Flush()
+}
+
+func (p *printer) alerts(alerts []feeds.WeatherAlert) error {
+	if alerts == nil {
+		alerts = []feeds.WeatherAlert{}
+	}
+	if ok, err := p.encode(alerts); ok {
+		return err
+	}
+	if len(alerts) == 0 {
+		_, err := fmt.Fprintln(p.w, "No active alerts")
+		return err
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "SEVERITY\tEVENT\tEXPIRES\tHEADLINE\n")
+	for _, a := range alerts {
+		expires := "-"
+		if !a.Expires.IsZero() {
+			expires = a.Expires.Format(time.DateTime + " MST") // in the location's zone
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", cmp.Or(a.SeverityLabel, a.Severity), a.Event, expires, a.Headline)
+	}
+	return tw.Flush()
+}
+
+// writeYAML writes v as YAML, going through its JSON encoding so field
+// names and omitted fields match the JSON output
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	node, err := readNode(dec)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	switch n := node.(type) {
+	case yamlMap, []any:
+		if isEmpty(n) {
+			fmt.Fprintln(&buf, yamlScalar(n))
+		} else {
+			writeYAMLNode(&buf, n, 0)
+		}
+	default:
+		fmt.Fprintln(&buf, yamlScalar(n))
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// yamlMap is a JSON object with its keys in order
+type yamlMap []yamlEntry
+
+type yamlEntry struct {
+	key   string
+	value any
+}
+
+// readNode reads one JSON value, keeping object keys in order
+func readNode(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		m := yamlMap{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := readNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, yamlEntry{key.(string), v})
+		}
+		_, err := dec.Token()
+		return m, err
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			v, err := readNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		_, err := dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+// writeYAMLNode writes a non-empty map or list in block style
+func writeYAMLNode(buf *bytes.Buffer, node any, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch n := node.(type) {
+	case yamlMap:
+		for _, e := range n {
+			if isEmpty(e.value) || !isCollection(e.value) {
+				fmt.Fprintf(buf, "%s%s: %s\n", pad, yamlString(e.key), yamlScalar(e.value))
+				continue
+			}
+			fmt.Fprintf(buf, "%s%s:\n", pad, yamlString(e.key))
+			child := indent + 2
+			if _, ok := e.value.([]any); ok {
+				child = indent
+			}
+			writeYAMLNode(buf, e.value, child)
+		}
+	case []any:
+		for _, item := range n {
+			if isEmpty(item) || !isCollection(item) {
+				fmt.Fprintf(buf, "%s- %s\n", pad, yamlScalar(item))
+				continue
+			}
+			// Write the item two columns in, then put the dash on its first line
+			var sub bytes.Buffer
+			writeYAMLNode(&sub, item, indent+2)
+			b := sub.Bytes()
+			copy(b[indent:], "- ")
+			buf.Write(b)
+		}
+	}
+}
+
+// isCollection reports whether a node is a map or list
+func isCollection(node any) bool {
+	switch node.(type) {
+	case yamlMap, []any:
+		return true
+	}
+	return false
+}
+
+// isEmpty reports whether a node is an empty map or list
+func isEmpty(node any) bool {
+	switch n := node.(type) {
+	case yamlMap:
+		return len(n) == 0
+	case []any:
+		return len(n) == 0
+	}
+	return false
+}
+
+// yamlScalar formats a scalar, or an empty map or list in flow style
+func yamlScalar(node any) string {
+	switch n := node.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(n)
+	case json.Number:
+		return n.String()
+	case string:
+		return yamlString(n)
+	case yamlMap:
+		return "{}"
+	case []any:
+		return "[]"
+	}
+	return fmt.Sprint(node)
+}
+
+// yamlString quotes a string when YAML would read it as something else
+func yamlString(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, ":#{}[],&*!|>'\"%@`\n\t\\") ||
+		strings.ContainsRune("-?", rune(s[0])) {
+		return strconv.Quote(s)
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	return s
+}
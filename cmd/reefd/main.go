@@ -0,0 +1,230 @@
+// Command reefd serves North American feeds as a REST API, keeping each
+// served country's feeds fresh in the background
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"reef-na/config"
+	"reef-na/digest"
+	"reef-na/feeds"
+	"reef-na/health"
+	"reef-na/internal/grpcapi"
+	"reef-na/internal/logger"
+	"reef-na/internal/server"
+	"reef-na/notify"
+	"reef-na/rules"
+)
+
+// shutdownTimeout bounds how long in-flight requests may take to finish
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	grpcAddr := flag.String("grpc-addr", ":9090", "gRPC listen address (plaintext HTTP/2), empty to disable")
+	configPath := flag.String("config", "", "config file (YAML or JSON); REEF_ environment variables override it")
+	countries := flag.String("countries", "", "comma-separated country codes to serve, replacing the config's locations (default US,CA,MX)")
+	healthInterval := flag.Duration("health-interval", time.Minute, "how often upstream providers are probed for /healthz")
+	logLevel := flag.String("log-level", "info", "log level (debug, info, warn, error)")
+	flag.Parse()
+	logger.Init(*logLevel)
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		level = slog.LevelInfo
+	}
+	feeds.SetLogger(slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	feeds.SetLogLevel(level)
+	if *healthInterval <= 0 {
+		logger.Errorf("invalid -health-interval %s: must be positive", *healthInterval)
+		os.Exit(1)
+	}
+
+	cfg, err := loadConfig(*configPath, *countries)
+	if err != nil {
+		logger.Errorf("invalid configuration: %v", err)
+		os.Exit(1)
+	}
+	metrics := feeds.NewMetrics()
+	client := feeds.NewClient(cfg.ClientOptions(feeds.WithMetrics(metrics))...)
+	registry, err := cfg.Registry(client)
+	if err != nil {
+		logger.Errorf("%v", err)
+		os.Exit(1)
+	}
+	sched := feeds.NewScheduler(client, cfg.SchedulerOptions(feeds.WithSchedulerRegistry(registry))...)
+	if err := cfg.Track(sched); err != nil {
+		logger.Errorf("cannot serve %v", err)
+		os.Exit(1)
+	}
+	locs, _ := cfg.FeedLocations() // validated by loadConfig
+	checker := newChecker(client, registry, locs[0], *healthInterval)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	notifier := notify.New(cfg.NotifyOptions(notify.WithOnError(func(sink string, n notify.Notification, err error) {
+		logger.Errorf("%s notification %q not delivered: %v", sink, n.Subject, err)
+	}))...)
+	digests := digest.NewGenerator(client, cfg.DigestOptions(digest.WithOnDigest(func(d *digest.Digest) {
+		logger.Infof("digest for %s generated", cmp.Or(d.Country, fmt.Sprintf("%.4f,%.4f", d.Latitude, d.Longitude)))
+		go func() { _ = notifier.Notify(ctx, notify.DigestNotification(d)) }()
+	}))...)
+	engine := cfg.RuleEngine(rules.WithOnFire(func(f rules.Firing) {
+		logger.Infof("rule %q fired for %s: %s", f.Rule, f.Location, f.Message)
+		go func() { _ = notifier.Notify(ctx, notify.RuleNotification(f)) }()
+	}))
+
+	go func() {
+		if err := sched.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Errorf("scheduler stopped: %v", err)
+		}
+	}()
+	go func() { _ = checker.Run(ctx) }()
+	if cfg.Digest.Schedule != "" {
+		go func() { _ = digests.Run(ctx) }()
+	}
+	if len(cfg.Notifiers) > 0 {
+		go func() { _ = notifier.WatchAlerts(ctx, sched) }()
+	}
+	if len(cfg.Rules) > 0 {
+		go func() { _ = engine.Run(ctx, sched) }()
+	}
+	if *configPath != "" {
+		watcher := config.NewWatcher(*configPath, cfg,
+			config.Runtime{Client: client, Registry: registry, Scheduler: sched, Checker: checker},
+			config.WithLoader(func() (*config.Config, error) { return loadConfig(*configPath, *countries) }),
+			config.WithReloadHook(logReload))
+		go func() { _ = watcher.Run(ctx) }()
+		go reloadOnHangup(ctx, watcher)
+	}
+
+	handler := server.New(sched, server.WithMetrics(metrics), server.WithHealth(checker), server.WithDigests(digests))
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	srv.RegisterOnShutdown(handler.Close)
+	servers := []*http.Server{srv}
+
+	if *grpcAddr != "" {
+		api := grpcapi.New(sched)
+		grpcSrv := &http.Server{
+			Addr:              *grpcAddr,
+			Handler:           api,
+			ReadHeaderTimeout: 5 * time.Second,
+			Protocols:         new(http.Protocols),
+		}
+		grpcSrv.Protocols.SetUnencryptedHTTP2(true)
+		grpcSrv.RegisterOnShutdown(api.Close)
+		servers = append(servers, grpcSrv)
+	}
+
+	for _, s := range servers {
+		go func() {
+			logger.Infof("reefd listening on %s", s.Addr)
+			if err := s.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Errorf("server on %s failed: %v", s.Addr, err)
+				stop()
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	logger.Infof("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, s := range servers {
+		if err := s.Shutdown(shutdownCtx); err != nil {
+			logger.Errorf("graceful shutdown of %s failed: %v", s.Addr, err)
+		}
+	}
+}
+
+// loadConfig loads the config file, or the defaults without one, with
+// environment overrides and countries, if any, replacing its locations
+func loadConfig(path, countries string) (*config.Config, error) {
+	var cfg *config.Config
+	if path != "" {
+		var err error
+		if cfg, err = config.Load(path); err != nil {
+			return nil, err
+		}
+	} else {
+		cfg = config.Default()
+		if err := cfg.ApplyEnv(os.Environ()); err != nil {
+			return nil, err
+		}
+	}
+	if countries != "" {
+		cfg.Locations = nil
+		for _, country := range strings.Split(countries, ",") {
+			if country = strings.TrimSpace(country); country != "" {
+				cfg.Locations = append(cfg.Locations, config.Location{Country: country})
+			}
+		}
+	}
+	return cfg, cfg.Validate()
+}
+
+// newChecker probes the weather provider and every registered feed at loc,
+// logging status changes
+func newChecker(client *feeds.Client, registry *feeds.Registry, loc feeds.Location, interval time.Duration) *health.Checker {
+	var probes []health.Probe
+	if loc.Country != "" {
+		probes = append(probes, health.WeatherProbe(client, loc.Country))
+	}
+	for _, f := range registry.Feeds() {
+		probes = append(probes, health.FeedProbe(f, loc))
+	}
+	return health.NewChecker(
+		health.WithInterval(interval),
+		health.WithProbes(probes...),
+		health.WithOnChange(func(p health.ProviderStatus) {
+			switch p.Status {
+			case health.Healthy:
+				logger.Infof("provider %s is healthy", p.Name)
+			default:
+				logger.Warnf("provider %s is %s after %d failed probes: %s", p.Name, p.Status, p.ConsecutiveFailures, p.LastError)
+			}
+		}),
+	)
+}
+
+// logReload logs what a config reload changed
+func logReload(ev config.Event) {
+	if len(ev.Applied) > 0 {
+		logger.Infof("config reloaded, applied %s", strings.Join(ev.Applied, ", "))
+	}
+	if len(ev.Restart) > 0 {
+		logger.Warnf("config changes to %s take effect on restart", strings.Join(ev.Restart, ", "))
+	}
+	if ev.Err != nil {
+		logger.Errorf("config reload failed: %v", ev.Err)
+	}
+}
+
+// reloadOnHangup reloads the config file on SIGHUP until ctx is done
+func reloadOnHangup(ctx context.Context, w *config.Watcher) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			w.Reload()
+		}
+	}
+}
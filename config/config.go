@@ -0,0 +1,643 @@
+// Package config loads reefd's settings — locations, feeds, refresh
+// intervals, providers, units and API keys — from a YAML or JSON file, with
+// environment variable overrides.
+//
+// A YAML config file looks like:
+//
+//	units: imperial
+//	providers: [nws, open-meteo]  # current conditions, in fallback order
+//	providerTimeout: 5s
+//	cacheTTL: 10m
+//	locations:
+//	  - country: US
+//	  - country: CA
+//	    lat: 43.6532
+//	    lon: -79.3832
+//	feeds: [alerts, forecast, sun] # all when omitted
+//	intervals:
+//	  weather: 5m
+//	  alerts: 2m
+//	apiKeys:
+//	  eia: ...
+//	digest:
+//	  schedule: "0 7 * * *" # each location's local time
+//	rules:
+//	  - name: Extreme cold
+//	    when: temperature < -20
+//	  - name: Damaging gusts
+//	    when: wind gust > 80 km/h
+//	    cooldown: 6h
+//	notifiers:
+//	  - type: slack
+//	    url: https://hooks.slack.com/services/...
+//	    events: [alert, rule]
+//	    minSeverity: severe
+//	  - type: smtp
+//	    host: smtp.example.com:587
+//	    username: reef
+//	    password: ...
+//	    from: reef@example.com
+//	    to: [ops@example.com]
+//
+// Only the subset of YAML such files need is supported: block mappings and
+// sequences, flow sequences of scalars, quoted and plain scalars and
+// comments; anchors, multi-line strings and flow mappings are rejected.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"reef-na/digest"
+	"reef-na/feeds"
+	"reef-na/notify"
+	"reef-na/rules"
+)
+
+// Environment variables overriding the config file. REEF_PROVIDERS,
+// REEF_LOCATIONS and REEF_FEEDS take comma-separated lists; locations set
+// this way are country codes. REEF_INTERVAL_<FEED> sets one feed's refresh
+// interval, e.g. REEF_INTERVAL_AIRQUALITY=15m.
+const (
+	EnvUnits           = "REEF_UNITS"
+	EnvProviders       = "REEF_PROVIDERS"
+	EnvProviderTimeout = "REEF_PROVIDER_TIMEOUT"
+	EnvCacheTTL        = "REEF_CACHE_TTL"
+	EnvLocations       = "REEF_LOCATIONS"
+	EnvFeeds           = "REEF_FEEDS"
+	EnvIntervalPrefix  = "REEF_INTERVAL_"
+	EnvEIAKey          = "REEF_EIA_KEY"
+	EnvGooglePollenKey = "REEF_GOOGLE_POLLEN_KEY"
+	EnvDigestSchedule  = "REEF_DIGEST_SCHEDULE"
+)
+
+// Defaults not left to the feeds package
+const (
+	defaultProviderTimeout = 10 * time.Second
+	defaultForecastDays    = 7
+)
+
+// providerNames are the current-conditions providers a config may name
+var providerNames = []string{feeds.ProviderOpenMeteo, feeds.ProviderNWS, feeds.ProviderECCC}
+
+// Config is the complete set of reefd settings
+type Config struct {
+	Units feeds.UnitSystem `json:"units"`
+	// Providers are the current-conditions providers, open-meteo, nws or
+	// eccc, tried in order when there are several
+	Providers []string `json:"providers"`
+	// ProviderTimeout bounds each provider's attempt when there are several
+	ProviderTimeout Duration `json:"providerTimeout"`
+	// CacheTTL is how long upstream responses are reused; zero keeps the
+	// Client's default
+	CacheTTL  Duration   `json:"cacheTTL"`
+	Locations []Location `json:"locations"`
+	// Feeds are the names of the feeds to refresh, all of them when empty
+	Feeds []string `json:"feeds"`
+	// Intervals override refresh intervals, "weather" included, which default
+	// to the feeds' TTLs
+	Intervals map[string]Duration `json:"intervals"`
+	APIKeys   APIKeys             `json:"apiKeys"`
+	Digest    Digest              `json:"digest"`
+	// Rules are threshold conditions over feed data that notify when they
+	// start to hold
+	Rules []Rule `json:"rules"`
+	// Notifiers are where alerts, digests and rule firings are pushed to
+	Notifiers []Notifier `json:"notifiers"`
+}
+
+// Location is a place to keep feeds fresh for: a country's registered
+// coordinates, or explicit coordinates within an optional country
+type Location struct {
+	Country string   `json:"country"`
+	Lat     *float64 `json:"lat"`
+	Lon     *float64 `json:"lon"`
+}
+
+// APIKeys are the keys of upstreams that need one
+type APIKeys struct {
+	EIA          string `json:"eia"`          // US fuel prices
+	GooglePollen string `json:"googlePollen"` // pollen outside Europe
+}
+
+// Digest configures the daily summaries of each location
+type Digest struct {
+	// Schedule is a cron expression such as "0 7 * * *", in each location's
+	// time zone, for generating digests; when empty they are only generated
+	// on request
+	Schedule string `json:"schedule"`
+	// Headlines is how many headlines a digest carries, 5 when zero
+	Headlines int `json:"headlines"`
+}
+
+// Rule is a threshold condition evaluated on every refresh
+type Rule struct {
+	Name string `json:"name"`
+	// When is the condition, such as "aqi > 150" or "wind gust > 80 km/h"
+	When string `json:"when"`
+	// Cooldown is the least time between notifications of the rule at a
+	// location, an hour when zero
+	Cooldown Duration `json:"cooldown"`
+}
+
+// parse returns the rules.Rule of a configured rule
+func (r Rule) parse() (rules.Rule, error) {
+	if r.Name == "" {
+		return rules.Rule{}, errors.New("needs a name")
+	}
+	if r.Cooldown < 0 {
+		return rules.Rule{}, errors.New("cooldown must not be negative")
+	}
+	parsed, err := rules.ParseRule(r.Name, r.When)
+	if err != nil {
+		return rules.Rule{}, err
+	}
+	parsed.Cooldown = time.Duration(r.Cooldown)
+	return parsed, nil
+}
+
+// Notifier types
+const (
+	NotifierSMTP    = "smtp"
+	NotifierWebhook = "webhook"
+	NotifierSlack   = "slack"
+	NotifierDiscord = "discord"
+)
+
+// notifierTypes are the notifier types a config may name
+var notifierTypes = []string{NotifierSMTP, NotifierWebhook, NotifierSlack, NotifierDiscord}
+
+// Notifier is a destination for alerts and digests
+type Notifier struct {
+	// Type is smtp, webhook, slack or discord
+	Type string `json:"type"`
+	// URL is where webhook, slack and discord notifiers post
+	URL string `json:"url"`
+	// Headers are added to a webhook's requests
+	Headers map[string]string `json:"headers"`
+	// Host is an smtp notifier's server, as host:port
+	Host     string   `json:"host"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	// Events are the kinds of notification to send, alert, digest and
+	// rule; all of them when empty
+	Events []string `json:"events"`
+	// MinSeverity is the least severe alert to send, minor, moderate,
+	// severe or extreme; every alert when empty
+	MinSeverity string `json:"minSeverity"`
+	// Subject and Template are text/templates over a notify.Notification
+	// replacing the default email subject and message body
+	Subject  string `json:"subject"`
+	Template string `json:"template"`
+}
+
+// sink creates the notifier's sink and the filters of what it receives
+func (n Notifier) sink() (notify.Sink, []notify.Filter, error) {
+	var opts []notify.SinkOption
+	if n.Template != "" {
+		opts = append(opts, notify.WithTemplate(n.Template))
+	}
+	var sink notify.Sink
+	var err error
+	switch n.Type {
+	case NotifierSMTP:
+		if n.Subject != "" {
+			opts = append(opts, notify.WithSubjectTemplate(n.Subject))
+		}
+		if n.Username != "" {
+			opts = append(opts, notify.WithSMTPAuth(n.Username, n.Password))
+		}
+		sink, err = notify.NewSMTPSink(n.Host, n.From, n.To, opts...)
+	case NotifierWebhook, NotifierSlack, NotifierDiscord:
+		if u, perr := url.Parse(n.URL); perr != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, nil, fmt.Errorf("invalid url %q, want an http or https URL", n.URL)
+		}
+		for key, value := range n.Headers {
+			opts = append(opts, notify.WithHeader(key, value))
+		}
+		switch n.Type {
+		case NotifierSlack:
+			sink, err = notify.NewSlackSink(n.URL, opts...)
+		case NotifierDiscord:
+			sink, err = notify.NewDiscordSink(n.URL, opts...)
+		default:
+			sink, err = notify.NewWebhookSink(n.URL, opts...)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown type %q, want %s", n.Type, strings.Join(notifierTypes, ", "))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var filters []notify.Filter
+	for _, e := range n.Events {
+		if e != notify.KindAlert && e != notify.KindDigest && e != notify.KindRule {
+			return nil, nil, fmt.Errorf("unknown event %q, want %s, %s or %s", e, notify.KindAlert, notify.KindDigest, notify.KindRule)
+		}
+	}
+	if len(n.Events) > 0 {
+		filters = append(filters, notify.OnKinds(n.Events...))
+	}
+	if n.MinSeverity != "" {
+		if !notify.ValidAlertSeverity(n.MinSeverity) {
+			return nil, nil, fmt.Errorf("unknown minSeverity %q, want minor, moderate, severe or extreme", n.MinSeverity)
+		}
+		filters = append(filters, notify.MinAlertSeverity(n.MinSeverity))
+	}
+	return sink, filters, nil
+}
+
+// equal reports whether n and o are the same settings
+func (n Notifier) equal(o Notifier) bool {
+	return n.Type == o.Type && n.URL == o.URL && maps.Equal(n.Headers, o.Headers) &&
+		n.Host == o.Host && n.Username == o.Username && n.Password == o.Password &&
+		n.From == o.From && slices.Equal(n.To, o.To) && slices.Equal(n.Events, o.Events) &&
+		n.MinSeverity == o.MinSeverity && n.Subject == o.Subject && n.Template == o.Template
+}
+
+// Duration is a time.Duration written as a string such as "90s" or "5m"
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"5m\", got %s", data)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalText writes the duration as a string
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Default returns the settings reefd uses without a config file: metric
+// units, Open-Meteo, every feed and the US, Canada and Mexico
+func Default() *Config {
+	return &Config{
+		Units:           feeds.UnitsMetric,
+		Providers:       []string{feeds.ProviderOpenMeteo},
+		ProviderTimeout: Duration(defaultProviderTimeout),
+		Locations:       []Location{{Country: "US"}, {Country: "CA"}, {Country: "MX"}},
+	}
+}
+
+// Load reads a config file, YAML or JSON by its extension, applies
+// environment variable overrides and validates the result. Settings missing
+// from the file keep their Default values.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	c, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := c.ApplyEnv(os.Environ()); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse decodes a config in the given format, "yaml", "yml" or "json", over
+// the Default settings. Unknown settings are errors. The result is not
+// validated.
+func Parse(data []byte, format string) (*Config, error) {
+	switch format {
+	case "yaml", "yml":
+		tree, err := parseYAML(data)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = json.Marshal(tree); err != nil {
+			return nil, err
+		}
+	case "json":
+	default:
+		return nil, fmt.Errorf("unsupported config format %q, want yaml or json", format)
+	}
+	c := Default()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return c, nil
+}
+
+// ApplyEnv overrides settings from REEF_ variables in environ, which has the
+// form of os.Environ
+func (c *Config) ApplyEnv(environ []string) error {
+	var errs []error
+	duration := func(name, v string) Duration {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+		return Duration(d)
+	}
+	for _, kv := range environ {
+		name, v, _ := strings.Cut(kv, "=")
+		switch {
+		case name == EnvUnits:
+			c.Units = feeds.UnitSystem(v)
+		case name == EnvProviders:
+			c.Providers = splitList(v)
+		case name == EnvProviderTimeout:
+			c.ProviderTimeout = duration(name, v)
+		case name == EnvCacheTTL:
+			c.CacheTTL = duration(name, v)
+		case name == EnvLocations:
+			c.Locations = nil
+			for _, country := range splitList(v) {
+				c.Locations = append(c.Locations, Location{Country: country})
+			}
+		case name == EnvFeeds:
+			c.Feeds = splitList(v)
+		case name == EnvEIAKey:
+			c.APIKeys.EIA = v
+		case name == EnvGooglePollenKey:
+			c.APIKeys.GooglePollen = v
+		case name == EnvDigestSchedule:
+			c.Digest.Schedule = v
+		case strings.HasPrefix(name, EnvIntervalPrefix):
+			feed := strings.TrimPrefix(name, EnvIntervalPrefix)
+			if i := slices.IndexFunc(intervalNames, func(f string) bool { return strings.EqualFold(f, feed) }); i >= 0 {
+				feed = intervalNames[i]
+			}
+			if c.Intervals == nil {
+				c.Intervals = make(map[string]Duration)
+			}
+			c.Intervals[feed] = duration(name, v)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// feedNames are the feeds reefd can refresh
+var feedNames = func() []string {
+	var names []string
+	for _, f := range availableFeeds(nil) {
+		names = append(names, f.Name())
+	}
+	return names
+}()
+
+// intervalNames are the feeds whose refresh intervals can be set: current
+// weather, which the Scheduler always refreshes, and feedNames
+var intervalNames = append([]string{"weather"}, feedNames...)
+
+// availableFeeds returns every feed reefd can refresh, fetched through c
+func availableFeeds(c *feeds.Client) []feeds.Feed {
+	return append(feeds.DefaultFeeds(c), feeds.ForecastFeed(c, defaultForecastDays), feeds.NewsFeed(c, 0))
+}
+
+// Validate checks every setting, reporting all problems at once
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Units != feeds.UnitsMetric && c.Units != feeds.UnitsImperial {
+		errs = append(errs, fmt.Errorf("unknown units %q, want metric or imperial", c.Units))
+	}
+	if len(c.Providers) == 0 {
+		errs = append(errs, errors.New("no providers"))
+	}
+	for i, p := range c.Providers {
+		if !slices.Contains(providerNames, p) {
+			errs = append(errs, fmt.Errorf("unknown provider %q, want %s", p, strings.Join(providerNames, ", ")))
+		} else if slices.Contains(c.Providers[:i], p) {
+			errs = append(errs, fmt.Errorf("provider %q listed twice", p))
+		}
+	}
+	if c.ProviderTimeout < 0 {
+		errs = append(errs, errors.New("providerTimeout must not be negative"))
+	}
+	if c.CacheTTL < 0 {
+		errs = append(errs, errors.New("cacheTTL must not be negative"))
+	}
+	if len(c.Locations) == 0 {
+		errs = append(errs, errors.New("no locations"))
+	}
+	for i, loc := range c.Locations {
+		if _, err := loc.resolve(); err != nil {
+			errs = append(errs, fmt.Errorf("location %d: %w", i+1, err))
+		}
+	}
+	for _, name := range c.Feeds {
+		if !slices.Contains(feedNames, name) {
+			errs = append(errs, fmt.Errorf("unknown feed %q, want one of %s", name, strings.Join(feedNames, ", ")))
+		}
+	}
+	for name, d := range c.Intervals {
+		switch {
+		case !slices.Contains(intervalNames, name):
+			errs = append(errs, fmt.Errorf("interval for unknown feed %q", name))
+		case d <= 0:
+			errs = append(errs, fmt.Errorf("interval for %s must be positive", name))
+		}
+	}
+	if c.Digest.Schedule != "" {
+		if _, err := digest.ParseSchedule(c.Digest.Schedule); err != nil {
+			errs = append(errs, fmt.Errorf("digest: %w", err))
+		}
+	}
+	if c.Digest.Headlines < 0 {
+		errs = append(errs, errors.New("digest headlines must not be negative"))
+	}
+	for i, r := range c.Rules {
+		if _, err := r.parse(); err != nil {
+			errs = append(errs, fmt.Errorf("rule %d: %w", i+1, err))
+		} else if slices.ContainsFunc(c.Rules[:i], func(o Rule) bool { return o.Name == r.Name }) {
+			errs = append(errs, fmt.Errorf("rule %q listed twice", r.Name))
+		}
+	}
+	for i, n := range c.Notifiers {
+		if _, _, err := n.sink(); err != nil {
+			errs = append(errs, fmt.Errorf("notifier %d: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resolve returns the feeds.Location of a configured location
+func (l Location) resolve() (feeds.Location, error) {
+	switch {
+	case l.Lat == nil && l.Lon == nil:
+		if l.Country == "" {
+			return feeds.Location{}, errors.New("needs a country or lat and lon")
+		}
+		return feeds.CountryLocation(l.Country)
+	case l.Lat == nil || l.Lon == nil:
+		return feeds.Location{}, errors.New("needs both lat and lon")
+	}
+	loc := feeds.Location{
+		Country:     strings.ToUpper(l.Country),
+		Coordinates: feeds.Coordinates{Lat: *l.Lat, Lon: *l.Lon},
+	}
+	return loc, loc.Validate()
+}
+
+// newProvider creates a current-conditions provider by name
+func newProvider(name string, options ...feeds.Option) feeds.WeatherProvider {
+	switch name {
+	case feeds.ProviderNWS:
+		return feeds.NewNWSProvider(options...)
+	case feeds.ProviderECCC:
+		return feeds.NewECCCProvider(options...)
+	default:
+		return feeds.NewOpenMeteoProvider(options...)
+	}
+}
+
+// ClientOptions returns the feeds.Client options for the settings, followed
+// by options, which also apply to the Clients of the providers. The Config
+// must be valid.
+func (c *Config) ClientOptions(options ...feeds.Option) []feeds.Option {
+	base := []feeds.Option{feeds.WithUnits(c.Units)}
+	if c.CacheTTL > 0 {
+		base = append(base, feeds.WithCacheTTL(time.Duration(c.CacheTTL)))
+	}
+	if c.APIKeys.EIA != "" {
+		base = append(base, feeds.WithEIAKey(c.APIKeys.EIA))
+	}
+	if c.APIKeys.GooglePollen != "" {
+		base = append(base, feeds.WithGooglePollenKey(c.APIKeys.GooglePollen))
+	}
+	base = append(base, options...)
+
+	if len(c.Providers) == 1 && c.Providers[0] == feeds.ProviderOpenMeteo {
+		return base // the Client's own provider, sharing its cache
+	}
+	var chain []feeds.WeatherProvider
+	for _, name := range c.Providers {
+		chain = append(chain, newProvider(name, base...))
+	}
+	if len(chain) == 1 {
+		return append(base, feeds.WithProvider(chain[0]))
+	}
+	return append(base, feeds.WithProvider(feeds.NewFallbackProvider(time.Duration(c.ProviderTimeout), chain...)))
+}
+
+// Registry returns a Registry of the enabled feeds, fetched through client
+func (c *Config) Registry(client *feeds.Client) (*feeds.Registry, error) {
+	r := feeds.NewRegistry()
+	for _, f := range availableFeeds(client) {
+		if len(c.Feeds) > 0 && !slices.Contains(c.Feeds, f.Name()) {
+			continue
+		}
+		if err := r.Register(f); err != nil {
+			return nil, fmt.Errorf("failed to register %s feed: %w", f.Name(), err)
+		}
+	}
+	return r, nil
+}
+
+// SchedulerOptions returns the feeds.Scheduler options for the refresh
+// intervals, followed by options
+func (c *Config) SchedulerOptions(options ...feeds.SchedulerOption) []feeds.SchedulerOption {
+	var opts []feeds.SchedulerOption
+	for name, d := range c.Intervals {
+		opts = append(opts, feeds.WithFeedInterval(name, time.Duration(d)))
+	}
+	return append(opts, options...)
+}
+
+// Track adds the configured locations to s
+func (c *Config) Track(s *feeds.Scheduler) error {
+	for i, l := range c.Locations {
+		var err error
+		if l.Lat == nil && l.Lon == nil {
+			err = s.TrackCountry(l.Country)
+		} else {
+			var loc feeds.Location
+			if loc, err = l.resolve(); err == nil {
+				err = s.Track(loc)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("location %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// FeedLocations returns the configured locations
+func (c *Config) FeedLocations() ([]feeds.Location, error) {
+	locs := make([]feeds.Location, 0, len(c.Locations))
+	for i, l := range c.Locations {
+		loc, err := l.resolve()
+		if err != nil {
+			return nil, fmt.Errorf("location %d: %w", i+1, err)
+		}
+		locs = append(locs, loc)
+	}
+	return locs, nil
+}
+
+// DigestOptions returns the digest.Generator options for the configured
+// locations and digest settings, followed by options. The Config must be valid.
+func (c *Config) DigestOptions(options ...digest.Option) []digest.Option {
+	locs, _ := c.FeedLocations()
+	opts := []digest.Option{digest.WithLocations(locs...), digest.WithHeadlineLimit(c.Digest.Headlines)}
+	if c.Digest.Schedule != "" {
+		opts = append(opts, digest.WithSchedule(digest.MustParseSchedule(c.Digest.Schedule)))
+	}
+	return append(opts, options...)
+}
+
+// NotifyOptions returns the notify.Notifier options for the configured
+// notifiers, followed by options. The Config must be valid.
+func (c *Config) NotifyOptions(options ...notify.Option) []notify.Option {
+	var opts []notify.Option
+	for _, n := range c.Notifiers {
+		if sink, filters, err := n.sink(); err == nil {
+			opts = append(opts, notify.WithSink(sink, filters...))
+		}
+	}
+	return append(opts, options...)
+}
+
+// RuleEngine returns a rules.Engine of the configured rules with options.
+// The Config must be valid.
+func (c *Config) RuleEngine(options ...rules.Option) *rules.Engine {
+	var parsed []rules.Rule
+	for _, r := range c.Rules {
+		if rule, err := r.parse(); err == nil {
+			parsed = append(parsed, rule)
+		}
+	}
+	return rules.NewEngine(parsed, options...)
+}
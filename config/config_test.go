@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"reef-na/feeds"
+)
+
+// writeConfig writes a config file into a temporary directory and returns
+// its path
+func writeConfig(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestWeatherInterval(t *testing.T) {
+	c, err := Load(writeConfig(t, "reefd.yaml", "intervals:\n  weather: 5m\n  alerts: 2m\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := time.Duration(c.Intervals["weather"]); got != 5*time.Minute {
+		t.Errorf("weather interval = %v, want 5m", got)
+	}
+
+	if err := c.ApplyEnv([]string{"REEF_INTERVAL_WEATHER=90s"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := time.Duration(c.Intervals["weather"]); got != 90*time.Second {
+		t.Errorf("weather interval from REEF_INTERVAL_WEATHER = %v, want 1m30s", got)
+	}
+
+	s := feeds.NewScheduler(feeds.NewClient(), c.SchedulerOptions()...)
+	if got := s.Interval("weather"); got != 90*time.Second {
+		t.Errorf("Scheduler weather interval = %v, want 1m30s", got)
+	}
+	next := *c
+	next.Intervals = map[string]Duration{"alerts": Duration(2 * time.Minute)}
+	ev := Runtime{Client: feeds.NewClient(), Registry: feeds.NewRegistry(), Scheduler: s}.apply(c, &next)
+	if ev.Err != nil || len(ev.Applied) != 1 || ev.Applied[0] != "intervals.weather" {
+		t.Errorf("apply = %v, %v, want intervals.weather applied", ev.Applied, ev.Err)
+	}
+	if got := s.Interval("weather"); got != feeds.DefaultWeatherInterval {
+		t.Errorf("Scheduler weather interval after removing it = %v, want %v", got, feeds.DefaultWeatherInterval)
+	}
+
+	c.Intervals["bogus"] = Duration(time.Minute)
+	if err := c.Validate(); err == nil {
+		t.Error("Validate accepted an interval for an unknown feed")
+	}
+}
@@ -0,0 +1,297 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"reef-na/feeds"
+	"reef-na/health"
+)
+
+// defaultPollInterval is how often a Watcher checks its file for changes
+const defaultPollInterval = 2 * time.Second
+
+// Runtime is the running state a reloaded Config is applied to
+type Runtime struct {
+	Client    *feeds.Client
+	Registry  *feeds.Registry
+	Scheduler *feeds.Scheduler
+	// Checker, if set, probes the enabled feeds at the first location and
+	// gains or loses their probes as feeds are enabled or disabled
+	Checker *health.Checker
+}
+
+// Event reports a reload of the config file
+type Event struct {
+	// Config is the config in effect after the reload
+	Config *Config
+	// Applied lists the settings changed while running, e.g. "locations",
+	// "feeds", "intervals.alerts" or "apiKeys.eia"
+	Applied []string
+	// Restart lists changed settings that take effect on the next start:
+	// units, providers, providerTimeout, cacheTTL, digest, rules and notifiers
+	Restart []string
+	// Err is why the file was rejected, in which case the previous config
+	// stays in effect, or why applying part of it failed
+	Err error
+}
+
+// WatchOption configures a Watcher
+type WatchOption func(*Watcher)
+
+// WithPollInterval sets how often the file is checked for changes; the
+// default is 2s
+func WithPollInterval(d time.Duration) WatchOption {
+	return func(w *Watcher) {
+		if d > 0 {
+			w.poll = d
+		}
+	}
+}
+
+// WithReloadHook sets a function called with every reload's Event, from the
+// goroutine that reloaded. Reloads that change nothing are not reported.
+func WithReloadHook(fn func(Event)) WatchOption {
+	return func(w *Watcher) {
+		w.hook = fn
+	}
+}
+
+// WithLoader sets how the file is loaded, e.g. to apply command-line
+// overrides on top of Load; the default is Load of the Watcher's path
+func WithLoader(fn func() (*Config, error)) WatchOption {
+	return func(w *Watcher) {
+		w.load = fn
+	}
+}
+
+// Watcher reloads a config file when it changes and applies the changes to
+// a running Client, Registry, Scheduler and health Checker, so refresh
+// intervals, locations, enabled feeds and API keys change without a
+// restart. The file is polled, which also catches editors that replace it
+// and mounted ConfigMaps that swap a symlink.
+type Watcher struct {
+	path string
+	rt   Runtime
+	poll time.Duration
+	hook func(Event)
+	load func() (*Config, error)
+
+	mu      sync.Mutex
+	current *Config
+	stamp   fileStamp // of the file current was loaded from
+	lastErr string    // why the file last failed to load, if it did
+}
+
+// fileStamp identifies a version of a file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+	err     bool // the file could not be read
+}
+
+// NewWatcher creates a Watcher for the file at path, whose contents are
+// current and already applied to rt
+func NewWatcher(path string, current *Config, rt Runtime, options ...WatchOption) *Watcher {
+	w := &Watcher{path: path, rt: rt, poll: defaultPollInterval, current: current}
+	w.load = func() (*Config, error) { return Load(path) }
+	for _, opt := range options {
+		opt(w)
+	}
+	w.stamp = stat(path)
+	return w
+}
+
+// stat returns the current stamp of a file
+func stat(path string) fileStamp {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{err: true}
+	}
+	return fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+}
+
+// Config returns the config in effect
+func (w *Watcher) Config() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Run reloads the file whenever it changes until ctx is done, and returns
+// ctx's error. A file that fails to load is tried again at every poll until
+// it loads, in case it was read in the middle of being written; the same
+// failure is reported once.
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.poll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		w.mu.Lock()
+		if stamp := stat(w.path); stamp != w.stamp {
+			w.reload(stamp, true)
+		}
+		w.mu.Unlock()
+	}
+}
+
+// Reload loads the file now, such as on SIGHUP, and applies what changed.
+// An invalid file is rejected as a whole.
+func (w *Watcher) Reload() Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.reload(stat(w.path), false)
+}
+
+// reload loads the file, whose stamp before loading was stamp, and applies
+// it. The stamp is kept only once the file loads, so that a file rejected
+// mid-write is loaded again even if its final version has the same stamp.
+// When quiet, a failure like the last one is not reported again. Called
+// with w.mu held.
+func (w *Watcher) reload(stamp fileStamp, quiet bool) Event {
+	next, err := w.load()
+	if err != nil {
+		ev := Event{Config: w.current, Err: err}
+		if !quiet || err.Error() != w.lastErr {
+			w.emit(ev)
+		}
+		w.lastErr = err.Error()
+		return ev
+	}
+	w.stamp, w.lastErr = stamp, ""
+	ev := w.rt.apply(w.current, next)
+	w.current = next
+	if len(ev.Applied) > 0 || len(ev.Restart) > 0 || ev.Err != nil {
+		w.emit(ev)
+	}
+	return ev
+}
+
+// emit reports an event to the hook
+func (w *Watcher) emit(ev Event) {
+	if w.hook != nil {
+		w.hook(ev)
+	}
+}
+
+// apply makes the running state match next where it differs from old
+func (rt Runtime) apply(old, next *Config) Event {
+	ev := Event{Config: next}
+	var errs []error
+
+	if old.Units != next.Units {
+		ev.Restart = append(ev.Restart, "units")
+	}
+	if !slices.Equal(old.Providers, next.Providers) {
+		ev.Restart = append(ev.Restart, "providers")
+	}
+	if old.ProviderTimeout != next.ProviderTimeout {
+		ev.Restart = append(ev.Restart, "providerTimeout")
+	}
+	if old.CacheTTL != next.CacheTTL {
+		ev.Restart = append(ev.Restart, "cacheTTL")
+	}
+	if old.Digest != next.Digest {
+		ev.Restart = append(ev.Restart, "digest")
+	}
+	if !slices.Equal(old.Rules, next.Rules) {
+		ev.Restart = append(ev.Restart, "rules")
+	}
+	if !slices.EqualFunc(old.Notifiers, next.Notifiers, Notifier.equal) {
+		ev.Restart = append(ev.Restart, "notifiers")
+	}
+
+	if old.APIKeys.EIA != next.APIKeys.EIA {
+		rt.Client.SetEIAKey(next.APIKeys.EIA)
+		ev.Applied = append(ev.Applied, "apiKeys.eia")
+	}
+	if old.APIKeys.GooglePollen != next.APIKeys.GooglePollen {
+		rt.Client.SetGooglePollenKey(next.APIKeys.GooglePollen)
+		ev.Applied = append(ev.Applied, "apiKeys.googlePollen")
+	}
+
+	if oldFeeds, nextFeeds := old.enabledFeeds(), next.enabledFeeds(); !slices.Equal(oldFeeds, nextFeeds) {
+		for _, name := range oldFeeds {
+			if !slices.Contains(nextFeeds, name) {
+				rt.Registry.Unregister(name)
+				if rt.Checker != nil {
+					rt.Checker.Remove(name)
+				}
+			}
+		}
+		probeLocs, _ := next.FeedLocations() // an error is reported below
+		for _, f := range availableFeeds(rt.Client) {
+			if slices.Contains(nextFeeds, f.Name()) && !slices.Contains(oldFeeds, f.Name()) {
+				if err := rt.Registry.Register(f); err != nil {
+					errs = append(errs, fmt.Errorf("failed to enable %s feed: %w", f.Name(), err))
+					continue
+				}
+				if rt.Checker != nil && len(probeLocs) > 0 {
+					rt.Checker.Add(health.FeedProbe(f, probeLocs[0]))
+				}
+			}
+		}
+		ev.Applied = append(ev.Applied, "feeds")
+	}
+
+	names := slices.Sorted(maps.Keys(old.Intervals))
+	for name := range next.Intervals {
+		if _, ok := old.Intervals[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if d := next.Intervals[name]; d != old.Intervals[name] {
+			rt.Scheduler.SetFeedInterval(name, time.Duration(d))
+			ev.Applied = append(ev.Applied, "intervals."+name)
+		}
+	}
+
+	oldLocs, err := old.FeedLocations()
+	errs = append(errs, err)
+	nextLocs, err := next.FeedLocations()
+	errs = append(errs, err)
+	if !slices.Equal(oldLocs, nextLocs) {
+		for _, loc := range oldLocs {
+			if !slices.Contains(nextLocs, loc) {
+				rt.Scheduler.Untrack(loc)
+			}
+		}
+		added := &Config{}
+		for i, loc := range nextLocs {
+			if !slices.Contains(oldLocs, loc) {
+				added.Locations = append(added.Locations, next.Locations[i])
+			}
+		}
+		errs = append(errs, added.Track(rt.Scheduler))
+		ev.Applied = append(ev.Applied, "locations")
+	}
+
+	ev.Err = errors.Join(errs...)
+	return ev
+}
+
+// enabledFeeds returns the names of the feeds the config enables, in order
+func (c *Config) enabledFeeds() []string {
+	if len(c.Feeds) == 0 {
+		return feedNames
+	}
+	var names []string
+	for _, name := range feedNames {
+		if slices.Contains(c.Feeds, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"reef-na/feeds"
+	"reef-na/health"
+)
+
+// watch starts a Watcher polling the config file at path, and returns it
+// with the channel its events are sent to
+func watch(t *testing.T, path string) (*Watcher, <-chan Event) {
+	t.Helper()
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := feeds.NewClient()
+	rt := Runtime{Client: client, Registry: feeds.NewRegistry(), Scheduler: feeds.NewScheduler(client)}
+	if err := c.Track(rt.Scheduler); err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan Event, 8)
+	w := NewWatcher(path, c, rt, WithPollInterval(5*time.Millisecond), WithReloadHook(func(ev Event) { events <- ev }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return w, events
+}
+
+// rewrite replaces the file at path in one step, as editors do, with its
+// modification time set so the change is seen even on file systems with
+// coarse timestamps
+func rewrite(t *testing.T, path, contents string, modTime time.Time) {
+	t.Helper()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(tmp, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// nextEvent returns the next reload event, failing the test after a second
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("no reload event")
+		return Event{}
+	}
+}
+
+func TestWatcherReload(t *testing.T) {
+	path := writeConfig(t, "reefd.yaml", "locations:\n- country: US\nfeeds: [alerts]\nintervals:\n  alerts: 2m\n")
+	w, events := watch(t, path)
+	start := time.Now()
+
+	rewrite(t, path, `units: imperial
+locations:
+- country: US
+- country: CA
+feeds: [alerts]
+intervals:
+  alerts: 5m
+  weather: 1m
+apiKeys:
+  eia: secret
+`, start.Add(time.Second))
+	ev := nextEvent(t, events)
+	if ev.Err != nil {
+		t.Fatal(ev.Err)
+	}
+	if want := []string{"apiKeys.eia", "intervals.alerts", "intervals.weather", "locations"}; !slices.Equal(ev.Applied, want) {
+		t.Errorf("Applied = %v, want %v", ev.Applied, want)
+	}
+	if want := []string{"units"}; !slices.Equal(ev.Restart, want) {
+		t.Errorf("Restart = %v, want %v", ev.Restart, want)
+	}
+	if !w.rt.Scheduler.Tracked(feeds.Location{Country: "CA"}) {
+		t.Error("added location CA is not tracked")
+	}
+	if got := w.rt.Scheduler.Interval("weather"); got != time.Minute {
+		t.Errorf("weather interval = %v, want 1m", got)
+	}
+	if w.Config().Units != feeds.UnitsImperial {
+		t.Errorf("Config().Units = %q, want imperial", w.Config().Units)
+	}
+
+	rewrite(t, path, "locations:\n- country: US\nfeeds: [nonsense]\n", start.Add(2*time.Second))
+	ev = nextEvent(t, events)
+	if ev.Err == nil {
+		t.Fatal("invalid config was not rejected")
+	}
+	if ev.Config != w.Config() || w.Config().Units != feeds.UnitsImperial {
+		t.Error("rejected config replaced the one in effect")
+	}
+}
+
+func TestWatcherRetriesRejectedFile(t *testing.T) {
+	path := writeConfig(t, "reefd.yaml", "locations:\n- country: US\n")
+	w, events := watch(t, path)
+	modTime := time.Now().Add(time.Second)
+
+	// caught mid-write, then finished within the same modification time and
+	// at the same size
+	partial := "locations:\n- country: US\nunits: imperiak\n"
+	final := "locations:\n- country: US\nunits: imperial\n"
+	rewrite(t, path, partial, modTime)
+	if ev := nextEvent(t, events); ev.Err == nil {
+		t.Fatal("invalid config was not rejected")
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("rejection reported again: %v", ev.Err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if ev := w.Reload(); ev.Err == nil {
+		t.Error("Reload accepted the invalid config")
+	}
+	if ev := nextEvent(t, events); ev.Err == nil {
+		t.Error("Reload did not report the rejection")
+	}
+
+	rewrite(t, path, final, modTime)
+	ev := nextEvent(t, events)
+	if ev.Err != nil {
+		t.Fatal(ev.Err)
+	}
+	if want := []string{"units"}; !slices.Equal(ev.Restart, want) {
+		t.Errorf("Restart = %v, want %v", ev.Restart, want)
+	}
+	if w.Config().Units != feeds.UnitsImperial {
+		t.Errorf("Config().Units = %q, want imperial", w.Config().Units)
+	}
+}
+
+func TestApplySyncsHealthProbes(t *testing.T) {
+	c, err := Load(writeConfig(t, "reefd.yaml", "locations:\n- country: US\nfeeds: [alerts]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := feeds.NewClient()
+	registry, err := c.Registry(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var probes []health.Probe
+	for _, f := range registry.Feeds() {
+		probes = append(probes, health.FeedProbe(f, feeds.Location{Country: "US"}))
+	}
+	checker := health.NewChecker(health.WithProbes(probes...))
+	rt := Runtime{Client: client, Registry: registry, Scheduler: feeds.NewScheduler(client), Checker: checker}
+
+	next := *c
+	next.Feeds = []string{"forecast"}
+	if ev := rt.apply(c, &next); ev.Err != nil {
+		t.Fatal(ev.Err)
+	}
+	if _, ok := checker.Provider("alerts"); ok {
+		t.Error("disabled alerts feed is still probed")
+	}
+	if _, ok := checker.Provider("forecast"); !ok {
+		t.Error("enabled forecast feed is not probed")
+	}
+}
@@ -0,0 +1,319 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML parses the subset of YAML config files use: block mappings and
+// sequences, flow sequences of scalars, plain and quoted scalars and
+// comments. Mappings become map[string]any, sequences []any and scalars
+// string, float64, bool or nil.
+func parseYAML(data []byte) (any, error) {
+	lines, err := yamlLines(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return map[string]any{}, nil
+	}
+	p := &yamlParser{lines: lines}
+	v, err := p.block(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		return nil, l.errorf("unexpected %q", l.text)
+	}
+	return v, nil
+}
+
+// yamlLine is a non-blank line with its comment removed
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+func (l yamlLine) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", l.num, fmt.Sprintf(format, args...))
+}
+
+// yamlLines splits a document into lines, dropping blank lines, comments
+// and document markers
+func yamlLines(doc string) ([]yamlLine, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(doc, "\n") {
+		l := yamlLine{num: i + 1}
+		text := strings.TrimRight(stripComment(raw), " \t\r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, l.errorf("tabs are not allowed in indentation")
+		}
+		l.indent, l.text = len(text)-len(trimmed), trimmed
+		lines = append(lines, l)
+	}
+	return lines, nil
+}
+
+// stripComment removes a # comment that is outside quoted scalars
+func stripComment(s string) string {
+	var quote, prev byte // prev is the last non-space byte outside quotes
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' || c == '\'' && quote == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+				i++ // an escaped character or ''
+			} else if c == quote {
+				quote, prev = 0, c
+			}
+		case (c == '"' || c == '\'') && (prev == 0 || strings.IndexByte(":-[,", prev) >= 0):
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		case c != ' ' && c != '\t':
+			prev = c
+		}
+	}
+	return s
+}
+
+// yamlParser reads nested blocks from a document's lines
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// block parses the mapping or sequence starting at the current line
+func (p *yamlParser) block(indent int) (any, error) {
+	if isListItem(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+// mapping parses "key: value" lines at indent
+func (p *yamlParser) mapping(indent int) (map[string]any, error) {
+	m := make(map[string]any)
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, l.errorf("unexpected indentation")
+		}
+		if isListItem(l.text) {
+			return nil, l.errorf("list item where a key was expected")
+		}
+		key, rest, ok, err := splitKey(l.text)
+		if err != nil {
+			return nil, l.errorf("%v", err)
+		}
+		if !ok {
+			return nil, l.errorf("expected key: value, got %q", l.text)
+		}
+		if _, dup := m[key]; dup {
+			return nil, l.errorf("duplicate key %q", key)
+		}
+		p.pos++
+		if rest != "" {
+			if m[key], err = flowValue(rest); err != nil {
+				return nil, l.errorf("%v", err)
+			}
+			continue
+		}
+		m[key] = nil
+		if p.pos < len(p.lines) {
+			// A sequence may sit at the key's own indentation
+			if next := p.lines[p.pos]; next.indent > indent || next.indent == indent && isListItem(next.text) {
+				if m[key], err = p.block(next.indent); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+// sequence parses "- item" lines at indent
+func (p *yamlParser) sequence(indent int) ([]any, error) {
+	list := []any{}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent || l.indent == indent && !isListItem(l.text) {
+			break
+		}
+		if l.indent > indent {
+			return nil, l.errorf("unexpected indentation")
+		}
+		item := strings.TrimLeft(l.text[1:], " ")
+		if item == "" {
+			p.pos++
+			var v any
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				var err error
+				if v, err = p.block(p.lines[p.pos].indent); err != nil {
+					return nil, err
+				}
+			}
+			list = append(list, v)
+			continue
+		}
+		if _, _, isMap, _ := splitKey(item); isMap || isListItem(item) {
+			// The item is a block starting on the dash's line; parse it
+			// as if it began on its own line at the item's column
+			p.lines[p.pos] = yamlLine{num: l.num, indent: indent + len(l.text) - len(item), text: item}
+			v, err := p.block(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			continue
+		}
+		v, err := flowValue(item)
+		if err != nil {
+			return nil, l.errorf("%v", err)
+		}
+		list = append(list, v)
+		p.pos++
+	}
+	return list, nil
+}
+
+// isListItem reports whether a line is a sequence entry
+func isListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits "key: rest", reporting whether text is a mapping entry
+func splitKey(text string) (key, rest string, ok bool, err error) {
+	if text[0] == '"' || text[0] == '\'' {
+		end := closingQuote(text)
+		if end < 0 {
+			return "", "", false, fmt.Errorf("unterminated string %s", text)
+		}
+		after := text[end+1:]
+		if after != ":" && !strings.HasPrefix(after, ": ") {
+			return "", "", false, nil // a quoted scalar
+		}
+		v, err := scalar(text[:end+1])
+		if err != nil {
+			return "", "", false, err
+		}
+		return fmt.Sprint(v), strings.TrimSpace(after[1:]), true, nil
+	}
+	if text[0] == '[' || text[0] == '{' {
+		return "", "", false, nil
+	}
+	if i := strings.Index(text, ": "); i >= 0 {
+		return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+2:]), true, nil
+	}
+	if strings.HasSuffix(text, ":") {
+		return strings.TrimSpace(text[:len(text)-1]), "", true, nil
+	}
+	return "", "", false, nil
+}
+
+// closingQuote returns the index of the quote closing the string text
+// starts with, or -1
+func closingQuote(text string) int {
+	q := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case q == '"' && text[i] == '\\':
+			i++
+		case text[i] == q && q == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++ // '' is an escaped quote
+		case text[i] == q:
+			return i
+		}
+	}
+	return -1
+}
+
+// flowValue parses a scalar, a flow sequence of scalars or an empty mapping
+func flowValue(s string) (any, error) {
+	switch {
+	case s == "{}":
+		return map[string]any{}, nil
+	case s[0] == '{':
+		return nil, fmt.Errorf("flow mappings are not supported, use a block mapping")
+	case s[0] != '[':
+		return scalar(s)
+	}
+	if !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("unterminated list %s", s)
+	}
+	list := []any{}
+	inner := strings.TrimSpace(s[1 : len(s)-1])
+	for inner != "" {
+		end := strings.IndexByte(inner, ',')
+		if inner[0] == '"' || inner[0] == '\'' {
+			q := closingQuote(inner)
+			if q < 0 {
+				return nil, fmt.Errorf("unterminated string %s", inner)
+			}
+			end = strings.IndexByte(inner[q:], ',')
+			if end >= 0 {
+				end += q
+			}
+		}
+		item := inner
+		if end >= 0 {
+			item, inner = inner[:end], strings.TrimSpace(inner[end+1:])
+		} else {
+			inner = ""
+		}
+		item = strings.TrimSpace(item)
+		if item == "" || item[0] == '[' || item[0] == '{' {
+			return nil, fmt.Errorf("unsupported list item %q in %s", item, s)
+		}
+		v, err := scalar(item)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+// scalar parses a quoted or plain scalar
+func scalar(s string) (any, error) {
+	switch s[0] {
+	case '"':
+		if closingQuote(s) != len(s)-1 {
+			return nil, fmt.Errorf("invalid string %s", s)
+		}
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", s)
+		}
+		return v, nil
+	case '\'':
+		if closingQuote(s) != len(s)-1 {
+			return nil, fmt.Errorf("invalid string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case '&', '*', '!', '|', '>', '%', '@', '`':
+		return nil, fmt.Errorf("unsupported YAML syntax %q", s)
+	}
+	switch strings.ToLower(s) {
+	case "null", "~":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	// Not inf, nan or hex floats, which YAML reads as strings
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !strings.ContainsAny(strings.ToLower(s), "xpn_") {
+		return f, nil
+	}
+	return s, nil
+}
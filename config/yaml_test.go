@@ -0,0 +1,150 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want any
+	}{
+		{"empty", "", map[string]any{}},
+		{"comments only", "# nothing\n---\n   # here\n", map[string]any{}},
+		{
+			"scalars",
+			"s: plain text\nn: 12.5\nneg: -3\nt: true\nf: False\nnull: ~\nempty:\nhex: 0x1F\ninf: .inf\nv: 1.2.3",
+			map[string]any{
+				"s": "plain text", "n": 12.5, "neg": -3.0, "t": true, "f": false, "null": nil, "empty": nil,
+				"hex": "0x1F", "inf": ".inf", "v": "1.2.3",
+			},
+		},
+		{
+			"comments and quoted #",
+			`a: "x # not a comment" # a comment` + "\n" +
+				`b: 'y # nor this'` + "\n" +
+				"c: z#w # only this\n" +
+				`d: "escaped \" # quote"` + "\n" +
+				"# whole line\n" +
+				"e: 1 #trailing",
+			map[string]any{"a": "x # not a comment", "b": "y # nor this", "c": "z#w", "d": `escaped " # quote`, "e": 1.0},
+		},
+		{
+			"single-quote escapes",
+			"a: 'it''s'\nb: '''quoted'''\nc: 'a '' # b' # comment\n'key''s': v",
+			map[string]any{"a": "it's", "b": "'quoted'", "c": "a ' # b", "key's": "v"},
+		},
+		{
+			"quoted keys and values",
+			`"a b": "tab\tand\nnewline"` + "\n'c: d': ':'",
+			map[string]any{"a b": "tab\tand\nnewline", "c: d": ":"},
+		},
+		{
+			"nested mappings",
+			"digest:\n  schedule: \"0 7 * * *\"\n  feeds:\n    alerts: 2m\nunits: metric",
+			map[string]any{
+				"digest": map[string]any{"schedule": "0 7 * * *", "feeds": map[string]any{"alerts": "2m"}},
+				"units":  "metric",
+			},
+		},
+		{
+			"indented sequence",
+			"providers:\n  - nws\n  - open-meteo",
+			map[string]any{"providers": []any{"nws", "open-meteo"}},
+		},
+		{
+			"sequence at the key's indentation",
+			"locations:\n- country: US\n- country: CA\n  lat: 43.65\nfeeds:\n- alerts\nunits: imperial",
+			map[string]any{
+				"locations": []any{
+					map[string]any{"country": "US"},
+					map[string]any{"country": "CA", "lat": 43.65},
+				},
+				"feeds": []any{"alerts"},
+				"units": "imperial",
+			},
+		},
+		{
+			"items on their own line",
+			"list:\n  -\n    a: 1\n  -\n  - - x\n    - y",
+			map[string]any{"list": []any{map[string]any{"a": 1.0}, nil, []any{"x", "y"}}},
+		},
+		{
+			"flow lists",
+			`a: [x, "y, z", 'w''s', 3, true, null]` + "\nb: []\nc: [ spaced ]\nd: {}",
+			map[string]any{
+				"a": []any{"x", "y, z", "w's", 3.0, true, nil},
+				"b": []any{},
+				"c": []any{"spaced"},
+				"d": map[string]any{},
+			},
+		},
+		{"top-level sequence", "- a\n- b", []any{"a", "b"}},
+		{"document marker", "---\na: 1\n", map[string]any{"a": 1.0}},
+		{"CRLF line endings", "a: 1\r\nb:\r\n  - x\r\n", map[string]any{"a": 1.0, "b": []any{"x"}}},
+	}
+	for _, tt := range tests {
+		got, err := parseYAML([]byte(tt.doc))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseYAML(%q) = %#v, want %#v", tt.name, tt.doc, got, tt.want)
+		}
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name, doc, err string
+	}{
+		{"duplicate key", "a: 1\nb: 2\na: 3", `line 3: duplicate key "a"`},
+		{"duplicate nested key", "d:\n  x: 1\n  x: 2", `line 3: duplicate key "x"`},
+		{"anchor", "a: &base 1", "unsupported YAML syntax"},
+		{"alias", "a: 1\nb: *a", "line 2: unsupported YAML syntax"},
+		{"tag", "a: !!str 1", "unsupported YAML syntax"},
+		{"block scalar", "a: |\n  text", "unsupported YAML syntax"},
+		{"anchor in a flow list", "a: [x, &y z]", "unsupported YAML syntax"},
+		{"nested flow list", "a: [x, [y]]", "unsupported list item"},
+		{"flow mapping", "a: {b: 1}", "flow mappings are not supported"},
+		{"unterminated flow list", "a: [x, y", "unterminated list"},
+		{"unterminated string", `a: "x`, "invalid string"},
+		{"unterminated quoted key", `"a: 1`, "unterminated string"},
+		{"text after a string", `a: "x" y`, "invalid string"},
+		{"tab indentation", "a:\n\tb: 1", "line 2: tabs are not allowed"},
+		{"bad indentation", "a: 1\n  b: 2", "line 2: unexpected indentation"},
+		{"list item in a mapping", "a: 1\n- b", "line 2: list item where a key was expected"},
+		{"not a mapping entry", "a: 1\nplain", `line 2: expected key: value, got "plain"`},
+		{"dedent past the document", "  a: 1\nb: 2", `line 2: unexpected "b: 2"`},
+	}
+	for _, tt := range tests {
+		_, err := parseYAML([]byte(tt.doc))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: parseYAML(%q) error = %v, want %q", tt.name, tt.doc, err, tt.err)
+		}
+	}
+}
+
+func TestStripComment(t *testing.T) {
+	tests := map[string]string{
+		"a: 1 # c":          "a: 1 ",
+		"# c":               "",
+		"a: b#c":            "a: b#c",
+		`a: "b # c"`:        `a: "b # c"`,
+		`a: "b \" # c" # d`: `a: "b \" # c" `,
+		"a: 'b '' # c' # d": "a: 'b '' # c' ",
+		"a: it's # c":       "a: it's ",
+		"- 'x' # c":         "- 'x' ",
+		`a: [x, "y # z"]`:   `a: [x, "y # z"]`,
+		"a:\t# c":           "a:\t",
+	}
+	for in, want := range tests {
+		if got := stripComment(in); got != want {
+			t.Errorf("stripComment(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
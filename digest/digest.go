@@ -0,0 +1,254 @@
+// Package digest composes a morning summary of a location — today's
+// weather and forecast, air quality, alerts, holidays and headlines — into a
+// single document, rendered as JSON, text or HTML, on demand or on a
+// cron-like schedule.
+package digest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"reef-na/feeds"
+)
+
+// Defaults for a Generator
+const (
+	defaultHeadlineLimit = 5
+	defaultSchedule      = "0 7 * * *"
+)
+
+// Digest is a summary of the day at one location
+type Digest struct {
+	Country   string    `json:"country,omitempty"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Timezone  string    `json:"timezone"`
+	Date      time.Time `json:"date"` // midnight of the day summarized, in Timezone
+
+	Weather    *feeds.WeatherData    `json:"weather,omitempty"`
+	Today      *feeds.DailyForecast  `json:"today,omitempty"`
+	Sun        *feeds.SunTimes       `json:"sun,omitempty"`
+	AirQuality *feeds.AirQualityData `json:"airQuality,omitempty"`
+	Alerts     []feeds.WeatherAlert  `json:"alerts"`
+	Holidays   []feeds.Holiday       `json:"holidays"` // today and the rest of this week
+	Headlines  []feeds.Headline      `json:"headlines"`
+
+	// Errors maps the sections that could not be fetched to why
+	Errors      map[string]string `json:"errors,omitempty"`
+	GeneratedAt time.Time         `json:"generatedAt"`
+}
+
+// Imperial reports whether the digest's weather carries imperial units
+func (d *Digest) Imperial() bool {
+	return d.Weather != nil && d.Weather.Imperial != nil
+}
+
+// Compose builds a Digest from a snapshot holding the "forecast", "sun",
+// "airQuality", "alerts", "holidays" and "news" feeds; missing feeds leave
+// their sections empty
+func Compose(snap *feeds.Snapshot) *Digest {
+	zone := time.UTC
+	if loc, err := time.LoadLocation(snap.Timezone); err == nil && snap.Timezone != "" {
+		zone = loc
+	}
+	generated := snap.FetchedAt.In(zone)
+	y, m, day := generated.Date()
+	d := &Digest{
+		Country:     snap.Country,
+		Latitude:    snap.Latitude,
+		Longitude:   snap.Longitude,
+		Timezone:    zone.String(),
+		Date:        time.Date(y, m, day, 0, 0, 0, 0, zone),
+		Weather:     snap.Weather,
+		Errors:      snap.Errors,
+		GeneratedAt: generated,
+		Alerts:      []feeds.WeatherAlert{},
+		Holidays:    []feeds.Holiday{},
+		Headlines:   []feeds.Headline{},
+	}
+	if f, ok := snap.Feeds["forecast"].(*feeds.ForecastData); ok && len(f.Days) > 0 {
+		d.Today = &f.Days[0]
+	}
+	d.Sun, _ = snap.Feeds["sun"].(*feeds.SunTimes)
+	d.AirQuality, _ = snap.Feeds["airQuality"].(*feeds.AirQualityData)
+	if alerts, ok := snap.Feeds["alerts"].([]feeds.WeatherAlert); ok {
+		d.Alerts = alerts
+	}
+	if holidays, ok := snap.Feeds["holidays"].([]feeds.Holiday); ok {
+		for _, h := range holidays {
+			if h.Today || h.ThisWeek {
+				d.Holidays = append(d.Holidays, h)
+			}
+		}
+	}
+	if headlines, ok := snap.Feeds["news"].([]feeds.Headline); ok {
+		d.Headlines = headlines
+	}
+	return d
+}
+
+// Option configures a Generator
+type Option func(*Generator)
+
+// WithSchedule sets when Run generates digests, in each location's own time
+// zone; the default is 07:00 every day
+func WithSchedule(s Schedule) Option {
+	return func(g *Generator) {
+		g.schedule = s
+	}
+}
+
+// WithLocations sets the locations Run generates digests for
+func WithLocations(locs ...feeds.Location) Option {
+	return func(g *Generator) {
+		g.locations = slices.Clone(locs)
+	}
+}
+
+// WithHeadlineLimit sets how many headlines a digest carries; the default is 5
+func WithHeadlineLimit(n int) Option {
+	return func(g *Generator) {
+		if n > 0 {
+			g.headlines = n
+		}
+	}
+}
+
+// WithOnDigest sets a function called with every digest Run generates
+func WithOnDigest(fn func(*Digest)) Option {
+	return func(g *Generator) {
+		g.onDigest = fn
+	}
+}
+
+// Generator fetches the feeds a digest needs and composes digests, either
+// on demand with Generate or on a schedule with Run
+type Generator struct {
+	client    *feeds.Client
+	agg       *feeds.Aggregator
+	schedule  Schedule
+	locations []feeds.Location
+	headlines int
+	onDigest  func(*Digest)
+
+	mu     sync.RWMutex
+	latest map[string]*Digest
+}
+
+// NewGenerator creates a Generator fetching through c (the default Client if nil)
+func NewGenerator(c *feeds.Client, options ...Option) *Generator {
+	g := &Generator{
+		client:    c,
+		schedule:  MustParseSchedule(defaultSchedule),
+		headlines: defaultHeadlineLimit,
+		latest:    make(map[string]*Digest),
+	}
+	for _, opt := range options {
+		opt(g)
+	}
+
+	r := feeds.NewRegistry()
+	for _, f := range append(feeds.DefaultFeeds(c), feeds.ForecastFeed(c, 1), feeds.NewsFeed(c, g.headlines)) {
+		_ = r.Register(f) // the names are distinct
+	}
+	g.agg = feeds.NewAggregator(c)
+	g.agg.UseRegistry(r)
+	return g
+}
+
+// Generate fetches and composes a digest for loc now. A location with a
+// country and no coordinates uses the country's registered coordinates.
+// Sections that fail are recorded in Digest.Errors.
+func (g *Generator) Generate(ctx context.Context, loc feeds.Location) (*Digest, error) {
+	var snap *feeds.Snapshot
+	var err error
+	if loc.Lat == 0 && loc.Lon == 0 && loc.Country != "" {
+		snap, err = g.agg.FetchCountry(ctx, loc.Country)
+	} else {
+		snap, err = g.agg.Fetch(ctx, loc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	d := Compose(snap)
+	g.mu.Lock()
+	g.latest[key(loc)] = d
+	g.mu.Unlock()
+	return d, nil
+}
+
+// Latest returns the last digest generated for loc
+func (g *Generator) Latest(loc feeds.Location) (*Digest, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	d, ok := g.latest[key(loc)]
+	return d, ok
+}
+
+// key identifies a location like the Scheduler does: by country when set
+func key(loc feeds.Location) string {
+	if loc.Country != "" {
+		return strings.ToUpper(loc.Country)
+	}
+	return fmt.Sprintf("%.4f,%.4f", loc.Lat, loc.Lon)
+}
+
+// Run generates a digest for every location each time the schedule fires
+// in that location's time zone, until ctx is done, and returns ctx's error
+func (g *Generator) Run(ctx context.Context) error {
+	type pending struct {
+		loc  feeds.Location
+		zone *time.Location
+		next time.Time
+	}
+	var all []*pending
+	for _, loc := range g.locations {
+		coords := loc.Coordinates
+		if coords.Lat == 0 && coords.Lon == 0 && loc.Country != "" {
+			if l, err := feeds.CountryLocation(loc.Country); err == nil {
+				coords = l.Coordinates
+			}
+		}
+		zone := feeds.TimezoneAt(coords)
+		all = append(all, &pending{loc: loc, zone: zone, next: g.schedule.Next(time.Now().In(zone))})
+	}
+
+	for {
+		var wake time.Time
+		for _, p := range all {
+			if !p.next.IsZero() && (wake.IsZero() || p.next.Before(wake)) {
+				wake = p.next
+			}
+		}
+		if wake.IsZero() {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		timer := time.NewTimer(time.Until(wake))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		for _, p := range all {
+			if p.next.IsZero() || p.next.After(now) {
+				continue
+			}
+			p.next = g.schedule.Next(now.In(p.zone))
+			d, err := g.Generate(ctx, p.loc)
+			if err != nil {
+				continue // only invalid locations fail, and they stay invalid
+			}
+			if g.onDigest != nil {
+				g.onDigest(d)
+			}
+		}
+	}
+}
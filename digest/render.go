@@ -0,0 +1,146 @@
+package digest
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+
+	"reef-na/feeds"
+)
+
+// Formats a digest can be rendered in
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+	FormatHTML = "html"
+)
+
+// textTemplate renders a digest as plain text, such as for an email body
+const textTemplate = `{{with .Country}}{{.}} {{end}}digest for {{.Date.Format "Monday, January 2"}}
+{{with .Weather}}
+Now: {{.Summary}}, {{temp .TemperatureC}} (feels like {{temp .FeelsLikeC}})
+{{- end}}
+{{- with .Today}}
+Today: {{.Summary}}, high {{temp .HighC}}, low {{temp .LowC}}
+{{- with .PrecipitationProbability}}, {{.}}% chance of precipitation{{end}}
+{{- end}}
+{{- with .Sun}}
+Sunrise {{.Sunrise.Format "15:04"}}, sunset {{.Sunset.Format "15:04"}}
+{{- end}}
+{{- with .AirQuality}}
+Air quality: {{.Category}} (AQI {{.AQI}})
+{{- end}}
+{{if .Alerts}}
+Alerts:
+{{- range .Alerts}}
+  - {{.Event}} ({{.Severity}}){{with .Headline}}: {{.}}{{end}}
+{{- end}}
+{{else}}
+No weather alerts.
+{{end}}
+{{- if .Holidays}}
+Holidays:
+{{- range .Holidays}}
+  - {{.Name}}, {{.Date.Format "Monday, January 2"}}
+{{- end}}
+{{end}}
+{{- if .Headlines}}
+Headlines:
+{{- range .Headlines}}
+  - {{.Title}}{{with .Source}} ({{.}}){{end}}
+{{- end}}
+{{end}}
+{{- with .Errors}}
+Unavailable: {{sections .}}
+{{end -}}
+`
+
+// htmlTemplate renders a digest as an HTML fragment, such as for an email
+const htmlTemplate = `<div class="reef-digest">
+<h2>{{with .Country}}{{.}} {{end}}digest for {{.Date.Format "Monday, January 2"}}</h2>
+<ul>
+{{- with .Weather}}
+<li>Now: {{.Summary}}, {{temp .TemperatureC}} (feels like {{temp .FeelsLikeC}})</li>
+{{- end}}
+{{- with .Today}}
+<li>Today: {{.Summary}}, high {{temp .HighC}}, low {{temp .LowC}}{{with .PrecipitationProbability}}, {{.}}% chance of precipitation{{end}}</li>
+{{- end}}
+{{- with .Sun}}
+<li>Sunrise {{.Sunrise.Format "15:04"}}, sunset {{.Sunset.Format "15:04"}}</li>
+{{- end}}
+{{- with .AirQuality}}
+<li>Air quality: {{.Category}} (AQI {{.AQI}})</li>
+{{- end}}
+</ul>
+{{- if .Alerts}}
+<h3>Alerts</h3>
+<ul>
+{{- range .Alerts}}
+<li><strong>{{.Event}}</strong> ({{.Severity}}){{with .Headline}}: {{.}}{{end}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>No weather alerts.</p>
+{{- end}}
+{{- if .Holidays}}
+<h3>Holidays</h3>
+<ul>
+{{- range .Holidays}}
+<li>{{.Name}}, {{.Date.Format "Monday, January 2"}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Headlines}}
+<h3>Headlines</h3>
+<ul>
+{{- range .Headlines}}
+<li>{{if .URL}}<a href="{{.URL}}">{{.Title}}</a>{{else}}{{.Title}}{{end}}{{with .Source}} ({{.}}){{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- with .Errors}}
+<p><small>Unavailable: {{sections .}}</small></p>
+{{- end}}
+</div>
+`
+
+// funcs returns the template functions for rendering d
+func (d *Digest) funcs() map[string]any {
+	return map[string]any{
+		"temp": func(c float64) string {
+			if d.Imperial() {
+				return fmt.Sprintf("%.0f°F", feeds.CelsiusToFahrenheit(c))
+			}
+			return fmt.Sprintf("%.0f°C", c)
+		},
+		"sections": func(errs map[string]string) string {
+			names := make([]string, 0, len(errs))
+			for name := range errs {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			return strings.Join(names, ", ")
+		},
+	}
+}
+
+// WriteText renders the digest as plain text
+func (d *Digest) WriteText(w io.Writer) error {
+	t, err := texttemplate.New("digest").Funcs(d.funcs()).Parse(textTemplate)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, d)
+}
+
+// WriteHTML renders the digest as an HTML fragment
+func (d *Digest) WriteHTML(w io.Writer) error {
+	t, err := htmltemplate.New("digest").Funcs(d.funcs()).Parse(htmlTemplate)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, d)
+}
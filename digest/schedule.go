@@ -0,0 +1,204 @@
+package digest
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a cron expression of five fields — minute, hour, day of month,
+// month and day of week — such as "0 7 * * *" for 07:00 every day or
+// "30 6 * * mon-fri" for 06:30 on weekdays. Fields take *, numbers, names
+// of months and weekdays, ranges, lists and steps ("*/15", "1-5/2"). As in
+// cron, when both day fields are restricted a day matching either is used.
+// The descriptors @hourly, @daily, @weekly, @monthly and @yearly are
+// accepted too.
+type Schedule struct {
+	spec                     string
+	minute, hour, dom, month uint64 // bit n set when n matches
+	dow                      uint64
+	domAny, dowAny           bool
+}
+
+// scheduleField is the range of one field and its names
+type scheduleField struct {
+	name     string
+	min, max int
+	names    []string // names[i] stands for min+i
+}
+
+var scheduleFields = [5]scheduleField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+var scheduleDescriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// ParseSchedule parses a cron expression
+func ParseSchedule(spec string) (Schedule, error) {
+	s := Schedule{spec: strings.TrimSpace(spec)}
+	expr := s.spec
+	if d, ok := scheduleDescriptors[strings.ToLower(expr)]; ok {
+		expr = d
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(scheduleFields) {
+		return Schedule{}, fmt.Errorf("invalid schedule %q: want 5 fields (minute hour day-of-month month day-of-week)", spec)
+	}
+	sets := [5]*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, f := range fields {
+		set, err := scheduleFields[i].parse(f)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		*sets[i] = set
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 // 7 is Sunday too
+	}
+	s.domAny, s.dowAny = fields[2] == "*", fields[4] == "*"
+	return s, nil
+}
+
+// MustParseSchedule is ParseSchedule for expressions known to be valid; it
+// panics on an error
+func MustParseSchedule(spec string) Schedule {
+	s, err := ParseSchedule(spec)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// parse parses one field into a bit set
+func (f scheduleField) parse(field string) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if r, s, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("%s: invalid step %q", f.name, s)
+			}
+			rng, step = r, n
+		}
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(a); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(b); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				hi = f.max // "5/10" means from 5 on
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("%s: range %q is backwards", f.name, rng)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// value parses a number or name within the field's range
+func (f scheduleField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s: %q is not between %d and %d", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// String returns the expression the Schedule was parsed from
+func (s Schedule) String() string {
+	return s.spec
+}
+
+// IsZero reports whether s is the zero Schedule, which never fires
+func (s Schedule) IsZero() bool {
+	return s.minute == 0
+}
+
+// Next returns the first time after t that matches the schedule, in t's
+// location, or the zero time for the zero Schedule or one that cannot match
+// within five years, such as February 30. Wall times that do not exist
+// because of a daylight saving change are skipped.
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.IsZero() {
+		return time.Time{}
+	}
+	loc := t.Location()
+	t = t.Truncate(time.Second).Add(time.Minute)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		var next time.Time
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			// Jump straight to the next matching minute of the hour
+			if later := s.minute >> uint(t.Minute()); later != 0 {
+				next = t.Add(time.Duration(bits.TrailingZeros64(later)) * time.Minute)
+			} else {
+				next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			}
+		default:
+			return t
+		}
+		if !next.After(t) {
+			// The wall time does not exist on a daylight saving change, and
+			// time.Date moved it back; step from the start of t's hour over
+			// the gap instead
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(time.Hour)
+		}
+		t = next
+	}
+	return time.Time{}
+}
+
+// dayMatches reports whether t's day matches the day-of-month and
+// day-of-week fields
+func (s Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	}
+	return dom || dow
+}
@@ -0,0 +1,74 @@
+package digest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	utc := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"daily", "0 7 * * *", utc(10, 15, 7, 0), utc(10, 16, 7, 0)},
+		{"seconds are dropped", "* * * * *", utc(10, 15, 7, 0).Add(59 * time.Second), utc(10, 15, 7, 1)},
+		{"step from a start", "5/10 * * * *", utc(10, 15, 10, 0), utc(10, 15, 10, 5)},
+		{"step from a start, next match", "5/10 * * * *", utc(10, 15, 10, 5), utc(10, 15, 10, 15)},
+		{"step from a start, next hour", "5/10 * * * *", utc(10, 15, 10, 55), utc(10, 15, 11, 5)},
+		{"7 is Sunday", "0 8 * * 7", utc(10, 15, 0, 0), utc(10, 18, 8, 0)},
+		{"0 is Sunday", "0 8 * * 0", utc(10, 15, 0, 0), utc(10, 18, 8, 0)},
+		{"weekday names", "30 6 * * mon-fri", utc(10, 16, 7, 0), utc(10, 19, 6, 30)},
+		{"day of month only", "0 9 13 * *", utc(10, 15, 0, 0), utc(11, 13, 9, 0)},
+		{"day of month or of week, weekday first", "0 9 13 * fri", utc(10, 15, 0, 0), utc(10, 16, 9, 0)},
+		{"day of month or of week, date first", "0 9 13 * fri", utc(10, 10, 0, 0), utc(10, 13, 9, 0)},
+		{"leap day", "0 0 29 feb *", utc(10, 15, 0, 0), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"no match within five years", "0 0 30 feb *", utc(10, 15, 0, 0), time.Time{}},
+		{"descriptor", "@monthly", utc(10, 15, 0, 0), utc(11, 1, 0, 0)},
+
+		// New York springs forward from 02:00 EST to 03:00 EDT on 8 March 2026
+		{"hour after the gap", "0 3 * * *", time.Date(2026, 3, 8, 1, 30, 0, 0, ny), time.Date(2026, 3, 8, 3, 0, 0, 0, ny)},
+		{"steps across the gap", "*/15 * * * *", time.Date(2026, 3, 8, 1, 50, 0, 0, ny), time.Date(2026, 3, 8, 3, 0, 0, 0, ny)},
+		{"minute after the gap", "30 * * * *", time.Date(2026, 3, 8, 1, 45, 0, 0, ny), time.Date(2026, 3, 8, 3, 30, 0, 0, ny)},
+		{"wall time in the gap is skipped", "30 2 * * *", time.Date(2026, 3, 8, 0, 0, 0, 0, ny), time.Date(2026, 3, 9, 2, 30, 0, 0, ny)},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := s.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%s: %q.Next(%v) = %v, want %v", tt.name, tt.spec, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * smarch *",
+		"@fortnightly",
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) accepted an invalid schedule", spec)
+		}
+	}
+}
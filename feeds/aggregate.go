@@ -0,0 +1,94 @@
+package feeds
+
+import "math"
+
+// AggregateWeather combines several readings into one summary. Numeric
+// fields are weighted averages, with wind direction averaged as a vector.
+// The weather code is the one with the greatest total weight, ties going to
+// the lower code, and the summary and icon follow from it. The icon is the
+// night variant when readings with night icons carry most of the weight.
+// Locations missing from weights count with weight 1. Nil readings and
+// non-positive weights are ignored, as is pressure in readings that do not
+// report it. Location fields are left empty.
+func AggregateWeather(results map[string]*WeatherData, weights map[string]float64) WeatherData {
+	var total float64
+	var sum WeatherData
+	var windX, windY float64
+	var pressureTotal float64
+	var nightWeight float64
+	codeWeights := make(map[int]float64)
+
+	for k, w := range results {
+		if w == nil {
+			continue
+		}
+		weight, ok := weights[k]
+		if !ok {
+			weight = 1
+		}
+		if weight <= 0 {
+			continue
+		}
+		total += weight
+		sum.TemperatureC += w.TemperatureC * weight
+		sum.FeelsLikeC += w.FeelsLikeC * weight
+		sum.RainMM += w.RainMM * weight
+		sum.SnowfallCM += w.SnowfallCM * weight
+		sum.PrecipitationMM += w.PrecipitationMM * weight
+		sum.HumidityPct += w.HumidityPct * weight
+		sum.CloudCoverPct += w.CloudCoverPct * weight
+		sum.WindSpeedKmh += w.WindSpeedKmh * weight
+		sum.WindGustsKmh += w.WindGustsKmh * weight
+		if w.PressureHPa > 0 {
+			sum.PressureHPa += w.PressureHPa * weight
+			pressureTotal += weight
+		}
+		rad := w.WindDirectionDeg * math.Pi / 180
+		windX += math.Sin(rad) * weight
+		windY += math.Cos(rad) * weight
+		codeWeights[w.WeatherCode] += weight
+		if isNightIcon(w.Icon) {
+			nightWeight += weight
+		}
+	}
+
+	if total == 0 {
+		return WeatherData{}
+	}
+
+	code, best := 0, -1.0
+	for c, cw := range codeWeights {
+		if cw > best || (cw == best && c < code) {
+			code, best = c, cw
+		}
+	}
+
+	direction := math.Atan2(windX, windY) * 180 / math.Pi
+	if direction < 0 {
+		direction += 360
+	}
+
+	var pressure float64
+	if pressureTotal > 0 {
+		pressure = sum.PressureHPa / pressureTotal
+	}
+
+	w := WeatherData{
+		Summary:          describeWeatherCode(code),
+		WeatherCode:      code,
+		TemperatureC:     sum.TemperatureC / total,
+		FeelsLikeC:       sum.FeelsLikeC / total,
+		RainMM:           sum.RainMM / total,
+		SnowfallCM:       sum.SnowfallCM / total,
+		PrecipitationMM:  sum.PrecipitationMM / total,
+		HumidityPct:      sum.HumidityPct / total,
+		CloudCoverPct:    sum.CloudCoverPct / total,
+		WindSpeedKmh:     sum.WindSpeedKmh / total,
+		WindDirectionDeg: direction,
+		WindGustsKmh:     sum.WindGustsKmh / total,
+		PressureHPa:      pressure,
+	}
+	w.ConditionSeverity = ClassifyConditions(w.WeatherCode, w.PrecipitationMM)
+	w.Icon = WeatherIcon(code, nightWeight*2 <= total)
+	return w
+}
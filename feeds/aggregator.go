@@ -0,0 +1,227 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultFeedTimeout bounds each feed of an aggregated snapshot unless overridden
+const defaultFeedTimeout = 5 * time.Second
+
+// Location identifies where a snapshot is fetched for. Country is empty for
+// bare coordinates; feeds that need it (such as alerts) then fail.
+type Location struct {
+	Country string
+	Coordinates
+}
+
+// FeedFunc fetches one feed for a location. The context carries the feed's timeout.
+type FeedFunc func(ctx context.Context, loc Location) (any, error)
+
+// Snapshot is the combined result of every feed for one location. Feeds that
+// failed or timed out are missing from Feeds and listed in Errors instead.
+type Snapshot struct {
+	Country   string            `json:"country,omitempty"`
+	Latitude  float64           `json:"latitude"`
+	Longitude float64           `json:"longitude"`
+	Timezone  string            `json:"timezone,omitempty"` // IANA zone of the location
+	Weather   *WeatherData      `json:"weather,omitempty"`
+	Trends    []Trend           `json:"trends,omitempty"` // from the Scheduler's recent weather history
+	Feeds     map[string]any    `json:"feeds"`
+	Errors    map[string]string `json:"errors,omitempty"`
+	FetchedAt time.Time         `json:"fetchedAt"` // in the location's time zone
+}
+
+// aggregatorFeed is a registered feed
+type aggregatorFeed struct {
+	fn      FeedFunc
+	timeout time.Duration
+}
+
+// Aggregator fetches current weather plus any registered feeds for a location
+// concurrently and combines them into one Snapshot
+type Aggregator struct {
+	client *Client
+
+	mu       sync.RWMutex
+	feeds    map[string]aggregatorFeed
+	registry *Registry
+}
+
+// NewAggregator creates an Aggregator that fetches weather through c (the
+// default Client if nil), plus the feeds in DefaultRegistry. Other feeds are
+// added with Register or RegisterDefaults.
+func NewAggregator(c *Client) *Aggregator {
+	if c == nil {
+		c = defaultClient
+	}
+	return &Aggregator{client: c, feeds: make(map[string]aggregatorFeed), registry: DefaultRegistry}
+}
+
+// UseRegistry replaces the Registry whose feeds are fetched alongside the
+// Aggregator's own; nil fetches only its own feeds. Feeds added with
+// Register take precedence over registry feeds of the same name.
+func (a *Aggregator) UseRegistry(r *Registry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.registry = r
+}
+
+// Register adds or replaces a named feed. A zero timeout uses the default of 5s.
+func (a *Aggregator) Register(name string, timeout time.Duration, fn FeedFunc) {
+	if timeout <= 0 {
+		timeout = defaultFeedTimeout
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.feeds[name] = aggregatorFeed{fn: fn, timeout: timeout}
+}
+
+// RegisterFeed adds or replaces a Feed, using its timeout if it implements
+// FeedTimeouter
+func (a *Aggregator) RegisterFeed(f Feed) {
+	var timeout time.Duration
+	if t, ok := f.(FeedTimeouter); ok {
+		timeout = t.Timeout()
+	}
+	a.Register(f.Name(), timeout, f.Fetch)
+}
+
+// RegisterDefaults registers the package's own location feeds (see
+// DefaultFeeds), each fetched through the Aggregator's Client
+func (a *Aggregator) RegisterDefaults() {
+	for _, f := range DefaultFeeds(a.client) {
+		a.RegisterFeed(f)
+	}
+}
+
+// FetchCountry fetches a snapshot at a country's coordinates
+func (a *Aggregator) FetchCountry(ctx context.Context, country string) (*Snapshot, error) {
+	coords, source, err := a.client.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+	if source == SourceFallback {
+		country = "US" // the fallback location is New York
+	}
+	return a.fetch(ctx, Location{Country: country, Coordinates: coords}, source), nil
+}
+
+// Fetch fetches a snapshot for a location. Weather and every registered feed
+// run concurrently, each under its own timeout; failures are recorded in
+// Snapshot.Errors and never fail the whole snapshot.
+func (a *Aggregator) Fetch(ctx context.Context, loc Location) (*Snapshot, error) {
+	if err := loc.Validate(); err != nil {
+		return nil, err
+	}
+	return a.fetch(ctx, loc, SourceExplicit), nil
+}
+
+// fetch runs weather and the registered feeds for loc
+func (a *Aggregator) fetch(ctx context.Context, loc Location, source string) *Snapshot {
+	a.mu.RLock()
+	feeds := make(map[string]aggregatorFeed, len(a.feeds)+1)
+	if a.registry != nil {
+		for _, f := range a.registry.Feeds() {
+			timeout := defaultFeedTimeout
+			if t, ok := f.(FeedTimeouter); ok && t.Timeout() > 0 {
+				timeout = t.Timeout()
+			}
+			feeds[f.Name()] = aggregatorFeed{fn: f.Fetch, timeout: timeout}
+		}
+	}
+	for name, f := range a.feeds {
+		feeds[name] = f
+	}
+	a.mu.RUnlock()
+	feeds["weather"] = aggregatorFeed{
+		fn: func(ctx context.Context, loc Location) (any, error) {
+			return a.client.fetchCurrent(ctx, loc.Coordinates, source)
+		},
+		timeout: defaultFeedTimeout,
+	}
+
+	snap := &Snapshot{
+		Country:   loc.Country,
+		Latitude:  loc.Lat,
+		Longitude: loc.Lon,
+		Feeds:     make(map[string]any),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, f := range feeds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fctx, cancel := context.WithTimeout(ctx, f.timeout)
+			defer cancel()
+			v, err := a.client.runFeed(fctx, name, f.fn, loc)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				if snap.Errors == nil {
+					snap.Errors = make(map[string]string)
+				}
+				snap.Errors[name] = err.Error()
+			case name == "weather":
+				snap.Weather, _ = v.(*WeatherData)
+			default:
+				snap.Feeds[name] = v
+			}
+		}()
+	}
+	wg.Wait()
+	snap.stamp(loc.Coordinates)
+	return snap
+}
+
+// stamp sets the snapshot's time zone, its weather's or else the one at
+// coords, and its FetchedAt time in that zone
+func (snap *Snapshot) stamp(coords Coordinates) {
+	var zone string
+	if snap.Weather != nil {
+		zone = snap.Weather.Timezone
+	}
+	tz := zoneFor(zone, coords)
+	snap.Timezone, snap.FetchedAt = tz.String(), time.Now().In(tz)
+}
+
+// runFeed calls fn in a span, returning when it finishes or ctx ends,
+// whichever is first, so a feed that ignores its context still cannot hold
+// up the snapshot. A panic in fn is turned into an error.
+func (c *Client) runFeed(ctx context.Context, name string, fn FeedFunc, loc Location) (v any, err error) {
+	key := locationKey(loc)
+	ctx, span := c.startSpan(withSpanLocation(ctx, key), spanFeed,
+		SpanAttribute{attrFeed, name},
+		SpanAttribute{attrLocation, key},
+	)
+	defer func() { endSpan(span, err) }()
+
+	type result struct {
+		v   any
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				c.log().ErrorContext(ctx, "feed panicked", "feed", name, "location", key, "panic", r)
+				done <- result{err: fmt.Errorf("feed panicked: %v", r)}
+			}
+		}()
+		v, err := fn(ctx, loc)
+		done <- result{v, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-ctx.Done():
+		return nil, contextError(ctx.Err())
+	}
+}
@@ -0,0 +1,349 @@
+package feeds
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultFAABaseURL is the FAA National Airspace System status host
+const defaultFAABaseURL = "https://nasstatus.faa.gov"
+
+// WithFAABaseURL points airport status requests at another host
+func WithFAABaseURL(base string) Option {
+	return func(c *Client) {
+		c.faaBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// Airport is a commercial airport identified by its IATA code
+type Airport struct {
+	Code    string // IATA code, e.g. "ORD"
+	ICAO    string // e.g. "KORD"
+	Name    string
+	City    string
+	Country string
+	Coordinates
+}
+
+var (
+	airportsMu sync.RWMutex
+
+	// airports holds major North American airports
+	airports = map[string]Airport{
+		"ATL": {Code: "ATL", ICAO: "KATL", Name: "Hartsfield-Jackson Atlanta International", City: "Atlanta", Country: "US", Coordinates: Coordinates{Lat: 33.6407, Lon: -84.4277}},
+		"BOS": {Code: "BOS", ICAO: "KBOS", Name: "Boston Logan International", City: "Boston", Country: "US", Coordinates: Coordinates{Lat: 42.3656, Lon: -71.0096}},
+		"CLT": {Code: "CLT", ICAO: "KCLT", Name: "Charlotte Douglas International", City: "Charlotte", Country: "US", Coordinates: Coordinates{Lat: 35.2140, Lon: -80.9431}},
+		"DEN": {Code: "DEN", ICAO: "KDEN", Name: "Denver International", City: "Denver", Country: "US", Coordinates: Coordinates{Lat: 39.8561, Lon: -104.6737}},
+		"DFW": {Code: "DFW", ICAO: "KDFW", Name: "Dallas/Fort Worth International", City: "Dallas", Country: "US", Coordinates: Coordinates{Lat: 32.8998, Lon: -97.0403}},
+		"DTW": {Code: "DTW", ICAO: "KDTW", Name: "Detroit Metropolitan Wayne County", City: "Detroit", Country: "US", Coordinates: Coordinates{Lat: 42.2162, Lon: -83.3554}},
+		"EWR": {Code: "EWR", ICAO: "KEWR", Name: "Newark Liberty International", City: "Newark", Country: "US", Coordinates: Coordinates{Lat: 40.6895, Lon: -74.1745}},
+		"IAH": {Code: "IAH", ICAO: "KIAH", Name: "George Bush Intercontinental", City: "Houston", Country: "US", Coordinates: Coordinates{Lat: 29.9902, Lon: -95.3368}},
+		"JFK": {Code: "JFK", ICAO: "KJFK", Name: "John F. Kennedy International", City: "New York", Country: "US", Coordinates: Coordinates{Lat: 40.6413, Lon: -73.7781}},
+		"LAS": {Code: "LAS", ICAO: "KLAS", Name: "Harry Reid International", City: "Las Vegas", Country: "US", Coordinates: Coordinates{Lat: 36.0840, Lon: -115.1537}},
+		"LAX": {Code: "LAX", ICAO: "KLAX", Name: "Los Angeles International", City: "Los Angeles", Country: "US", Coordinates: Coordinates{Lat: 33.9416, Lon: -118.4085}},
+		"LGA": {Code: "LGA", ICAO: "KLGA", Name: "LaGuardia", City: "New York", Country: "US", Coordinates: Coordinates{Lat: 40.7769, Lon: -73.8740}},
+		"MCO": {Code: "MCO", ICAO: "KMCO", Name: "Orlando International", City: "Orlando", Country: "US", Coordinates: Coordinates{Lat: 28.4312, Lon: -81.3081}},
+		"MIA": {Code: "MIA", ICAO: "KMIA", Name: "Miami International", City: "Miami", Country: "US", Coordinates: Coordinates{Lat: 25.7959, Lon: -80.2870}},
+		"MSP": {Code: "MSP", ICAO: "KMSP", Name: "Minneapolis-Saint Paul International", City: "Minneapolis", Country: "US", Coordinates: Coordinates{Lat: 44.8848, Lon: -93.2223}},
+		"ORD": {Code: "ORD", ICAO: "KORD", Name: "O'Hare International", City: "Chicago", Country: "US", Coordinates: Coordinates{Lat: 41.9742, Lon: -87.9073}},
+		"PHL": {Code: "PHL", ICAO: "KPHL", Name: "Philadelphia International", City: "Philadelphia", Country: "US", Coordinates: Coordinates{Lat: 39.8744, Lon: -75.2424}},
+		"PHX": {Code: "PHX", ICAO: "KPHX", Name: "Phoenix Sky Harbor International", City: "Phoenix", Country: "US", Coordinates: Coordinates{Lat: 33.4352, Lon: -112.0101}},
+		"SEA": {Code: "SEA", ICAO: "KSEA", Name: "Seattle-Tacoma International", City: "Seattle", Country: "US", Coordinates: Coordinates{Lat: 47.4502, Lon: -122.3088}},
+		"SFO": {Code: "SFO", ICAO: "KSFO", Name: "San Francisco International", City: "San Francisco", Country: "US", Coordinates: Coordinates{Lat: 37.6213, Lon: -122.3790}},
+		"YUL": {Code: "YUL", ICAO: "CYUL", Name: "Montréal-Trudeau International", City: "Montreal", Country: "CA", Coordinates: Coordinates{Lat: 45.4706, Lon: -73.7408}},
+		"YVR": {Code: "YVR", ICAO: "CYVR", Name: "Vancouver International", City: "Vancouver", Country: "CA", Coordinates: Coordinates{Lat: 49.1967, Lon: -123.1815}},
+		"YYC": {Code: "YYC", ICAO: "CYYC", Name: "Calgary International", City: "Calgary", Country: "CA", Coordinates: Coordinates{Lat: 51.1215, Lon: -114.0076}},
+		"YYZ": {Code: "YYZ", ICAO: "CYYZ", Name: "Toronto Pearson International", City: "Toronto", Country: "CA", Coordinates: Coordinates{Lat: 43.6777, Lon: -79.6248}},
+		"CUN": {Code: "CUN", ICAO: "MMUN", Name: "Cancún International", City: "Cancún", Country: "MX", Coordinates: Coordinates{Lat: 21.0365, Lon: -86.8771}},
+		"GDL": {Code: "GDL", ICAO: "MMGL", Name: "Guadalajara International", City: "Guadalajara", Country: "MX", Coordinates: Coordinates{Lat: 20.5218, Lon: -103.3112}},
+		"MEX": {Code: "MEX", ICAO: "MMMX", Name: "Mexico City International", City: "Mexico City", Country: "MX", Coordinates: Coordinates{Lat: 19.4361, Lon: -99.0719}},
+	}
+)
+
+// RegisterAirport adds or replaces an airport
+func RegisterAirport(airport Airport) error {
+	if len(airport.Code) != 3 {
+		return fmt.Errorf("invalid IATA airport code %q", airport.Code)
+	}
+	if err := airport.Validate(); err != nil {
+		return err
+	}
+	airport.Code = strings.ToUpper(airport.Code)
+	airport.ICAO = strings.ToUpper(airport.ICAO)
+	airport.Country = strings.ToUpper(airport.Country)
+
+	airportsMu.Lock()
+	defer airportsMu.Unlock()
+	airports[airport.Code] = airport
+	return nil
+}
+
+// Airports returns the registered airports sorted by code
+func Airports() []Airport {
+	airportsMu.RLock()
+	defer airportsMu.RUnlock()
+	list := make([]Airport, 0, len(airports))
+	for _, a := range airports {
+		list = append(list, a)
+	}
+	slices.SortFunc(list, func(a, b Airport) int { return strings.Compare(a.Code, b.Code) })
+	return list
+}
+
+// lookupAirport finds a registered airport by IATA or ICAO code
+func lookupAirport(code string) (Airport, bool) {
+	code = strings.ToUpper(code)
+	airportsMu.RLock()
+	defer airportsMu.RUnlock()
+	if a, ok := airports[code]; ok {
+		return a, true
+	}
+	for _, a := range airports {
+		if a.ICAO == code {
+			return a, true
+		}
+	}
+	return Airport{}, false
+}
+
+// AirportStatus is the FAA-reported status of a US airport. Nil programs are
+// not in effect.
+type AirportStatus struct {
+	Code            string              `json:"code"`
+	Name            string              `json:"name,omitempty"`
+	Delayed         bool                `json:"delayed"`
+	AvgDelayMinutes int                 `json:"avgDelayMinutes"` // best estimate across the programs below
+	GroundStop      *GroundStop         `json:"groundStop,omitempty"`
+	GroundDelay     *GroundDelayProgram `json:"groundDelay,omitempty"`
+	ArrivalDelay    *AirportDelay       `json:"arrivalDelay,omitempty"`
+	DepartureDelay  *AirportDelay       `json:"departureDelay,omitempty"`
+	Closure         *AirportClosure     `json:"closure,omitempty"`
+	Updated         time.Time           `json:"updated,omitzero"`
+}
+
+// GroundStop holds departures bound for the airport at their origin
+type GroundStop struct {
+	Reason  string `json:"reason"`
+	EndTime string `json:"endTime,omitempty"` // as published, e.g. "5:30 pm EDT"
+}
+
+// GroundDelayProgram meters departures bound for the airport
+type GroundDelayProgram struct {
+	Reason     string `json:"reason"`
+	AvgMinutes int    `json:"avgMinutes"`
+	MaxMinutes int    `json:"maxMinutes"`
+}
+
+// AirportDelay is a general arrival or departure delay
+type AirportDelay struct {
+	Reason     string `json:"reason"`
+	MinMinutes int    `json:"minMinutes"`
+	MaxMinutes int    `json:"maxMinutes"`
+	Trend      string `json:"trend,omitempty"` // "Increasing", "Decreasing" or "Steady"
+}
+
+// AirportClosure is a full airport closure
+type AirportClosure struct {
+	Reason string `json:"reason"`
+	Start  string `json:"start,omitempty"`
+	Reopen string `json:"reopen,omitempty"`
+}
+
+// FAAAirportStatusResponse represents the FAA airport-status-information XML document
+type FAAAirportStatusResponse struct {
+	UpdateTime string `xml:"Update_Time"`
+	DelayTypes []struct {
+		Name         string `xml:"Name"`
+		GroundDelays []struct {
+			Airport string `xml:"ARPT"`
+			Reason  string `xml:"Reason"`
+			Avg     string `xml:"Avg"`
+			Max     string `xml:"Max"`
+		} `xml:"Ground_Delay_List>Ground_Delay"`
+		GroundStops []struct {
+			Airport string `xml:"ARPT"`
+			Reason  string `xml:"Reason"`
+			EndTime string `xml:"End_Time"`
+		} `xml:"Ground_Stop_List>Program"`
+		Delays []struct {
+			Airport string `xml:"ARPT"`
+			Reason  string `xml:"Reason"`
+			Delays  []struct {
+				Type  string `xml:"Type,attr"` // "Arrival" or "Departure"
+				Min   string `xml:"Min"`
+				Max   string `xml:"Max"`
+				Trend string `xml:"Trend"`
+			} `xml:"Arrival_Departure"`
+		} `xml:"Arrival_Departure_Delay_List>Delay"`
+		Closures []struct {
+			Airport string `xml:"ARPT"`
+			Reason  string `xml:"Reason"`
+			Start   string `xml:"Start"`
+			Reopen  string `xml:"Reopen"`
+		} `xml:"Airport_Closure_List>Airport"`
+	} `xml:"Delay_type"`
+}
+
+// FetchAirportStatus fetches an airport's status using the default Client
+func FetchAirportStatus(code string) (*AirportStatus, error) {
+	return defaultClient.FetchAirportStatus(code)
+}
+
+// FetchAirportStatus returns the FAA status of a US airport by IATA or ICAO
+// code. An airport without delays has Delayed false. Registered airports
+// outside the US return ErrAirportStatusUnavailable.
+func (c *Client) FetchAirportStatus(code string) (*AirportStatus, error) {
+	airport, ok := lookupAirport(code)
+	if !ok {
+		// The FAA also reports smaller airports that are not registered
+		if len(code) != 3 {
+			return nil, fmt.Errorf("%w: no airport %q", ErrUnknownLocation, code)
+		}
+		airport = Airport{Code: strings.ToUpper(code), Country: "US"}
+	}
+	if airport.Country != "US" {
+		return nil, fmt.Errorf("%w: %s is outside the FAA's coverage", ErrAirportStatusUnavailable, airport.Code)
+	}
+
+	statuses, updated, err := c.fetchAirportStatuses(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	status, ok := statuses[airport.Code]
+	if !ok {
+		status = &AirportStatus{Code: airport.Code, Updated: updated}
+	}
+	status.Name = airport.Name
+	return status, nil
+}
+
+// FetchAirportDelays fetches delayed airports using the default Client
+func FetchAirportDelays() ([]AirportStatus, error) {
+	return defaultClient.FetchAirportDelays()
+}
+
+// FetchAirportDelays returns every US airport the FAA reports a ground stop,
+// delay program, general delay or closure for, longest average delay first
+func (c *Client) FetchAirportDelays() ([]AirportStatus, error) {
+	statuses, _, err := c.fetchAirportStatuses(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	delayed := make([]AirportStatus, 0, len(statuses))
+	for _, s := range statuses {
+		if a, ok := lookupAirport(s.Code); ok {
+			s.Name = a.Name
+		}
+		delayed = append(delayed, *s)
+	}
+	slices.SortFunc(delayed, func(a, b AirportStatus) int {
+		return cmp.Or(cmp.Compare(b.AvgDelayMinutes, a.AvgDelayMinutes), strings.Compare(a.Code, b.Code))
+	})
+	return delayed, nil
+}
+
+// fetchAirportStatuses requests the national airport status document and
+// groups its programs by airport code
+func (c *Client) fetchAirportStatuses(ctx context.Context) (map[string]*AirportStatus, time.Time, error) {
+	var apiResp FAAAirportStatusResponse
+	if err := c.getXML(ctx, c.faaBaseURL+"/api/airport-status-information", nil, &apiResp); err != nil {
+		return nil, time.Time{}, err
+	}
+	// e.g. "Tue Oct 15 14:23:10 2024 GMT"; a zero time when the format changes
+	updated, _ := time.Parse("Mon Jan 2 15:04:05 2006 MST", strings.TrimSpace(apiResp.UpdateTime))
+
+	statuses := make(map[string]*AirportStatus)
+	status := func(code string) *AirportStatus {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		s, ok := statuses[code]
+		if !ok {
+			s = &AirportStatus{Code: code, Delayed: true, Updated: updated}
+			statuses[code] = s
+		}
+		return s
+	}
+	for _, dt := range apiResp.DelayTypes {
+		for _, d := range dt.GroundDelays {
+			status(d.Airport).GroundDelay = &GroundDelayProgram{
+				Reason:     strings.TrimSpace(d.Reason),
+				AvgMinutes: parseFAADuration(d.Avg),
+				MaxMinutes: parseFAADuration(d.Max),
+			}
+		}
+		for _, g := range dt.GroundStops {
+			status(g.Airport).GroundStop = &GroundStop{
+				Reason:  strings.TrimSpace(g.Reason),
+				EndTime: strings.TrimSpace(g.EndTime),
+			}
+		}
+		for _, d := range dt.Delays {
+			s := status(d.Airport)
+			for _, ad := range d.Delays {
+				delay := &AirportDelay{
+					Reason:     strings.TrimSpace(d.Reason),
+					MinMinutes: parseFAADuration(ad.Min),
+					MaxMinutes: parseFAADuration(ad.Max),
+					Trend:      strings.TrimSpace(ad.Trend),
+				}
+				if strings.EqualFold(ad.Type, "Arrival") {
+					s.ArrivalDelay = delay
+				} else {
+					s.DepartureDelay = delay
+				}
+			}
+		}
+		for _, cl := range dt.Closures {
+			status(cl.Airport).Closure = &AirportClosure{
+				Reason: strings.TrimSpace(cl.Reason),
+				Start:  strings.TrimSpace(cl.Start),
+				Reopen: strings.TrimSpace(cl.Reopen),
+			}
+		}
+	}
+	for _, s := range statuses {
+		s.AvgDelayMinutes = s.avgDelay()
+	}
+	return statuses, updated, nil
+}
+
+// avgDelay estimates the average delay: the ground delay program's average
+// when there is one, otherwise the midpoint of the longest general delay
+func (s *AirportStatus) avgDelay() int {
+	if s.GroundDelay != nil && s.GroundDelay.AvgMinutes > 0 {
+		return s.GroundDelay.AvgMinutes
+	}
+	avg := 0
+	for _, d := range []*AirportDelay{s.ArrivalDelay, s.DepartureDelay} {
+		if d != nil {
+			avg = max(avg, (d.MinMinutes+d.MaxMinutes)/2)
+		}
+	}
+	return avg
+}
+
+// faaDurationPart matches one unit of an FAA duration, e.g. "1 hour" or "30 minutes"
+var faaDurationPart = regexp.MustCompile(`(\d+)\s*(hour|hr|minute|min)`)
+
+// parseFAADuration converts an FAA duration such as "1 hour and 30 minutes"
+// to minutes; unrecognized text is 0
+func parseFAADuration(s string) int {
+	minutes := 0
+	for _, m := range faaDurationPart.FindAllStringSubmatch(strings.ToLower(s), -1) {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(m[2], "h") {
+			n *= 60
+		}
+		minutes += n
+	}
+	return minutes
+}
@@ -0,0 +1,113 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// defaultAirQualityBaseURL is the Open-Meteo air quality API host
+const defaultAirQualityBaseURL = "https://air-quality-api.open-meteo.com"
+
+// AirQualityData represents current air quality. Concentrations are in µg/m³.
+type AirQualityData struct {
+	AQI       int     `json:"aqi"`      // US EPA air quality index
+	Category  string  `json:"category"` // EPA category for AQI, e.g. "Moderate"
+	PM25      float64 `json:"pm25"`
+	PM10      float64 `json:"pm10"`
+	Ozone     float64 `json:"ozone"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+	Timezone  string  `json:"timezone,omitempty"`
+}
+
+// OpenMeteoAirQualityResponse represents a current-conditions response from the Open-Meteo air quality API
+type OpenMeteoAirQualityResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+	Current   struct {
+		Time  Timestamp `json:"time"`
+		USAQI *float64  `json:"us_aqi"`
+		PM25  float64   `json:"pm2_5"`
+		PM10  float64   `json:"pm10"`
+		Ozone float64   `json:"ozone"`
+	} `json:"current"`
+}
+
+// aqiCategories are the upper AQI bound of each US EPA category
+var aqiCategories = []struct {
+	max  int
+	name string
+}{
+	{50, "Good"},
+	{100, "Moderate"},
+	{150, "Unhealthy for Sensitive Groups"},
+	{200, "Unhealthy"},
+	{300, "Very Unhealthy"},
+}
+
+// AQICategory returns the US EPA category name for an air quality index value
+func AQICategory(aqi int) string {
+	for _, c := range aqiCategories {
+		if aqi <= c.max {
+			return c.name
+		}
+	}
+	return "Hazardous"
+}
+
+// FetchAirQuality fetches air quality for a country using the default Client
+func FetchAirQuality(country string) (*AirQualityData, error) {
+	return defaultClient.FetchAirQuality(country)
+}
+
+// FetchAirQuality fetches current air quality at a country's coordinates
+func (c *Client) FetchAirQuality(country string) (*AirQualityData, error) {
+	coords, _, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchAirQuality(context.Background(), coords)
+}
+
+// FetchAirQualityByCoords fetches air quality for coordinates using the default Client
+func FetchAirQualityByCoords(lat, lon float64) (*AirQualityData, error) {
+	return defaultClient.FetchAirQualityByCoords(lat, lon)
+}
+
+// FetchAirQualityByCoords fetches the current US AQI, PM2.5, PM10 and ozone at the given coordinates
+func (c *Client) FetchAirQualityByCoords(lat, lon float64) (*AirQualityData, error) {
+	coords := Coordinates{Lat: lat, Lon: lon}
+	if err := coords.Validate(); err != nil {
+		return nil, err
+	}
+	return c.fetchAirQuality(context.Background(), coords)
+}
+
+// fetchAirQuality requests current air quality at coords
+func (c *Client) fetchAirQuality(ctx context.Context, coords Coordinates) (*AirQualityData, error) {
+	q := coords.query()
+	q.Set("current", "us_aqi,pm2_5,pm10,ozone")
+	q.Set("timezone", "auto")
+
+	var apiResp OpenMeteoAirQualityResponse
+	if err := c.getOpenMeteo(ctx, c.airQualityBaseURL+"/v1/air-quality", q, &apiResp); err != nil {
+		return nil, err
+	}
+	if apiResp.Current.Time.IsZero() || apiResp.Current.USAQI == nil {
+		return nil, withKind(ErrDecode, errors.New("air quality response has no current index"))
+	}
+
+	aqi := int(math.Round(*apiResp.Current.USAQI))
+	return &AirQualityData{
+		AQI:       aqi,
+		Category:  LocalizeAQICategory(AQICategory(aqi), c.language),
+		PM25:      apiResp.Current.PM25,
+		PM10:      apiResp.Current.PM10,
+		Ozone:     apiResp.Current.Ozone,
+		Latitude:  apiResp.Latitude,
+		Longitude: apiResp.Longitude,
+		Timezone:  apiResp.Timezone,
+	}, nil
+}
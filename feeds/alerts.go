@@ -0,0 +1,205 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ecccTimeStampLayout is the layout of ECCC citypage <timeStamp> elements
+const ecccTimeStampLayout = "20060102150405"
+
+// Alert severities, following the Common Alerting Protocol levels
+const (
+	AlertSeverityExtreme  = "extreme"
+	AlertSeveritySevere   = "severe"
+	AlertSeverityModerate = "moderate"
+	AlertSeverityMinor    = "minor"
+	AlertSeverityUnknown  = "unknown"
+)
+
+// WeatherAlert is an active severe weather alert or warning
+type WeatherAlert struct {
+	Event     string    `json:"event"`    // e.g. "Tornado Warning"
+	Headline  string    `json:"headline"` // one-line summary from the issuing agency
+	Severity  string    `json:"severity"` // one of the AlertSeverity constants
+	Effective time.Time `json:"effective"`
+	Expires   time.Time `json:"expires,omitzero"` // zero if the feed gives no expiry
+
+	// SeverityLabel is Severity for display, in the Client's language
+	SeverityLabel string `json:"severityLabel,omitempty"`
+}
+
+// NWSAlertsResponse represents an /alerts/active response from api.weather.gov
+type NWSAlertsResponse struct {
+	Features []struct {
+		Properties struct {
+			Event     string    `json:"event"`
+			Headline  string    `json:"headline"`
+			Severity  string    `json:"severity"`
+			Effective time.Time `json:"effective"`
+			Expires   time.Time `json:"expires"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// FetchWeatherAlerts fetches active alerts for a country using the default Client
+func FetchWeatherAlerts(country string) ([]WeatherAlert, error) {
+	return defaultClient.FetchWeatherAlerts(country)
+}
+
+// FetchWeatherAlerts fetches the active severe weather alerts at a country's
+// coordinates. Unknown countries follow the Client's fallback setting.
+func (c *Client) FetchWeatherAlerts(country string) ([]WeatherAlert, error) {
+	coords, source, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+	if source == SourceFallback {
+		country = "US" // the fallback location is New York
+	}
+	return c.fetchAlerts(context.Background(), country, coords)
+}
+
+// FetchWeatherAlertsByCoords fetches active alerts for coordinates using the default Client
+func FetchWeatherAlertsByCoords(country string, lat, lon float64) ([]WeatherAlert, error) {
+	return defaultClient.FetchWeatherAlertsByCoords(country, lat, lon)
+}
+
+// FetchWeatherAlertsByCoords fetches the active severe weather alerts at the
+// given coordinates from the national feed of country: the National Weather
+// Service for US and Environment and Climate Change Canada for CA. Mexico's
+// SMN publishes no machine-readable point feed, so MX and any other country
+// return ErrAlertsUnavailable. An empty slice means no alerts are in effect.
+func (c *Client) FetchWeatherAlertsByCoords(country string, lat, lon float64) ([]WeatherAlert, error) {
+	coords := Coordinates{Lat: lat, Lon: lon}
+	if err := coords.Validate(); err != nil {
+		return nil, err
+	}
+	return c.fetchAlerts(context.Background(), country, coords)
+}
+
+// fetchAlerts dispatches to the national alert feed for country, giving
+// alert times in the time zone at coords
+func (c *Client) fetchAlerts(ctx context.Context, country string, coords Coordinates) ([]WeatherAlert, error) {
+	var alerts []WeatherAlert
+	var err error
+	switch strings.ToUpper(country) {
+	case "US":
+		alerts, err = c.fetchNWSAlerts(ctx, coords)
+	case "CA":
+		alerts, err = c.fetchECCCAlerts(ctx, coords)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrAlertsUnavailable, country)
+	}
+	if err != nil {
+		return nil, err
+	}
+	loc := TimezoneAt(coords)
+	for i := range alerts {
+		alerts[i].Effective = localTime(alerts[i].Effective, loc)
+		alerts[i].Expires = localTime(alerts[i].Expires, loc)
+		alerts[i].SeverityLabel = AlertSeverityLabel(alerts[i].Severity, c.language)
+	}
+	return alerts, nil
+}
+
+// fetchNWSAlerts fetches the active NWS alerts whose area contains coords
+func (c *Client) fetchNWSAlerts(ctx context.Context, coords Coordinates) ([]WeatherAlert, error) {
+	q := url.Values{}
+	q.Set("point", fmt.Sprintf("%.4f,%.4f", coords.Lat, coords.Lon))
+
+	var resp NWSAlertsResponse
+	if err := c.getJSON(ctx, c.nwsBaseURL+"/alerts/active", q, &resp); err != nil {
+		return nil, err
+	}
+
+	alerts := make([]WeatherAlert, 0, len(resp.Features))
+	for _, f := range resp.Features {
+		p := f.Properties
+		alerts = append(alerts, WeatherAlert{
+			Event:     p.Event,
+			Headline:  p.Headline,
+			Severity:  normalizeAlertSeverity(p.Severity),
+			Effective: p.Effective,
+			Expires:   p.Expires,
+		})
+	}
+	return alerts, nil
+}
+
+// fetchECCCAlerts fetches the warnings listed on the citypage nearest to coords.
+// Citypages carry no expiry time, and ended warnings are skipped.
+func (c *Client) fetchECCCAlerts(ctx context.Context, coords Coordinates) ([]WeatherAlert, error) {
+	page, err := c.fetchCityPage(ctx, coords)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]WeatherAlert, 0, len(page.Warnings.Events))
+	for _, e := range page.Warnings.Events {
+		if strings.EqualFold(e.Type, "ended") {
+			continue
+		}
+		alert := WeatherAlert{
+			Event:    ecccEventName(e.Description),
+			Headline: strings.TrimSpace(e.Description),
+			Severity: ecccAlertSeverity(e.Type, e.Priority),
+		}
+		for _, dt := range e.DateTimes {
+			if dt.Zone != "UTC" {
+				continue
+			}
+			if t, err := time.Parse(ecccTimeStampLayout, strings.TrimSpace(dt.TimeStamp)); err == nil {
+				alert.Effective = t
+			}
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+// ecccEventName turns a description such as "SNOWFALL WARNING IN EFFECT" into "Snowfall Warning"
+func ecccEventName(description string) string {
+	name := strings.TrimSpace(description)
+	for _, suffix := range []string{" IN EFFECT", " ENDED"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	words := strings.Fields(strings.ToLower(name))
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToUpper(r)) + w[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+// ecccAlertSeverity maps an ECCC warning type and priority to an alert severity
+func ecccAlertSeverity(typ, priority string) string {
+	switch strings.ToLower(typ) {
+	case "warning":
+		if strings.EqualFold(priority, "urgent") {
+			return AlertSeverityExtreme
+		}
+		return AlertSeveritySevere
+	case "watch":
+		return AlertSeverityModerate
+	case "advisory", "statement":
+		return AlertSeverityMinor
+	default:
+		return AlertSeverityUnknown
+	}
+}
+
+// normalizeAlertSeverity lowercases a CAP severity, mapping anything unrecognised to unknown
+func normalizeAlertSeverity(s string) string {
+	switch s = strings.ToLower(s); s {
+	case AlertSeverityExtreme, AlertSeveritySevere, AlertSeverityModerate, AlertSeverityMinor:
+		return s
+	default:
+		return AlertSeverityUnknown
+	}
+}
@@ -0,0 +1,98 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Timestamp layouts used by Open-Meteo when timeformat=iso8601
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02T15:04"
+)
+
+// TimeFormat selects how Open-Meteo encodes timestamps
+type TimeFormat string
+
+const (
+	// TimeFormatISO8601 returns local date/time strings such as "2025-01-02T15:00"
+	TimeFormatISO8601 TimeFormat = "iso8601"
+	// TimeFormatUnixTime returns seconds since the Unix epoch
+	TimeFormatUnixTime TimeFormat = "unixtime"
+)
+
+// Timestamp is an Open-Meteo timestamp in either timeformat. ISO8601 values
+// carry no offset and are interpreted in the location's own time zone.
+type Timestamp struct {
+	iso    string
+	unix   int64
+	isUnix bool
+}
+
+// UnmarshalJSON accepts an ISO8601 string or a Unix epoch number
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		*t = Timestamp{}
+		return json.Unmarshal(data, &t.iso)
+	}
+	if string(data) == "null" {
+		*t = Timestamp{}
+		return nil
+	}
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %s", data)
+	}
+	*t = Timestamp{unix: n, isUnix: true}
+	return nil
+}
+
+// IsZero reports whether the timestamp was absent
+func (t Timestamp) IsZero() bool {
+	return !t.isUnix && t.iso == ""
+}
+
+// String returns the timestamp as received
+func (t Timestamp) String() string {
+	if t.isUnix {
+		return strconv.FormatInt(t.unix, 10)
+	}
+	return t.iso
+}
+
+// In converts the timestamp to a time.Time in loc
+func (t Timestamp) In(loc *time.Location) (time.Time, error) {
+	if t.isUnix {
+		return time.Unix(t.unix, 0).In(loc), nil
+	}
+	layout := dateTimeLayout
+	if len(t.iso) == len(dateLayout) {
+		layout = dateLayout
+	}
+	parsed, err := time.ParseInLocation(layout, t.iso, loc)
+	if err != nil {
+		return time.Time{}, withKind(ErrDecode, fmt.Errorf("invalid timestamp %q: %w", t.iso, err))
+	}
+	return parsed, nil
+}
+
+// apiLocation resolves the location's time zone from the IANA name returned by
+// Open-Meteo, falling back to a fixed offset if the zone database lacks it
+func apiLocation(name string, offsetSeconds int) *time.Location {
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.FixedZone(name, offsetSeconds)
+}
+
+// truncateDay strips the time of day, keeping the date in t's location
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
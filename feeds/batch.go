@@ -0,0 +1,184 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Batch fetching limits
+const (
+	defaultBatchConcurrency = 4
+	maxLocationsPerRequest  = 50 // coordinates per Open-Meteo multi-location request
+)
+
+// WithBatchConcurrency sets how many upstream requests a batch fetch runs at once (default 4)
+func WithBatchConcurrency(n int) Option {
+	return func(c *Client) {
+		c.batchConcurrency = max(n, 1)
+	}
+}
+
+// FetchWeatherBatch fetches several countries using the default Client
+func FetchWeatherBatch(countries []string) (map[string]*WeatherData, map[string]error) {
+	return defaultClient.FetchWeatherBatch(countries)
+}
+
+// FetchWeatherBatch fetches current conditions for several countries at
+// once, keyed by country code as given. Each country appears in exactly one
+// of the returned maps. See FetchWeatherBatchCoords for how requests are made.
+func (c *Client) FetchWeatherBatch(countries []string) (map[string]*WeatherData, map[string]error) {
+	results := make(map[string]*WeatherData)
+	errs := make(map[string]error)
+
+	var coords []Coordinates
+	var keys []string
+	sources := make(map[string]string)
+	for _, country := range countries {
+		if _, seen := sources[country]; seen {
+			continue
+		}
+		co, source, err := c.resolveCountry(country)
+		if err != nil {
+			errs[country] = err
+			continue
+		}
+		sources[country] = source
+		coords = append(coords, co)
+		keys = append(keys, country)
+	}
+
+	fetched, fetchErrs := c.fetchBatch(context.Background(), coords)
+	for i, key := range keys {
+		if fetchErrs[i] != nil {
+			errs[key] = fetchErrs[i]
+			continue
+		}
+		w := fetched[i]
+		w.Source = sources[key]
+		c.finishWeather(w)
+		results[key] = w
+	}
+	return results, errs
+}
+
+// FetchWeatherBatchCoords fetches several locations using the default Client
+func FetchWeatherBatchCoords(coords []Coordinates) (map[Coordinates]*WeatherData, map[Coordinates]error) {
+	return defaultClient.FetchWeatherBatchCoords(coords)
+}
+
+// FetchWeatherBatchCoords fetches current conditions for several locations
+// at once. With the default Open-Meteo provider, locations are combined into
+// multi-location requests of up to 50 coordinates; other providers are called
+// once per location. Either way at most WithBatchConcurrency requests run at
+// a time. Each location appears in exactly one of the returned maps.
+func (c *Client) FetchWeatherBatchCoords(coords []Coordinates) (map[Coordinates]*WeatherData, map[Coordinates]error) {
+	results := make(map[Coordinates]*WeatherData)
+	errs := make(map[Coordinates]error)
+
+	var valid []Coordinates
+	for _, co := range coords {
+		if _, seen := results[co]; seen {
+			continue
+		}
+		if _, seen := errs[co]; seen {
+			continue
+		}
+		if err := co.Validate(); err != nil {
+			errs[co] = err
+			continue
+		}
+		results[co] = nil // mark as seen
+		valid = append(valid, co)
+	}
+
+	fetched, fetchErrs := c.fetchBatch(context.Background(), valid)
+	for i, co := range valid {
+		if fetchErrs[i] != nil {
+			delete(results, co)
+			errs[co] = fetchErrs[i]
+			continue
+		}
+		w := fetched[i]
+		w.Source = SourceExplicit
+		c.finishWeather(w)
+		results[co] = w
+	}
+	return results, errs
+}
+
+// fetchBatch fetches raw readings for coords, returning results and errors by index
+func (c *Client) fetchBatch(ctx context.Context, coords []Coordinates) ([]*WeatherData, []error) {
+	results := make([]*WeatherData, len(coords))
+	errs := make([]error, len(coords))
+
+	// Each job fills results[start:end]
+	type job struct{ start, end int }
+	var jobs []job
+	om, combine := c.provider.(*OpenMeteoProvider)
+	if combine {
+		for start := 0; start < len(coords); start += maxLocationsPerRequest {
+			jobs = append(jobs, job{start, min(start+maxLocationsPerRequest, len(coords))})
+		}
+	} else {
+		for i := range coords {
+			jobs = append(jobs, job{i, i + 1})
+		}
+	}
+
+	sem := make(chan struct{}, max(c.batchConcurrency, 1))
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+
+			if !combine {
+				results[j.start], errs[j.start] = c.provider.Fetch(ctx, coords[j.start])
+				return
+			}
+			ws, err := om.client.openMeteoMulti(ctx, coords[j.start:j.end])
+			for i := j.start; i < j.end; i++ {
+				if err != nil {
+					errs[i] = err
+				} else {
+					results[i] = ws[i-j.start]
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return results, errs
+}
+
+// openMeteoMulti fetches current conditions for several coordinates in one
+// Open-Meteo request, returning readings in the order of coords
+func (c *Client) openMeteoMulti(ctx context.Context, coords []Coordinates) ([]*WeatherData, error) {
+	lats := make([]string, len(coords))
+	lons := make([]string, len(coords))
+	for i, co := range coords {
+		lats[i] = fmt.Sprintf("%.4f", co.Lat)
+		lons[i] = fmt.Sprintf("%.4f", co.Lon)
+	}
+
+	q := url.Values{}
+	q.Set("latitude", strings.Join(lats, ","))
+	q.Set("longitude", strings.Join(lons, ","))
+	q.Set("current", c.currentVariables())
+	q.Set("timezone", "auto")
+
+	var apiResp MultiLocationResponse
+	if err := c.getOpenMeteo(ctx, c.forecastBaseURL+"/v1/forecast", q, &apiResp); err != nil {
+		return nil, err
+	}
+	if len(apiResp) != len(coords) {
+		return nil, withKind(ErrDecode, fmt.Errorf("weather response has %d locations, requested %d", len(apiResp), len(coords)))
+	}
+	if err := apiResp.Validate(); err != nil {
+		return nil, err
+	}
+	return apiResp.ToWeatherData(), nil
+}
@@ -0,0 +1,86 @@
+package feeds
+
+// WeatherDataBuilder builds WeatherData values for tests and synthetic data.
+//
+// Defaults: weather code 0 (clear sky), 20°C, feels-like equal to the
+// temperature, a summary derived from the weather code and no location.
+type WeatherDataBuilder struct {
+	w            WeatherData
+	feelsLikeSet bool
+}
+
+// NewWeatherDataBuilder returns a builder populated with the defaults
+func NewWeatherDataBuilder() *WeatherDataBuilder {
+	return &WeatherDataBuilder{w: WeatherData{TemperatureC: 20}}
+}
+
+// WithSummary overrides the summary derived from the weather code
+func (b *WeatherDataBuilder) WithSummary(summary string) *WeatherDataBuilder {
+	b.w.Summary = summary
+	return b
+}
+
+// WithWeatherCode sets the WMO weather code
+func (b *WeatherDataBuilder) WithWeatherCode(code int) *WeatherDataBuilder {
+	b.w.WeatherCode = code
+	return b
+}
+
+// WithTemperatureC sets the temperature in Celsius
+func (b *WeatherDataBuilder) WithTemperatureC(c float64) *WeatherDataBuilder {
+	b.w.TemperatureC = c
+	return b
+}
+
+// WithFeelsLikeC sets the apparent temperature in Celsius
+func (b *WeatherDataBuilder) WithFeelsLikeC(c float64) *WeatherDataBuilder {
+	b.w.FeelsLikeC = c
+	b.feelsLikeSet = true
+	return b
+}
+
+// WithHumidityPct sets the relative humidity in percent
+func (b *WeatherDataBuilder) WithHumidityPct(pct float64) *WeatherDataBuilder {
+	b.w.HumidityPct = pct
+	return b
+}
+
+// WithWind sets the wind speed in km/h and the direction it blows from in degrees
+func (b *WeatherDataBuilder) WithWind(speedKmh, directionDeg float64) *WeatherDataBuilder {
+	b.w.WindSpeedKmh = speedKmh
+	b.w.WindDirectionDeg = directionDeg
+	return b
+}
+
+// WithPressureHPa sets the mean sea-level pressure in hPa
+func (b *WeatherDataBuilder) WithPressureHPa(hpa float64) *WeatherDataBuilder {
+	b.w.PressureHPa = hpa
+	return b
+}
+
+// WithLocation sets the coordinates
+func (b *WeatherDataBuilder) WithLocation(lat, lon float64) *WeatherDataBuilder {
+	b.w.Latitude = lat
+	b.w.Longitude = lon
+	return b
+}
+
+// WithTimezone sets the IANA timezone name
+func (b *WeatherDataBuilder) WithTimezone(tz string) *WeatherDataBuilder {
+	b.w.Timezone = tz
+	return b
+}
+
+// Build returns the WeatherData, filling in derived defaults
+func (b *WeatherDataBuilder) Build() WeatherData {
+	w := b.w
+	if w.Summary == "" {
+		w.Summary = describeWeatherCode(w.WeatherCode)
+	}
+	if !b.feelsLikeSet {
+		w.FeelsLikeC = w.TemperatureC
+	}
+	w.ConditionSeverity = ClassifyConditions(w.WeatherCode, w.PrecipitationMM)
+	w.Icon = WeatherIcon(w.WeatherCode, true) // built data has no time to tell night by
+	return w
+}
@@ -0,0 +1,255 @@
+package feeds
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Cache defaults
+const (
+	defaultCacheTTL        = 10 * time.Minute
+	defaultCacheMaxEntries = 1000
+)
+
+// Cache stores upstream response bodies keyed by request URL. Implementations
+// must be safe for concurrent use.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+	Delete(key string)
+	Flush()
+	Len() int
+}
+
+// CacheEntry is a cached response body and when it was fetched. ETag and
+// LastModified hold the upstream validators, if any, so an expired entry can
+// be revalidated with a conditional request instead of downloaded again.
+type CacheEntry struct {
+	Body         []byte
+	Fetched      time.Time
+	ETag         string
+	LastModified string
+}
+
+// MemoryCache is an in-memory Cache holding a bounded number of entries;
+// when full, the oldest entry is evicted
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]CacheEntry
+}
+
+// NewMemoryCache creates a MemoryCache holding at most maxEntries responses
+// (unbounded if maxEntries is zero or negative)
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{maxEntries: maxEntries, entries: make(map[string]CacheEntry)}
+}
+
+// Get returns the entry for key
+func (m *MemoryCache) Get(key string) (CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	return e, ok
+}
+
+// Set stores entry under key, evicting the oldest entry if the cache is full
+func (m *MemoryCache) Set(key string, entry CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.entries[key]; !ok && m.maxEntries > 0 && len(m.entries) >= m.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, e := range m.entries {
+			if oldestKey == "" || e.Fetched.Before(oldest) {
+				oldestKey, oldest = k, e.Fetched
+			}
+		}
+		delete(m.entries, oldestKey)
+	}
+	m.entries[key] = entry
+}
+
+// Delete removes the entry for key
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+// Flush removes every entry
+func (m *MemoryCache) Flush() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.entries)
+}
+
+// Len returns the number of entries
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// WithCacheTTL sets how long upstream responses are served from the cache
+// (default 10 minutes). Responses are keyed by request URL, so each feed and
+// coordinate pair is cached separately. A zero or negative TTL disables caching.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cacheTTL = ttl
+	}
+}
+
+// WithStaleWhileRevalidate lets a response up to window past its TTL be
+// served immediately while a single background request refreshes it
+func WithStaleWhileRevalidate(window time.Duration) Option {
+	return func(c *Client) {
+		c.staleWindow = window
+	}
+}
+
+// WithCache replaces the in-memory response cache, e.g. with a FileCache so
+// responses survive restarts, or to share one cache between Clients
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithStaleOnError serves the last cached response, however old, when the
+// upstream request fails, so a deployment keeps showing last-known data while
+// offline. Errors are still returned when nothing is cached.
+func WithStaleOnError() Option {
+	return func(c *Client) {
+		c.staleOnError = true
+	}
+}
+
+// bypassCacheKey marks a context whose requests skip the response cache
+type bypassCacheKey struct{}
+
+// BypassCache returns a context whose fetches always go upstream. Fresh
+// responses are still stored for later callers.
+func BypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+// maxAgeKey caps the age of cached responses a context's requests accept
+type maxAgeKey struct{}
+
+// withMaxAge returns a context whose fetches accept cached responses no older
+// than maxAge, for feeds that go stale faster than the Client's TTL
+func withMaxAge(ctx context.Context, maxAge time.Duration) context.Context {
+	return context.WithValue(ctx, maxAgeKey{}, maxAge)
+}
+
+// FlushCache empties the default Client's response cache
+func FlushCache() {
+	defaultClient.FlushCache()
+}
+
+// FlushCache empties the Client's response cache
+func (c *Client) FlushCache() {
+	c.cache.Flush()
+}
+
+// cachedGet returns the body for target from the cache when fresh enough,
+// otherwise fetches and stores it
+func (c *Client) cachedGet(ctx context.Context, target string) ([]byte, error) {
+	if c.cacheTTL <= 0 {
+		e, err := c.fetch(ctx, target, nil)
+		return e.Body, err
+	}
+
+	ttl := c.cacheTTL
+	if maxAge, ok := ctx.Value(maxAgeKey{}).(time.Duration); ok && maxAge < ttl {
+		ttl = maxAge
+	}
+	e, cached := c.cache.Get(target)
+	if bypass, _ := ctx.Value(bypassCacheKey{}).(bool); cached && !bypass {
+		age := time.Since(e.Fetched)
+		if age < ttl {
+			c.observeCache(target, CacheHit)
+			c.log().DebugContext(ctx, "cache hit", "url", redactURL(target), "age", age)
+			return e.Body, nil
+		}
+		if age < ttl+c.staleWindow {
+			c.observeCache(target, CacheStale)
+			c.recordStale()
+			c.log().InfoContext(ctx, "serving stale response while revalidating", "url", redactURL(target), "age", age)
+			c.revalidate(target)
+			return e.Body, nil
+		}
+	}
+
+	var prev *CacheEntry
+	if cached {
+		prev = &e
+	}
+	fresh, err := c.fetch(ctx, target, prev)
+	if err != nil {
+		if cached && c.staleOnError && ctx.Err() == nil {
+			c.observeCache(target, CacheStale)
+			c.recordStale()
+			c.log().InfoContext(ctx, "serving stale response after upstream error", "url", redactURL(target),
+				"age", time.Since(e.Fetched), "error", err)
+			return e.Body, nil
+		}
+		c.observeCache(target, CacheMiss)
+		return nil, err
+	}
+	c.observeCache(target, CacheMiss)
+	c.cache.Set(target, fresh)
+	return fresh.Body, nil
+}
+
+// observeCache reports a cache lookup to the Client's metrics
+func (c *Client) observeCache(target, result string) {
+	if c.metrics != nil {
+		c.metrics.ObserveCache(hostOf(target), result)
+	}
+}
+
+// revalidate refreshes target in the background unless a refresh is already running
+func (c *Client) revalidate(target string) {
+	c.revalidateMu.Lock()
+	if c.revalidating[target] {
+		c.revalidateMu.Unlock()
+		return
+	}
+	c.revalidating[target] = true
+	c.revalidateMu.Unlock()
+
+	go func() {
+		defer func() {
+			c.revalidateMu.Lock()
+			delete(c.revalidating, target)
+			c.revalidateMu.Unlock()
+		}()
+		var prev *CacheEntry
+		if e, ok := c.cache.Get(target); ok {
+			prev = &e
+		}
+		// A failed refresh leaves the stale entry to expire on its own
+		fresh, err := c.fetch(context.Background(), target, prev)
+		if err != nil {
+			c.log().Warn("background revalidation failed", "url", redactURL(target), "error", err)
+			return
+		}
+		c.cache.Set(target, fresh)
+	}()
+}
+
+// evict drops target from the cache after its body failed to parse, and
+// counts the decode error
+func (c *Client) evict(target string) {
+	if c.cacheTTL > 0 {
+		c.cache.Delete(target)
+	}
+	if c.metrics != nil {
+		c.metrics.ObserveDecodeError(hostOf(target))
+	}
+	c.log().Warn("unparseable upstream response", "url", redactURL(target))
+}
@@ -0,0 +1,87 @@
+package feeds
+
+import "time"
+
+// date returns midnight UTC on the given day; calendar rules compare dates
+// only, so they work in UTC and callers convert local days with civilDate
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// civilDate returns t's calendar day in its own location as a UTC midnight
+func civilDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return date(y, m, d)
+}
+
+// nthWeekday returns the nth given weekday of a month (n = 1 for the first);
+// a negative n counts from the end of the month, so -1 is the last
+func nthWeekday(year int, month time.Month, wd time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := date(year, month+1, 0)
+		return last.AddDate(0, 0, -((int(last.Weekday())-int(wd)+7)%7 + 7*(-n-1)))
+	}
+	first := date(year, month, 1)
+	return first.AddDate(0, 0, (int(wd)-int(first.Weekday())+7)%7+7*(n-1))
+}
+
+// weekdayOnOrBefore returns the last given weekday on or before t
+func weekdayOnOrBefore(t time.Time, wd time.Weekday) time.Time {
+	return t.AddDate(0, 0, -((int(t.Weekday()) - int(wd) + 7) % 7))
+}
+
+// easterSunday returns the date of Western Easter (anonymous Gregorian algorithm)
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+// observedNearest moves a Saturday holiday to Friday and a Sunday one to
+// Monday, as US federal holidays are observed
+func observedNearest(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, -1)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// observedForward moves each weekend holiday to the next weekday that is not
+// already a holiday, as Canadian holidays are observed; with Christmas on a
+// Saturday that gives Monday and Boxing Day Tuesday
+func observedForward(days []time.Time) []time.Time {
+	taken := make(map[time.Time]bool, len(days))
+	for _, d := range days {
+		if !isWeekend(d) {
+			taken[d] = true
+		}
+	}
+	out := make([]time.Time, 0, len(days))
+	for _, d := range days {
+		if isWeekend(d) {
+			for isWeekend(d) || taken[d] {
+				d = d.AddDate(0, 0, 1)
+			}
+			taken[d] = true
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// isWeekend reports whether t falls on a Saturday or Sunday
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
@@ -0,0 +1,135 @@
+package feeds
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Change describes how a location's snapshot changed in one refresh
+type Change struct {
+	Location string    // the Scheduler's key: country code, or "lat,lon"
+	Previous *Snapshot // nil for the location's first snapshot
+	Current  *Snapshot
+	Feeds    []string // names of the feeds whose data changed, "weather" included, sorted
+}
+
+// Changed reports whether a feed's data changed
+func (c Change) Changed(feed string) bool {
+	_, found := slices.BinarySearch(c.Feeds, feed)
+	return found
+}
+
+// WeatherSummaryChanged reports whether the weather condition changed, as
+// opposed to just readings such as the temperature. The first weather
+// reading counts as a change.
+func (c Change) WeatherSummaryChanged() bool {
+	cur := c.Current.Weather
+	if cur == nil {
+		return false
+	}
+	if c.Previous == nil || c.Previous.Weather == nil {
+		return true
+	}
+	prev := c.Previous.Weather
+	return cur.Summary != prev.Summary || cur.WeatherCode != prev.WeatherCode
+}
+
+// NewAlerts returns the weather alerts in Current that were not in Previous
+func (c Change) NewAlerts() []WeatherAlert {
+	cur, _ := c.Current.Feeds["alerts"].([]WeatherAlert)
+	var prev []WeatherAlert
+	if c.Previous != nil {
+		prev, _ = c.Previous.Feeds["alerts"].([]WeatherAlert)
+	}
+	var issued []WeatherAlert
+	for _, a := range cur {
+		if !slices.Contains(prev, a) {
+			issued = append(issued, a)
+		}
+	}
+	return issued
+}
+
+// ChangeFilter selects the changes a watcher is notified of
+type ChangeFilter func(Change) bool
+
+// OnFeedChange matches changes to any of the named feeds
+func OnFeedChange(feeds ...string) ChangeFilter {
+	return func(c Change) bool {
+		return slices.ContainsFunc(feeds, c.Changed)
+	}
+}
+
+// OnWeatherSummaryChange matches changes of the weather condition
+func OnWeatherSummaryChange() ChangeFilter {
+	return Change.WeatherSummaryChanged
+}
+
+// OnNewAlerts matches refreshes that issued new weather alerts
+func OnNewAlerts() ChangeFilter {
+	return func(c Change) bool {
+		return len(c.NewAlerts()) > 0
+	}
+}
+
+// diffSnapshots returns the sorted names of the feeds whose data differs
+// between prev and cur; every feed in cur differs from a nil prev
+func diffSnapshots(prev, cur *Snapshot) []string {
+	var changed []string
+	if prev == nil {
+		prev = &Snapshot{}
+	}
+	if !reflect.DeepEqual(prev.Weather, cur.Weather) {
+		changed = append(changed, "weather")
+	}
+	for name, v := range cur.Feeds {
+		if old, ok := prev.Feeds[name]; !ok || !reflect.DeepEqual(old, v) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range prev.Feeds {
+		if _, ok := cur.Feeds[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// Watch returns a channel receiving the Scheduler's snapshot changes that
+// match any of filters, or every change if none are given, and a function
+// that ends the watch and closes the channel. Refreshes that change no data
+// are not reported. Like Subscribe, a watcher more than 16 changes behind
+// misses changes. Filters run inside the Scheduler and must not call it.
+func (s *Scheduler) Watch(filters ...ChangeFilter) (<-chan Change, func()) {
+	ch := make(chan Change, subscriberBuffer)
+	s.subsMu.Lock()
+	s.watchers[ch] = filters
+	s.subsMu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.subsMu.Lock()
+			delete(s.watchers, ch)
+			s.subsMu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// publishChange sends a change to every watcher whose filters match it
+func (s *Scheduler) publishChange(c Change) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for ch, filters := range s.watchers {
+		if len(filters) > 0 && !slices.ContainsFunc(filters, func(f ChangeFilter) bool { return f(c) }) {
+			continue
+		}
+		select {
+		case ch <- c:
+		default:
+		}
+	}
+}
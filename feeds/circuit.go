@@ -0,0 +1,206 @@
+package feeds
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Circuit breaker defaults
+const (
+	defaultCircuitThreshold = 5
+	defaultCircuitCooldown  = 30 * time.Second
+)
+
+// CircuitState is the state of an upstream's circuit breaker
+type CircuitState int
+
+// Circuit breaker states
+const (
+	CircuitClosed   CircuitState = iota // requests flow normally
+	CircuitOpen                         // requests fail fast with ErrCircuitOpen
+	CircuitHalfOpen                     // one trial request is let through
+)
+
+// String returns the state name
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the state as its name
+func (s CircuitState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// WithCircuitBreaker sets the per-upstream circuit breaker: after threshold
+// consecutive failed requests (5xx, network errors or timeouts, after retries)
+// requests to that host fail fast with ErrCircuitOpen until cooldown has
+// passed, then a single trial request decides whether to close the circuit
+// again. The default is 5 failures and a 30s cooldown; a threshold of zero
+// or less disables the breaker.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.circuitThreshold = threshold
+		c.circuitCooldown = cooldown
+	}
+}
+
+// WithCircuitStateHook registers fn to be called whenever an upstream's
+// circuit changes state. host is the upstream host name, e.g.
+// api.open-meteo.com. fn runs synchronously on the requesting goroutine.
+func WithCircuitStateHook(fn func(host string, from, to CircuitState)) Option {
+	return func(c *Client) {
+		c.circuitHook = fn
+	}
+}
+
+// circuitBreaker tracks the health of one upstream host
+type circuitBreaker struct {
+	host      string
+	threshold int
+	cooldown  time.Duration
+	hook      func(host string, from, to CircuitState)
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trial    bool // a half-open trial request is in flight
+}
+
+// allow reports whether a request may proceed, returning ErrCircuitOpen if not
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	from := b.state
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.cooldown {
+		b.state, b.trial = CircuitHalfOpen, false
+	}
+	var err error
+	switch {
+	case b.state == CircuitOpen, b.state == CircuitHalfOpen && b.trial:
+		err = ErrCircuitOpen
+	case b.state == CircuitHalfOpen:
+		b.trial = true
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return err
+}
+
+// record updates the breaker with the outcome of an allowed request
+func (b *circuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	from := b.state
+	b.trial = false
+	if failed {
+		b.failures++
+		if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+			b.state, b.openedAt = CircuitOpen, time.Now()
+		}
+	} else {
+		b.failures = 0
+		b.state = CircuitClosed
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// abandon releases a half-open trial whose outcome says nothing about the
+// upstream, such as a request cancelled by the caller
+func (b *circuitBreaker) abandon() {
+	b.mu.Lock()
+	b.trial = false
+	b.mu.Unlock()
+}
+
+// current returns the breaker state
+func (b *circuitBreaker) current() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// refusing reports whether the breaker is open and still cooling down, so a
+// request now would fail with ErrCircuitOpen
+func (b *circuitBreaker) refusing() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == CircuitOpen && time.Since(b.openedAt) < b.cooldown
+}
+
+// circuitOpen reports whether the circuit of target's host is refusing
+// requests. Other upstreams contacted through the Client are not consulted.
+func (c *Client) circuitOpen(target string) bool {
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	c.circuitMu.Lock()
+	b, ok := c.circuits[u.Host]
+	c.circuitMu.Unlock()
+	return ok && b.refusing()
+}
+
+// notify calls the state hook if the state changed
+func (b *circuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.hook != nil {
+		b.hook(b.host, from, to)
+	}
+}
+
+// breakerFor returns the circuit breaker for target's host, or nil if disabled
+func (c *Client) breakerFor(target string) *circuitBreaker {
+	if c.circuitThreshold <= 0 {
+		return nil
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil
+	}
+
+	c.circuitMu.Lock()
+	defer c.circuitMu.Unlock()
+	b, ok := c.circuits[u.Host]
+	if !ok {
+		b = &circuitBreaker{
+			host:      u.Host,
+			threshold: c.circuitThreshold,
+			cooldown:  c.circuitCooldown,
+			hook:      c.circuitHook,
+		}
+		if c.metrics != nil {
+			c.metrics.ObserveCircuit(u.Host, CircuitClosed)
+		}
+		b.hook = func(host string, from, to CircuitState) {
+			if c.metrics != nil {
+				c.metrics.ObserveCircuit(host, to)
+			}
+			c.log().Warn("upstream circuit changed state", "host", host, "from", from, "to", to)
+			if c.circuitHook != nil {
+				c.circuitHook(host, from, to)
+			}
+		}
+		c.circuits[u.Host] = b
+	}
+	return b
+}
+
+// isUpstreamFailure reports whether a failed request counts against the
+// upstream's circuit: transient errors and timeouts do, client errors don't
+func isUpstreamFailure(transient bool, err error) bool {
+	return transient || errors.Is(err, ErrUpstreamTimeout)
+}
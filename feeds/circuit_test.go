@@ -0,0 +1,36 @@
+package feeds
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProviderCircuitOpenPerHost(t *testing.T) {
+	c := NewClient(WithCircuitBreaker(1, time.Minute))
+	openMeteo := &OpenMeteoProvider{client: c}
+	nws := &NWSProvider{client: c, points: make(map[Coordinates]nwsPoint)}
+	eccc := &ECCCProvider{client: c}
+	fallback := NewFallbackProvider(0, openMeteo, nws, eccc)
+
+	// trip an unrelated upstream and then Open-Meteo's
+	c.breakerFor(c.eiaBaseURL + "/v2").record(true)
+	if tripped(openMeteo) || tripped(nws) || tripped(eccc) {
+		t.Error("open EIA circuit reported as a weather provider's")
+	}
+	c.breakerFor(c.forecastBaseURL + "/v1/forecast").record(true)
+	if !tripped(openMeteo) {
+		t.Error("open Open-Meteo circuit not reported")
+	}
+	if tripped(nws) || tripped(eccc) {
+		t.Error("open Open-Meteo circuit reported by NWS or ECCC")
+	}
+	if tripped(fallback) {
+		t.Error("FallbackProvider reported open with NWS and ECCC available")
+	}
+
+	c.breakerFor(c.nwsBaseURL + "/points/40.7,-74").record(true)
+	c.breakerFor(c.ecccBaseURL + "/citypage_weather/xml/ON/s0000430_e.xml").record(true)
+	if !tripped(nws) || !tripped(eccc) || !tripped(fallback) {
+		t.Error("open NWS and ECCC circuits not reported")
+	}
+}
@@ -0,0 +1,96 @@
+package feeds
+
+import (
+	"context"
+	"strings"
+)
+
+// North America city coordinates by country (major metros), keyed by
+// lower-case city name. Guarded by coordinatesMu.
+var naCityCoordinates = map[string]map[string]Coordinates{
+	"US": {
+		"new york":      {Lat: 40.7128, Lon: -74.0060},
+		"los angeles":   {Lat: 34.0522, Lon: -118.2437},
+		"chicago":       {Lat: 41.8781, Lon: -87.6298},
+		"houston":       {Lat: 29.7604, Lon: -95.3698},
+		"phoenix":       {Lat: 33.4484, Lon: -112.0740},
+		"philadelphia":  {Lat: 39.9526, Lon: -75.1652},
+		"san antonio":   {Lat: 29.4241, Lon: -98.4936},
+		"san diego":     {Lat: 32.7157, Lon: -117.1611},
+		"dallas":        {Lat: 32.7767, Lon: -96.7970},
+		"austin":        {Lat: 30.2672, Lon: -97.7431},
+		"san francisco": {Lat: 37.7749, Lon: -122.4194},
+		"seattle":       {Lat: 47.6062, Lon: -122.3321},
+		"denver":        {Lat: 39.7392, Lon: -104.9903},
+		"washington":    {Lat: 38.9072, Lon: -77.0369},
+		"boston":        {Lat: 42.3601, Lon: -71.0589},
+		"atlanta":       {Lat: 33.7490, Lon: -84.3880},
+		"miami":         {Lat: 25.7617, Lon: -80.1918},
+		"minneapolis":   {Lat: 44.9778, Lon: -93.2650},
+		"detroit":       {Lat: 42.3314, Lon: -83.0458},
+		"las vegas":     {Lat: 36.1699, Lon: -115.1398},
+		"portland":      {Lat: 45.5152, Lon: -122.6784},
+		"anchorage":     {Lat: 61.2181, Lon: -149.9003},
+		"honolulu":      {Lat: 21.3069, Lon: -157.8583},
+	},
+	"CA": {
+		"toronto":     {Lat: 43.6532, Lon: -79.3832},
+		"montreal":    {Lat: 45.5017, Lon: -73.5673},
+		"vancouver":   {Lat: 49.2827, Lon: -123.1207},
+		"calgary":     {Lat: 51.0447, Lon: -114.0719},
+		"edmonton":    {Lat: 53.5461, Lon: -113.4938},
+		"ottawa":      {Lat: 45.4215, Lon: -75.6972},
+		"winnipeg":    {Lat: 49.8951, Lon: -97.1384},
+		"quebec city": {Lat: 46.8139, Lon: -71.2080},
+		"halifax":     {Lat: 44.6488, Lon: -63.5752},
+	},
+	"MX": {
+		"mexico city": {Lat: 19.4326, Lon: -99.1332},
+		"guadalajara": {Lat: 20.6597, Lon: -103.3496},
+		"monterrey":   {Lat: 25.6866, Lon: -100.3161},
+		"puebla":      {Lat: 19.0414, Lon: -98.2063},
+		"tijuana":     {Lat: 32.5149, Lon: -117.0382},
+		"cancun":      {Lat: 21.1619, Lon: -86.8515},
+		"merida":      {Lat: 20.9674, Lon: -89.5926},
+	},
+}
+
+// cityKey normalises a city name for registry lookups
+func cityKey(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
+
+// lookupCity returns the registered coordinates for a city in a country
+func lookupCity(country, city string) (Coordinates, bool) {
+	coordinatesMu.RLock()
+	defer coordinatesMu.RUnlock()
+
+	coords, ok := naCityCoordinates[country][cityKey(city)]
+	return coords, ok
+}
+
+// RegisterCity adds or replaces the coordinates used for a city in a country
+func RegisterCity(country, city string, coords Coordinates) {
+	coordinatesMu.Lock()
+	defer coordinatesMu.Unlock()
+
+	if naCityCoordinates[country] == nil {
+		naCityCoordinates[country] = make(map[string]Coordinates)
+	}
+	naCityCoordinates[country][cityKey(city)] = coords
+}
+
+// FetchWeatherForCity fetches weather for a city using the default Client
+func FetchWeatherForCity(country, city string) (*WeatherData, error) {
+	return defaultClient.FetchWeatherForCity(country, city)
+}
+
+// FetchWeatherForCity fetches weather for a city in a country. Cities missing
+// from the registry fall back to the country's default coordinates.
+func (c *Client) FetchWeatherForCity(country, city string) (*WeatherData, error) {
+	ctx := context.Background()
+	if coords, ok := lookupCity(country, city); ok {
+		return c.fetchCurrent(ctx, coords, SourceCityMap)
+	}
+	return c.FetchWeatherContext(ctx, country)
+}
@@ -0,0 +1,458 @@
+package feeds
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMaxResponseBytes caps upstream response bodies unless overridden
+const defaultMaxResponseBytes = 10 << 20 // 10MB
+
+// defaultUserAgent identifies upstream requests; api.weather.gov rejects requests without one
+const defaultUserAgent = "reef-na (https://github.com/cb-squidstack/reef-na)"
+
+// Client fetches feed data from upstream APIs
+type Client struct {
+	httpClient          *http.Client
+	forecastBaseURL     string
+	archiveBaseURL      string
+	geocodingBaseURL    string
+	airQualityBaseURL   string
+	marineBaseURL       string
+	nwsBaseURL          string
+	ecccBaseURL         string
+	googlePollenBaseURL string
+	fxBaseURL           string
+	currencies          []string
+	marketsBaseURL      string
+	usgsBaseURL         string
+	nhcBaseURL          string
+	nifcBaseURL         string
+	cwfisBaseURL        string
+	coopsBaseURL        string
+	iwlsBaseURL         string
+	usgsWaterBaseURL    string
+	geoMetBaseURL       string
+	faaBaseURL          string
+	eiaBaseURL          string
+	creBaseURL          string
+	espnBaseURL         string
+	userAgent           string
+	maxResponseBytes    int64
+	noFallback          bool
+	tempPrecision       int // decimals to round temperatures to; negative keeps full precision
+	extraParams         url.Values
+	transforms          []func(*WeatherData)
+	timeFormat          TimeFormat
+	units               UnitSystem
+	precipitationUnit   PrecipitationUnit
+	windUnit            WindUnit
+	language            Language
+	uvIndex             bool
+	provider            WeatherProvider
+	newsProvider        NewsProvider
+	marketProvider      MarketProvider
+	fuelProviders       map[string]FuelPriceProvider
+	retry               RetryPolicy
+	circuitThreshold    int
+	circuitCooldown     time.Duration
+	circuitHook         func(host string, from, to CircuitState)
+	defaultRateLimit    *rateLimit
+	hostRateLimits      map[string]rateLimit
+	batchConcurrency    int
+	metrics             MetricsRecorder
+	tracer              Tracer
+	logger              *slog.Logger
+
+	cache        Cache
+	cacheTTL     time.Duration
+	staleWindow  time.Duration
+	staleOnError bool
+	revalidateMu sync.Mutex
+	revalidating map[string]bool
+
+	circuitMu sync.Mutex
+	circuits  map[string]*circuitBreaker
+
+	limiterMu sync.Mutex
+	limiters  map[string]*tokenBucket
+
+	keyMu           sync.RWMutex // API keys can be rotated while in use
+	googlePollenKey string
+	eiaKey          string
+
+	statusMu    sync.Mutex
+	lastErr     error
+	lastErrAt   time.Time
+	lastSuccess time.Time
+	lastStaleAt time.Time
+
+	geocodeMu    sync.Mutex
+	geocodeTTL   time.Duration
+	geocodeCache map[string]geocodeEntry
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for upstream requests, e.g. to
+// route through a proxy or change the timeout
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithBaseURL points every Open-Meteo endpoint (forecast, archive, geocoding, air quality, marine) at the
+// given scheme and host, such as an httptest server or a caching proxy.
+// Endpoint paths like /v1/forecast are appended to it.
+func WithBaseURL(base string) Option {
+	return func(c *Client) {
+		base = strings.TrimSuffix(base, "/")
+		c.forecastBaseURL = base
+		c.archiveBaseURL = base
+		c.geocodingBaseURL = base
+		c.airQualityBaseURL = base
+		c.marineBaseURL = base
+	}
+}
+
+// WithUserAgent sets the User-Agent sent upstream. The National Weather
+// Service asks for an application name and contact address.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithMaxResponseBytes caps how many bytes are read from an upstream response body
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *Client) {
+		c.maxResponseBytes = n
+	}
+}
+
+// WithNoFallback makes fetches for countries missing from the coordinate map
+// fail with ErrUnknownCountry instead of silently using New York. Callers
+// must then only pass supported country codes.
+func WithNoFallback() Option {
+	return func(c *Client) {
+		c.noFallback = true
+	}
+}
+
+// WithTemperaturePrecision rounds every temperature in fetched results to the
+// given number of decimals (0 for whole degrees). Open-Meteo has no rounding
+// parameter, so this is applied when decoding. Full precision is the default.
+func WithTemperaturePrecision(decimals int) Option {
+	return func(c *Client) {
+		c.tempPrecision = decimals
+	}
+}
+
+// WithExtraParam adds an arbitrary query parameter to every upstream request,
+// so newer Open-Meteo parameters can be used before this package wraps them.
+// It may be repeated, including for the same key. Values are sent verbatim
+// (URL-escaped); parameters the Client sets itself take precedence.
+func WithExtraParam(key, value string) Option {
+	return func(c *Client) {
+		c.extraParams.Add(key, value)
+	}
+}
+
+// WithResultTransform registers a function that may adjust or enrich each
+// successful WeatherData result (e.g. a local sensor bias correction) before
+// it is returned. Transforms run in the order they were added, after the
+// Client's own processing such as temperature rounding.
+func WithResultTransform(fn func(*WeatherData)) Option {
+	return func(c *Client) {
+		c.transforms = append(c.transforms, fn)
+	}
+}
+
+// WithTimeFormat selects the timestamp encoding requested from Open-Meteo.
+// Both forms decode to the same time.Time values in the location's time zone;
+// TimeFormatUnixTime avoids parsing local wall-clock strings. The default is
+// TimeFormatISO8601 with timezone=auto.
+func WithTimeFormat(format TimeFormat) Option {
+	return func(c *Client) {
+		c.timeFormat = format
+	}
+}
+
+// NewClient creates a Client with the given options applied over the defaults
+func NewClient(options ...Option) *Client {
+	c := &Client{
+		httpClient:          &http.Client{Timeout: 10 * time.Second},
+		forecastBaseURL:     defaultForecastBaseURL,
+		archiveBaseURL:      defaultArchiveBaseURL,
+		geocodingBaseURL:    defaultGeocodingBaseURL,
+		airQualityBaseURL:   defaultAirQualityBaseURL,
+		marineBaseURL:       defaultMarineBaseURL,
+		nwsBaseURL:          defaultNWSBaseURL,
+		ecccBaseURL:         defaultECCCBaseURL,
+		googlePollenBaseURL: defaultGooglePollenBaseURL,
+		fxBaseURL:           defaultFXBaseURL,
+		currencies:          defaultCurrencies,
+		marketsBaseURL:      defaultMarketsBaseURL,
+		usgsBaseURL:         defaultUSGSBaseURL,
+		nhcBaseURL:          defaultNHCBaseURL,
+		nifcBaseURL:         defaultNIFCBaseURL,
+		cwfisBaseURL:        defaultCWFISBaseURL,
+		coopsBaseURL:        defaultCOOPSBaseURL,
+		iwlsBaseURL:         defaultIWLSBaseURL,
+		usgsWaterBaseURL:    defaultUSGSWaterBaseURL,
+		geoMetBaseURL:       defaultGeoMetBaseURL,
+		faaBaseURL:          defaultFAABaseURL,
+		eiaBaseURL:          defaultEIABaseURL,
+		creBaseURL:          defaultCREBaseURL,
+		espnBaseURL:         defaultESPNBaseURL,
+		userAgent:           defaultUserAgent,
+		geocodeTTL:          defaultGeocodeTTL,
+		geocodeCache:        make(map[string]geocodeEntry),
+		cacheTTL:            defaultCacheTTL,
+		revalidating:        make(map[string]bool),
+		maxResponseBytes:    defaultMaxResponseBytes,
+		retry:               defaultRetryPolicy,
+		circuitThreshold:    defaultCircuitThreshold,
+		circuitCooldown:     defaultCircuitCooldown,
+		circuits:            make(map[string]*circuitBreaker),
+		hostRateLimits:      make(map[string]rateLimit),
+		limiters:            make(map[string]*tokenBucket),
+		batchConcurrency:    defaultBatchConcurrency,
+		tempPrecision:       -1,
+		extraParams:         url.Values{},
+		timeFormat:          TimeFormatISO8601,
+		units:               UnitsMetric,
+	}
+	for _, fn := range options {
+		fn(c)
+	}
+	if c.cache == nil {
+		c.cache = NewMemoryCache(defaultCacheMaxEntries)
+	}
+	if c.newsProvider == nil {
+		c.newsProvider = &RSSNewsProvider{client: c}
+	}
+	if c.marketProvider == nil {
+		c.marketProvider = &YahooMarketProvider{client: c}
+	}
+	if c.provider == nil {
+		c.provider = &OpenMeteoProvider{client: c}
+	}
+	return c
+}
+
+// defaultClient backs the package-level fetch functions
+var defaultClient = NewClient()
+
+// getOpenMeteo performs a GET request against an Open-Meteo weather API. The
+// configured time format and extra parameters are added unless q already sets them.
+func (c *Client) getOpenMeteo(ctx context.Context, endpoint string, q url.Values, v any) error {
+	if c.timeFormat != "" && c.timeFormat != TimeFormatISO8601 {
+		q.Set("timeformat", string(c.timeFormat))
+	}
+	for k, vals := range c.extraParams {
+		if _, ok := q[k]; !ok {
+			q[k] = append([]string(nil), vals...)
+		}
+	}
+	return c.getJSON(ctx, endpoint, q, v)
+}
+
+// getJSON performs a GET request against an upstream API and decodes the JSON body into v
+func (c *Client) getJSON(ctx context.Context, endpoint string, q url.Values, v any) error {
+	target := requestURL(endpoint, q)
+	body, err := c.cachedGet(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	// Parse response
+	if err := json.Unmarshal(body, v); err != nil {
+		c.evict(target)
+		return fmt.Errorf("failed to parse weather response: %w", withKind(ErrDecode, err))
+	}
+	return nil
+}
+
+// requestURL appends the encoded query to endpoint
+func requestURL(endpoint string, q url.Values) string {
+	if len(q) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + q.Encode()
+}
+
+// fetchOnce performs a single GET request against an upstream API and returns
+// the body, enforcing the Client's maximum response size. When prev carries
+// validators the request is conditional, and a 304 response reuses prev's body.
+// retry reports whether the failure is transient (a 5xx status or a network
+// error other than a timeout).
+func (c *Client) fetchOnce(ctx context.Context, target string, prev *CacheEntry) (entry CacheEntry, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return CacheEntry{}, false, fmt.Errorf("failed to build weather request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if prev != nil {
+		if prev.ETag != "" {
+			req.Header.Set("If-None-Match", prev.ETag)
+		}
+		if prev.LastModified != "" {
+			req.Header.Set("If-Modified-Since", prev.LastModified)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Keep API keys in the query out of error messages and logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactURL(urlErr.URL)
+		}
+		err = classifyTransportError(ctx, err)
+		return CacheEntry{}, isTransient(ctx, err), fmt.Errorf("weather API call failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	entry = CacheEntry{
+		Fetched:      time.Now(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if resp.StatusCode == http.StatusNotModified && prev != nil {
+		entry.Body = prev.Body
+		entry.ETag = cmp.Or(entry.ETag, prev.ETag)
+		entry.LastModified = cmp.Or(entry.LastModified, prev.LastModified)
+		return entry, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return CacheEntry{}, resp.StatusCode >= 500, &UpstreamStatusError{Code: resp.StatusCode}
+	}
+
+	// Read one byte past the limit so an oversized body can be detected
+	entry.Body, err = io.ReadAll(io.LimitReader(resp.Body, c.maxResponseBytes+1))
+	if err != nil {
+		err = classifyTransportError(ctx, err)
+		return CacheEntry{}, isTransient(ctx, err), fmt.Errorf("failed to read weather response: %w", err)
+	}
+	if int64(len(entry.Body)) > c.maxResponseBytes {
+		return CacheEntry{}, false, fmt.Errorf("%w (limit %d bytes)", ErrResponseTooLarge, c.maxResponseBytes)
+	}
+	return entry, false, nil
+}
+
+// getXML performs a GET request against an upstream API and decodes the XML body into v
+func (c *Client) getXML(ctx context.Context, endpoint string, q url.Values, v any) error {
+	target := requestURL(endpoint, q)
+	body, err := c.cachedGet(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	dec.CharsetReader = latin1Reader
+	if err := dec.Decode(v); err != nil {
+		c.evict(target)
+		return fmt.Errorf("failed to parse weather response: %w", withKind(ErrDecode, err))
+	}
+	return nil
+}
+
+// latin1Reader lets the XML decoder read ISO-8859-1 documents, which some
+// government feeds still publish; other non-UTF-8 charsets are rejected
+func latin1Reader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "latin-1":
+		b, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		runes := make([]rune, len(b))
+		for i, ch := range b {
+			runes[i] = rune(ch)
+		}
+		return strings.NewReader(string(runes)), nil
+	default:
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+}
+
+// classifyTransportError separates the caller's context ending, reported as
+// the wrapped context error (context.DeadlineExceeded or context.Canceled),
+// from the Client's own HTTP timeout, reported as ErrUpstreamTimeout
+func classifyTransportError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return contextError(fmt.Errorf("%w: %v", ctxErr, err))
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		// %v rather than %w: the transport error itself matches context.DeadlineExceeded
+		return fmt.Errorf("%w: %v", ErrUpstreamTimeout, err)
+	}
+	return err
+}
+
+// roundTemperature applies the configured temperature precision to v
+func (c *Client) roundTemperature(v float64) float64 {
+	if c.tempPrecision < 0 {
+		return v
+	}
+	return roundTo(v, c.tempPrecision)
+}
+
+// finishWeather applies temperature rounding, unit conversions and result transforms to w
+func (c *Client) finishWeather(w *WeatherData) {
+	if w.Provider == "" {
+		w.Provider = providerName(c.provider)
+	}
+	if w.Timezone == "" && (w.Latitude != 0 || w.Longitude != 0) {
+		w.Timezone = TimezoneAt(Coordinates{Lat: w.Latitude, Lon: w.Longitude}).String()
+	}
+	if c.units == UnitsImperial {
+		// Convert before rounding so °F is not rounded twice
+		imp := w.ToImperial()
+		imp.TemperatureF = c.roundTemperature(imp.TemperatureF)
+		imp.FeelsLikeF = c.roundTemperature(imp.FeelsLikeF)
+		w.Imperial = &imp
+	}
+	if c.precipitationUnit != "" {
+		p := w.PrecipitationIn(c.precipitationUnit)
+		w.Precipitation = &p
+	}
+	if c.windUnit != "" {
+		wind := w.WindIn(c.windUnit)
+		w.Wind = &wind
+	}
+	w.TemperatureC = c.roundTemperature(w.TemperatureC)
+	w.FeelsLikeC = c.roundTemperature(w.FeelsLikeC)
+	w.Summary = c.summary(w.WeatherCode, w.Summary)
+	w.ConditionSeverity = ClassifyConditions(w.WeatherCode, w.PrecipitationMM)
+	at := w.ObservedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	w.Icon = w.iconAt(at)
+	c.applyTransforms(w)
+}
+
+// applyTransforms runs the registered result transforms on w in order
+func (c *Client) applyTransforms(w *WeatherData) {
+	for _, fn := range c.transforms {
+		fn(w)
+	}
+}
@@ -0,0 +1,43 @@
+package feeds
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+// countingTransport counts the requests it passes on
+type countingTransport struct {
+	next http.RoundTripper
+	n    atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.n.Add(1)
+	return t.next.RoundTrip(r)
+}
+
+func TestFetchWeatherWithMockServer(t *testing.T) {
+	srv := currentServer(t)
+	transport := &countingTransport{next: srv.Client().Transport}
+	c := NewClient(WithBaseURL(srv.URL+"/"), WithHTTPClient(&http.Client{Transport: transport}))
+
+	w, err := c.FetchWeather("US")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := transport.n.Load(); n != 1 {
+		t.Errorf("made %d requests through WithHTTPClient, want 1", n)
+	}
+	if w.TemperatureC != 21.5 || w.FeelsLikeC != 22.1 || w.WeatherCode != 3 || w.Summary != "Overcast" {
+		t.Errorf("got %v°C (feels %v°C), code %d %q, want 21.5°C (feels 22.1°C), code 3 \"Overcast\"",
+			w.TemperatureC, w.FeelsLikeC, w.WeatherCode, w.Summary)
+	}
+	if w.HumidityPct != 64 || w.WindSpeedKmh != 14.8 || w.WindGustsKmh != 31.3 || w.PressureHPa != 1012.4 {
+		t.Errorf("got %v%%, wind %v gusting %v km/h, %v hPa, want 64%%, wind 14.8 gusting 31.3 km/h, 1012.4 hPa",
+			w.HumidityPct, w.WindSpeedKmh, w.WindGustsKmh, w.PressureHPa)
+	}
+	if w.Source != SourceCountryMap || w.Provider != "open-meteo" || w.Timezone != "America/New_York" {
+		t.Errorf("got source %q, provider %q, timezone %q", w.Source, w.Provider, w.Timezone)
+	}
+}
@@ -0,0 +1,58 @@
+package feeds
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// countriesJSON lists the built-in countries and the city used for each
+//
+//go:embed data/countries.json
+var countriesJSON []byte
+
+// placeEntry is one record of the embedded country and region registries
+type placeEntry struct {
+	Code string  `json:"code"` // ISO 3166 code: alpha-2 country or subdivision suffix
+	Name string  `json:"name"`
+	City string  `json:"city"`
+	Lat  float64 `json:"lat"`
+	Lon  float64 `json:"lon"`
+	TZ   string  `json:"tz"` // IANA time zone of the city
+}
+
+// loadCountries parses the country registry, rejecting malformed codes,
+// duplicates and out-of-range coordinates
+func loadCountries(data []byte) (map[string]Coordinates, error) {
+	var entries []placeEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse country registry: %w", err)
+	}
+
+	countries := make(map[string]Coordinates, len(entries))
+	for _, e := range entries {
+		if len(e.Code) != 2 || strings.ToUpper(e.Code) != e.Code {
+			return nil, fmt.Errorf("country registry: invalid code %q", e.Code)
+		}
+		if _, dup := countries[e.Code]; dup {
+			return nil, fmt.Errorf("country registry: duplicate code %q", e.Code)
+		}
+		coords := Coordinates{Lat: e.Lat, Lon: e.Lon}
+		if err := coords.Validate(); err != nil {
+			return nil, fmt.Errorf("country registry: %s (%s): %w", e.Code, e.City, err)
+		}
+		countries[e.Code] = coords
+	}
+	return countries, nil
+}
+
+// mustLoadCountries loads the embedded registry; it is checked at startup so
+// a bad edit to the data file fails immediately
+func mustLoadCountries() map[string]Coordinates {
+	countries, err := loadCountries(countriesJSON)
+	if err != nil {
+		panic(err)
+	}
+	return countries
+}
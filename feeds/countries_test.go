@@ -0,0 +1,78 @@
+package feeds
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestCountryRegistryEntries(t *testing.T) {
+	var entries []placeEntry
+	if err := json.Unmarshal(countriesJSON, &entries); err != nil {
+		t.Fatal(err)
+	}
+	code := regexp.MustCompile(`^[A-Z]{2}$`)
+	for _, e := range entries {
+		if !code.MatchString(e.Code) {
+			t.Errorf("%q: code is not an upper-case ISO 3166 alpha-2 code", e.Code)
+		}
+		if e.Name == "" || e.City == "" {
+			t.Errorf("%s: missing name or city", e.Code)
+		}
+		// North America, Greenland and Bermuda included
+		if e.Lat < 5 || e.Lat > 84 || e.Lon < -170 || e.Lon > -10 {
+			t.Errorf("%s: %s at %v,%v is outside North America", e.Code, e.City, e.Lat, e.Lon)
+		}
+		coords, ok := lookupCountry(e.Code)
+		if !ok || coords != (Coordinates{Lat: e.Lat, Lon: e.Lon}) {
+			t.Errorf("%s: registered at %v (%v), want %v,%v", e.Code, coords, ok, e.Lat, e.Lon)
+		}
+		if _, err := time.LoadLocation(e.TZ); err != nil {
+			t.Errorf("%s: time zone %q: %v", e.Code, e.TZ, err)
+		} else if got := TimezoneAt(coords).String(); got != e.TZ {
+			t.Errorf("%s: TimezoneAt(%v) = %s, want %s", e.Code, coords, got, e.TZ)
+		}
+	}
+	if got, want := len(SupportedCountries()), len(entries); got < want {
+		t.Errorf("SupportedCountries has %d codes, want at least %d", got, want)
+	}
+}
+
+func TestCountryLocations(t *testing.T) {
+	tests := []struct {
+		code     string
+		lat, lon float64
+		tz       string
+	}{
+		{"GT", 14.6349, -90.5069, "America/Guatemala"}, // Central America
+		{"jm", 17.9712, -76.7936, "America/Jamaica"},   // the Caribbean, looked up in lower case
+	}
+	for _, tt := range tests {
+		loc, err := CountryLocation(tt.code)
+		if err != nil {
+			t.Fatalf("CountryLocation(%q): %v", tt.code, err)
+		}
+		if loc.Lat != tt.lat || loc.Lon != tt.lon {
+			t.Errorf("CountryLocation(%q) = %v,%v, want %v,%v", tt.code, loc.Lat, loc.Lon, tt.lat, tt.lon)
+		}
+		if got := TimezoneAt(loc.Coordinates).String(); got != tt.tz {
+			t.Errorf("TimezoneAt(%q) = %s, want %s", tt.code, got, tt.tz)
+		}
+	}
+}
+
+func TestLoadCountriesRejects(t *testing.T) {
+	tests := map[string]string{
+		"invalid JSON":     `[{"code":`,
+		"lower-case code":  `[{"code":"us","lat":40,"lon":-74}]`,
+		"three-letter":     `[{"code":"USA","lat":40,"lon":-74}]`,
+		"duplicate code":   `[{"code":"US","lat":40,"lon":-74},{"code":"US","lat":41,"lon":-74}]`,
+		"latitude too big": `[{"code":"US","lat":91,"lon":-74}]`,
+	}
+	for name, data := range tests {
+		if _, err := loadCountries([]byte(data)); err == nil {
+			t.Errorf("%s: loadCountries accepted %s", name, data)
+		}
+	}
+}
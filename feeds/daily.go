@@ -0,0 +1,130 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Daily variables requested from Open-Meteo. The archive API has no
+// precipitation probability, so it uses the shorter list.
+const (
+	archiveDailyVariables  = "temperature_2m_max,temperature_2m_min,weather_code"
+	forecastDailyVariables = archiveDailyVariables + ",precipitation_probability_max"
+)
+
+// DailyForecast represents the aggregated weather for a single day
+type DailyForecast struct {
+	Date                     time.Time `json:"date"`
+	Summary                  string    `json:"summary"`
+	WeatherCode              int       `json:"weatherCode"`
+	HighC                    float64   `json:"highC"`
+	LowC                     float64   `json:"lowC"`
+	PrecipitationProbability *int      `json:"precipitationProbability,omitempty"` // percent; forecasts only
+}
+
+// ForecastData represents a run of daily forecasts for one location
+type ForecastData struct {
+	Latitude  float64         `json:"latitude"`
+	Longitude float64         `json:"longitude"`
+	Timezone  string          `json:"timezone"`
+	Days      []DailyForecast `json:"days"`
+}
+
+// OpenMeteoDailyResponse represents a daily-aggregate response from Open-Meteo
+type OpenMeteoDailyResponse struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	Timezone         string  `json:"timezone"`
+	UTCOffsetSeconds int     `json:"utc_offset_seconds"`
+	Daily            struct {
+		Time                        []Timestamp `json:"time"`
+		TemperatureMax              []float64   `json:"temperature_2m_max"`
+		TemperatureMin              []float64   `json:"temperature_2m_min"`
+		WeatherCode                 []int       `json:"weather_code"`
+		PrecipitationProbabilityMax []int       `json:"precipitation_probability_max"`
+	} `json:"daily"`
+}
+
+// fetchDailyRange fetches daily aggregates between start and end (inclusive)
+// from an Open-Meteo endpoint that accepts start_date/end_date
+func (c *Client) fetchDailyRange(ctx context.Context, endpoint, variables, country string, start, end time.Time) ([]DailyForecast, error) {
+	coords, _, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+
+	q := coords.query()
+	q.Set("start_date", start.Format(dateLayout))
+	q.Set("end_date", end.Format(dateLayout))
+	q.Set("daily", variables)
+
+	fd, err := c.getDaily(ctx, endpoint, q)
+	if err != nil {
+		return nil, err
+	}
+	return fd.Days, nil
+}
+
+// getDaily requests and converts a daily-aggregate response
+func (c *Client) getDaily(ctx context.Context, endpoint string, q url.Values) (*ForecastData, error) {
+	q.Set("timezone", "auto")
+
+	var apiResp OpenMeteoDailyResponse
+	if err := c.getOpenMeteo(ctx, endpoint, q, &apiResp); err != nil {
+		return nil, err
+	}
+	days, err := apiResp.toDailyForecasts()
+	if err != nil {
+		return nil, err
+	}
+	for i := range days {
+		days[i].HighC = c.roundTemperature(days[i].HighC)
+		days[i].LowC = c.roundTemperature(days[i].LowC)
+		days[i].Summary = c.summary(days[i].WeatherCode, days[i].Summary)
+	}
+
+	return &ForecastData{
+		Latitude:  apiResp.Latitude,
+		Longitude: apiResp.Longitude,
+		Timezone:  apiResp.Timezone,
+		Days:      days,
+	}, nil
+}
+
+// toDailyForecasts converts the parallel daily arrays into DailyForecast entries
+func (r *OpenMeteoDailyResponse) toDailyForecasts() ([]DailyForecast, error) {
+	d := r.Daily
+	n := len(d.Time)
+	if len(d.TemperatureMax) != n || len(d.TemperatureMin) != n || len(d.WeatherCode) != n {
+		return nil, withKind(ErrDecode, errors.New("daily weather response has mismatched array lengths"))
+	}
+	hasProbability := len(d.PrecipitationProbabilityMax) > 0
+	if hasProbability && len(d.PrecipitationProbabilityMax) != n {
+		return nil, withKind(ErrDecode, errors.New("daily weather response has mismatched array lengths"))
+	}
+
+	loc := apiLocation(r.Timezone, r.UTCOffsetSeconds)
+	days := make([]DailyForecast, 0, n)
+	for i := range n {
+		date, err := d.Time[i].In(loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse daily date: %w", err)
+		}
+		day := DailyForecast{
+			Date:        date,
+			Summary:     describeWeatherCode(d.WeatherCode[i]),
+			WeatherCode: d.WeatherCode[i],
+			HighC:       d.TemperatureMax[i],
+			LowC:        d.TemperatureMin[i],
+		}
+		if hasProbability {
+			p := d.PrecipitationProbabilityMax[i]
+			day.PrecipitationProbability = &p
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
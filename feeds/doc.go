@@ -0,0 +1,9 @@
+// Package feeds fetches North American regional data (weather, forecasts,
+// alerts, news, markets, transit and other location feeds) from public
+// upstream APIs.
+//
+// Package-level functions use a default Client; NewClient with Options
+// configures hosts, caching, retries and providers. Aggregator combines
+// feeds for a location into one Snapshot, and feeds registered with
+// RegisterFeed are included by every Aggregator.
+package feeds
@@ -0,0 +1,168 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultUSGSBaseURL is the US Geological Survey earthquake API host
+const defaultUSGSBaseURL = "https://earthquake.usgs.gov"
+
+// defaultEarthquakeWindow is how far back FetchEarthquakes looks unless overridden
+const defaultEarthquakeWindow = 24 * time.Hour
+
+// WithUSGSBaseURL points earthquake requests at another host
+func WithUSGSBaseURL(base string) Option {
+	return func(c *Client) {
+		c.usgsBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// Earthquake is an event from the USGS earthquake catalog
+type Earthquake struct {
+	ID         string    `json:"id"`
+	Magnitude  float64   `json:"magnitude"`
+	Place      string    `json:"place"` // e.g. "10 km SW of Ridgecrest, CA"
+	DepthKm    float64   `json:"depthKm"`
+	Time       time.Time `json:"time"`
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	DistanceKm float64   `json:"distanceKm,omitempty"` // from the query's Center, if any
+	Tsunami    bool      `json:"tsunami"`              // a tsunami message was issued
+	URL        string    `json:"url,omitempty"`
+}
+
+// BoundingBox is a latitude/longitude rectangle
+type BoundingBox struct {
+	MinLat, MinLon float64
+	MaxLat, MaxLon float64
+}
+
+// EarthquakeQuery filters FetchEarthquakes. Center with RadiusKm and Box are
+// alternatives; with neither, earthquakes worldwide are returned.
+type EarthquakeQuery struct {
+	MinMagnitude float64
+	Window       time.Duration // how far back to look; default 24h
+	Center       *Coordinates
+	RadiusKm     float64 // around Center, up to 20001.6
+	Box          *BoundingBox
+	Limit        int // most recent first; 0 for no limit beyond the API's
+}
+
+// USGSEarthquakeResponse represents a GeoJSON response from the USGS FDSN event API
+type USGSEarthquakeResponse struct {
+	Features []struct {
+		ID         string `json:"id"`
+		Properties struct {
+			Mag     *float64 `json:"mag"`
+			Place   string   `json:"place"`
+			Time    int64    `json:"time"` // Unix milliseconds
+			URL     string   `json:"url"`
+			Tsunami int      `json:"tsunami"`
+		} `json:"properties"`
+		Geometry struct {
+			Coordinates []float64 `json:"coordinates"` // longitude, latitude, depth in km
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// FetchEarthquakes fetches earthquakes using the default Client
+func FetchEarthquakes(query EarthquakeQuery) ([]Earthquake, error) {
+	return defaultClient.FetchEarthquakes(query)
+}
+
+// FetchEarthquakes fetches recent earthquakes from the USGS catalog matching
+// query, most recent first
+func (c *Client) FetchEarthquakes(query EarthquakeQuery) ([]Earthquake, error) {
+	return c.fetchEarthquakes(context.Background(), query)
+}
+
+// FetchEarthquakesNear fetches earthquakes near a country using the default Client
+func FetchEarthquakesNear(country string, radiusKm, minMagnitude float64) ([]Earthquake, error) {
+	return defaultClient.FetchEarthquakesNear(country, radiusKm, minMagnitude)
+}
+
+// FetchEarthquakesNear fetches the last 24 hours of earthquakes of at least
+// minMagnitude within radiusKm of a country's coordinates
+func (c *Client) FetchEarthquakesNear(country string, radiusKm, minMagnitude float64) ([]Earthquake, error) {
+	coords, _, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchEarthquakes(context.Background(), EarthquakeQuery{
+		MinMagnitude: minMagnitude,
+		Center:       &coords,
+		RadiusKm:     radiusKm,
+	})
+}
+
+// fetchEarthquakes queries the USGS FDSN event service
+func (c *Client) fetchEarthquakes(ctx context.Context, query EarthquakeQuery) ([]Earthquake, error) {
+	window := query.Window
+	if window <= 0 {
+		window = defaultEarthquakeWindow
+	}
+	// Whole minutes keep the request URL, and so the cache key, stable between calls
+	start := time.Now().Add(-window).UTC().Truncate(time.Minute)
+
+	q := url.Values{}
+	if query.Center != nil {
+		if err := query.Center.Validate(); err != nil {
+			return nil, err
+		}
+		q = query.Center.query()
+		q.Set("maxradiuskm", strconv.FormatFloat(query.RadiusKm, 'f', -1, 64))
+	} else if b := query.Box; b != nil {
+		for _, corner := range []Coordinates{{Lat: b.MinLat, Lon: b.MinLon}, {Lat: b.MaxLat, Lon: b.MaxLon}} {
+			if err := corner.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		q.Set("minlatitude", fmt.Sprintf("%.4f", b.MinLat))
+		q.Set("minlongitude", fmt.Sprintf("%.4f", b.MinLon))
+		q.Set("maxlatitude", fmt.Sprintf("%.4f", b.MaxLat))
+		q.Set("maxlongitude", fmt.Sprintf("%.4f", b.MaxLon))
+	}
+	q.Set("format", "geojson")
+	q.Set("orderby", "time")
+	q.Set("starttime", start.Format(time.RFC3339))
+	if query.MinMagnitude > 0 {
+		q.Set("minmagnitude", strconv.FormatFloat(query.MinMagnitude, 'f', -1, 64))
+	}
+	if query.Limit > 0 {
+		q.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	var apiResp USGSEarthquakeResponse
+	if err := c.getJSON(ctx, c.usgsBaseURL+"/fdsnws/event/1/query", q, &apiResp); err != nil {
+		return nil, err
+	}
+
+	quakes := make([]Earthquake, 0, len(apiResp.Features))
+	for _, f := range apiResp.Features {
+		pos := f.Geometry.Coordinates
+		if len(pos) < 3 || f.Properties.Mag == nil {
+			continue
+		}
+		eq := Earthquake{
+			ID:        f.ID,
+			Magnitude: *f.Properties.Mag,
+			Place:     f.Properties.Place,
+			DepthKm:   pos[2],
+			Time:      time.UnixMilli(f.Properties.Time).UTC(),
+			Latitude:  pos[1],
+			Longitude: pos[0],
+			Tsunami:   f.Properties.Tsunami != 0,
+			URL:       f.Properties.URL,
+		}
+		if query.Center != nil {
+			eq.DistanceKm = roundTo(distanceKm(*query.Center, Coordinates{Lat: eq.Latitude, Lon: eq.Longitude}), 1)
+		}
+		quakes = append(quakes, eq)
+	}
+	return quakes, nil
+}
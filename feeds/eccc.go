@@ -0,0 +1,264 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultECCCBaseURL is the Environment and Climate Change Canada datamart host
+const defaultECCCBaseURL = "https://dd.weather.gc.ca"
+
+// ecccMaxSiteDistanceKm is how far from the nearest citypage site a location
+// may be; anything further is treated as outside ECCC coverage
+const ecccMaxSiteDistanceKm = 250
+
+// WithECCCBaseURL points Environment and Climate Change Canada requests at another host
+func WithECCCBaseURL(base string) Option {
+	return func(c *Client) {
+		c.ecccBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// ECCCCityPageResponse represents a citypage weather document from the ECCC datamart
+type ECCCCityPageResponse struct {
+	Location struct {
+		Name struct {
+			Code string `xml:"code,attr"`
+			Text string `xml:",chardata"`
+		} `xml:"name"`
+	} `xml:"location"`
+	CurrentConditions struct {
+		DateTimes []struct {
+			Zone      string `xml:"zone,attr"`
+			TimeStamp string `xml:"timeStamp"`
+		} `xml:"dateTime"`
+		Condition   string `xml:"condition"`
+		IconCode    string `xml:"iconCode"`
+		Temperature string `xml:"temperature"`
+		Humidex     string `xml:"humidex"`
+		WindChill   string `xml:"windChill"`
+		Humidity    string `xml:"relativeHumidity"`
+		Wind        struct {
+			Speed   string `xml:"speed"`
+			Gust    string `xml:"gust"`
+			Bearing string `xml:"bearing"`
+		} `xml:"wind"`
+		Pressure string `xml:"pressure"` // kPa
+	} `xml:"currentConditions"`
+	Warnings struct {
+		Events []struct {
+			Type        string `xml:"type,attr"`
+			Priority    string `xml:"priority,attr"`
+			Description string `xml:"description,attr"`
+			DateTimes   []struct {
+				Zone      string `xml:"zone,attr"`
+				TimeStamp string `xml:"timeStamp"`
+			} `xml:"dateTime"`
+		} `xml:"event"`
+	} `xml:"warnings"`
+}
+
+// ECCCSite is an ECCC citypage forecast location
+type ECCCSite struct {
+	Code     string // citypage site code, e.g. s0000458
+	Province string // two-letter province or territory code
+	Coordinates
+}
+
+var (
+	ecccSitesMu sync.RWMutex
+
+	// ecccSites holds the citypage sites for major Canadian cities
+	ecccSites = map[string]ECCCSite{
+		"s0000458": {Code: "s0000458", Province: "ON", Coordinates: Coordinates{Lat: 43.74, Lon: -79.37}},  // Toronto
+		"s0000430": {Code: "s0000430", Province: "ON", Coordinates: Coordinates{Lat: 45.33, Lon: -75.58}},  // Ottawa
+		"s0000635": {Code: "s0000635", Province: "QC", Coordinates: Coordinates{Lat: 45.53, Lon: -73.65}},  // Montréal
+		"s0000620": {Code: "s0000620", Province: "QC", Coordinates: Coordinates{Lat: 46.81, Lon: -71.21}},  // Québec
+		"s0000141": {Code: "s0000141", Province: "BC", Coordinates: Coordinates{Lat: 49.25, Lon: -123.12}}, // Vancouver
+		"s0000775": {Code: "s0000775", Province: "BC", Coordinates: Coordinates{Lat: 48.43, Lon: -123.37}}, // Victoria
+		"s0000047": {Code: "s0000047", Province: "AB", Coordinates: Coordinates{Lat: 51.05, Lon: -114.07}}, // Calgary
+		"s0000045": {Code: "s0000045", Province: "AB", Coordinates: Coordinates{Lat: 53.55, Lon: -113.49}}, // Edmonton
+		"s0000193": {Code: "s0000193", Province: "MB", Coordinates: Coordinates{Lat: 49.88, Lon: -97.15}},  // Winnipeg
+		"s0000788": {Code: "s0000788", Province: "SK", Coordinates: Coordinates{Lat: 50.45, Lon: -104.61}}, // Regina
+		"s0000797": {Code: "s0000797", Province: "SK", Coordinates: Coordinates{Lat: 52.13, Lon: -106.67}}, // Saskatoon
+		"s0000318": {Code: "s0000318", Province: "NS", Coordinates: Coordinates{Lat: 44.65, Lon: -63.58}},  // Halifax
+		"s0000250": {Code: "s0000250", Province: "NB", Coordinates: Coordinates{Lat: 45.95, Lon: -66.66}},  // Fredericton
+		"s0000583": {Code: "s0000583", Province: "PE", Coordinates: Coordinates{Lat: 46.24, Lon: -63.13}},  // Charlottetown
+		"s0000280": {Code: "s0000280", Province: "NL", Coordinates: Coordinates{Lat: 47.56, Lon: -52.71}},  // St. John's
+		"s0000825": {Code: "s0000825", Province: "YT", Coordinates: Coordinates{Lat: 60.72, Lon: -135.06}}, // Whitehorse
+		"s0000366": {Code: "s0000366", Province: "NT", Coordinates: Coordinates{Lat: 62.45, Lon: -114.37}}, // Yellowknife
+		"s0000394": {Code: "s0000394", Province: "NU", Coordinates: Coordinates{Lat: 63.75, Lon: -68.52}},  // Iqaluit
+	}
+)
+
+// ecccIconCodes maps ECCC condition icon codes (day 00–29, night 30–39, and
+// special conditions 40+) to the closest WMO weather code
+var ecccIconCodes = map[string]int{
+	"00": 0, "30": 0,
+	"01": 1, "31": 1,
+	"02": 2, "32": 2, "22": 2,
+	"03": 3, "33": 3, "10": 3,
+	"06": 80, "36": 80, "07": 80, "37": 80,
+	"08": 85, "38": 85,
+	"11": 61,
+	"12": 63,
+	"13": 81,
+	"14": 66,
+	"15": 71, "16": 71,
+	"17": 73,
+	"18": 75,
+	"19": 95, "39": 95, "47": 95,
+	"23": 45, "24": 45, "44": 45,
+	"25": 71, "40": 71,
+	"26": 77,
+	"27": 96, "46": 96,
+	"28": 51,
+}
+
+// RegisterECCCSite adds or replaces an ECCC citypage site used by ECCCProvider
+func RegisterECCCSite(site ECCCSite) error {
+	if site.Code == "" || len(site.Province) != 2 {
+		return fmt.Errorf("invalid ECCC site %q in %q", site.Code, site.Province)
+	}
+	if err := site.Validate(); err != nil {
+		return err
+	}
+	site.Province = strings.ToUpper(site.Province)
+
+	ecccSitesMu.Lock()
+	defer ecccSitesMu.Unlock()
+	ecccSites[site.Code] = site
+	return nil
+}
+
+// nearestECCCSite returns the registered site closest to coords and its distance in km
+func nearestECCCSite(coords Coordinates) (ECCCSite, float64) {
+	ecccSitesMu.RLock()
+	defer ecccSitesMu.RUnlock()
+
+	var best ECCCSite
+	bestKm := math.Inf(1)
+	for _, site := range ecccSites {
+		if d := distanceKm(coords, site.Coordinates); d < bestKm || (d == bestKm && site.Code < best.Code) {
+			best, bestKm = site, d
+		}
+	}
+	return best, bestKm
+}
+
+// ECCCProvider is a WeatherProvider backed by Environment and Climate Change
+// Canada citypage observations. Coordinates are served by the nearest
+// registered citypage site, so only locations in Canada are covered; Fetch
+// fails when no site lies within 250 km.
+type ECCCProvider struct {
+	client *Client
+}
+
+// NewECCCProvider creates an ECCC provider whose requests use the given Client options
+func NewECCCProvider(options ...Option) *ECCCProvider {
+	return &ECCCProvider{client: NewClient(options...)}
+}
+
+// circuitOpen reports whether the ECCC circuit is refusing requests
+func (p *ECCCProvider) circuitOpen() bool {
+	return p.client.circuitOpen(p.client.ecccBaseURL)
+}
+
+// Fetch returns the latest observed conditions at the citypage site nearest to coords
+func (p *ECCCProvider) Fetch(ctx context.Context, coords Coordinates) (*WeatherData, error) {
+	page, err := p.client.fetchCityPage(ctx, coords)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := page.CurrentConditions
+	temp, err := strconv.ParseFloat(strings.TrimSpace(cur.Temperature), 64)
+	if err != nil {
+		return nil, withKind(ErrDecode, errors.New("ECCC citypage has no current temperature"))
+	}
+
+	// Humidex and wind chill are only reported when they apply
+	feels := temp
+	for _, s := range []string{cur.Humidex, cur.WindChill} {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			feels = v
+			break
+		}
+	}
+
+	summary := strings.TrimSpace(cur.Condition)
+	code, ok := ecccIconCodes[strings.TrimSpace(cur.IconCode)]
+	if ok {
+		summary = describeWeatherCode(code)
+	} else {
+		code = nwsWeatherCode(summary)
+	}
+
+	w := &WeatherData{
+		Summary:          summary,
+		WeatherCode:      code,
+		TemperatureC:     temp,
+		FeelsLikeC:       feels,
+		HumidityPct:      ecccValue(cur.Humidity),
+		WindSpeedKmh:     ecccValue(cur.Wind.Speed),
+		WindDirectionDeg: ecccValue(cur.Wind.Bearing),
+		WindGustsKmh:     ecccValue(cur.Wind.Gust),
+		PressureHPa:      ecccValue(cur.Pressure) * 10,
+		Latitude:         coords.Lat,
+		Longitude:        coords.Lon,
+	}
+	// Citypages give observation times in UTC and the station's own zone,
+	// whose abbreviations such as "EST" are not IANA names
+	for _, dt := range cur.DateTimes {
+		if dt.Zone != "UTC" {
+			continue
+		}
+		if t, err := time.Parse(ecccTimeStampLayout, strings.TrimSpace(dt.TimeStamp)); err == nil {
+			loc := TimezoneAt(coords)
+			w.Timezone, w.ObservedAt = loc.String(), t.In(loc)
+		}
+	}
+	return w, nil
+}
+
+// ecccValue parses a numeric citypage element; missing values and text such
+// as "calm" give 0
+func ecccValue(s string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// fetchCityPage fetches the citypage document of the site nearest to coords
+func (c *Client) fetchCityPage(ctx context.Context, coords Coordinates) (*ECCCCityPageResponse, error) {
+	site, km := nearestECCCSite(coords)
+	if km > ecccMaxSiteDistanceKm {
+		return nil, fmt.Errorf("%w: no ECCC citypage site within %d km of %.4f,%.4f", ErrUnknownLocation, ecccMaxSiteDistanceKm, coords.Lat, coords.Lon)
+	}
+
+	var page ECCCCityPageResponse
+	endpoint := fmt.Sprintf("%s/citypage_weather/xml/%s/%s_e.xml", c.ecccBaseURL, site.Province, site.Code)
+	if err := c.getXML(ctx, endpoint, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// distanceKm returns the great-circle distance between two points
+func distanceKm(a, b Coordinates) float64 {
+	const earthRadiusKm = 6371.0
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,135 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Failure categories. Every error returned by this package for one of these
+// failure modes matches the category with errors.Is, in addition to any more
+// specific error such as ErrUnknownCountry.
+var (
+	// ErrUnknownLocation is the category of errors for locations that cannot
+	// be resolved: ErrUnknownCountry, ErrPlaceNotFound and locations outside
+	// a provider's coverage
+	ErrUnknownLocation = errors.New("unknown location")
+
+	// ErrTimeout is the category of errors for requests that ran out of time,
+	// whether the Client's own timeout (ErrUpstreamTimeout) or the caller's deadline
+	ErrTimeout = errors.New("timeout")
+
+	// ErrDecode is the category of errors for upstream responses that could
+	// not be parsed or were missing required data
+	ErrDecode = errors.New("decode error")
+)
+
+var (
+	// ErrUnknownCountry is returned when a country has no registered coordinates
+	// and the Client does not fall back to a default location
+	ErrUnknownCountry error = &kindError{msg: "unknown country", kinds: []error{ErrUnknownLocation}}
+
+	// ErrUnknownRegion is returned when a state or province is not in the
+	// region registry and the Client does not fall back to the country
+	ErrUnknownRegion error = &kindError{msg: "unknown region", kinds: []error{ErrUnknownLocation}}
+
+	// ErrInvalidCoordinates is returned for latitudes outside ±90 or longitudes outside ±180
+	ErrInvalidCoordinates = errors.New("invalid coordinates")
+
+	// ErrPlaceNotFound is returned when the geocoder has no match for a place query
+	ErrPlaceNotFound error = &kindError{msg: "place not found", kinds: []error{ErrUnknownLocation}}
+
+	// ErrResponseTooLarge is returned when an upstream response exceeds the configured limit
+	ErrResponseTooLarge = errors.New("upstream response exceeds maximum size")
+
+	// ErrUpstreamTimeout is returned when the Client's own HTTP timeout expires
+	// before the upstream API responds
+	ErrUpstreamTimeout error = &kindError{msg: "upstream request timed out", kinds: []error{ErrTimeout}}
+
+	// ErrCircuitOpen is returned without contacting an upstream whose circuit
+	// breaker has tripped after repeated failures
+	ErrCircuitOpen = errors.New("upstream circuit open")
+
+	// ErrAlertsUnavailable is returned when no alert feed covers the requested country
+	ErrAlertsUnavailable = errors.New("weather alerts unavailable")
+
+	// ErrPollenUnavailable is returned when the pollen source has no data for a location
+	ErrPollenUnavailable = errors.New("pollen data unavailable")
+
+	// ErrAirportStatusUnavailable is returned for airports outside the FAA's coverage
+	ErrAirportStatusUnavailable = errors.New("airport status unavailable")
+
+	// ErrFuelPricesUnavailable is returned when no fuel price source covers a country
+	ErrFuelPricesUnavailable = errors.New("fuel prices unavailable")
+
+	// ErrUnknownCurrency is returned for a currency code without an exchange rate
+	ErrUnknownCurrency = errors.New("unknown currency")
+
+	// ErrUnknownMarket is returned for an exchange or market symbol that is not known
+	ErrUnknownMarket = errors.New("unknown market")
+
+	// ErrUnknownAgency is returned for a transit agency that is not registered
+	ErrUnknownAgency = errors.New("unknown transit agency")
+
+	// ErrUnknownLeague is returned for a sports league without a scoreboard
+	ErrUnknownLeague = errors.New("unknown league")
+)
+
+// UpstreamStatusError is returned when an upstream API responds with a non-200 status
+type UpstreamStatusError struct {
+	Code int
+}
+
+// Error reports the status code
+func (e *UpstreamStatusError) Error() string {
+	return fmt.Sprintf("weather API returned status %d", e.Code)
+}
+
+// Temporary reports whether the status suggests a later retry may succeed
+// (5xx or 429 Too Many Requests)
+func (e *UpstreamStatusError) Temporary() bool {
+	return e.Code >= 500 || e.Code == http.StatusTooManyRequests
+}
+
+// Retryable reports whether err is a transient failure worth retrying later:
+// a timeout, a network error, a temporary upstream status or an open circuit. Decode errors,
+// unknown locations and other client-side errors are not retryable.
+func Retryable(err error) bool {
+	var status *UpstreamStatusError
+	switch {
+	case errors.As(err, &status):
+		return status.Temporary()
+	case errors.Is(err, context.Canceled):
+		return false
+	case errors.Is(err, ErrTimeout), errors.Is(err, ErrCircuitOpen):
+		return true
+	default:
+		var ne net.Error
+		return errors.As(err, &ne)
+	}
+}
+
+// kindError is an error with its own message that also matches one or more
+// category errors
+type kindError struct {
+	msg   string
+	kinds []error
+}
+
+func (e *kindError) Error() string   { return e.msg }
+func (e *kindError) Unwrap() []error { return e.kinds }
+
+// contextError returns a context's error, also matching ErrTimeout when the deadline passed
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return withKind(ErrTimeout, err)
+	}
+	return err
+}
+
+// withKind returns err with its message unchanged, additionally matching kind
+func withKind(kind, err error) error {
+	return &kindError{msg: err.Error(), kinds: []error{err, kind}}
+}
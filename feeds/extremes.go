@@ -0,0 +1,30 @@
+package feeds
+
+// WarmestLocation returns the key and reading with the highest temperature.
+// Nil readings are ignored; an empty map yields "" and nil.
+func WarmestLocation(results map[string]*WeatherData) (string, *WeatherData) {
+	return extremeLocation(results, func(a, b float64) bool { return a > b })
+}
+
+// ColdestLocation returns the key and reading with the lowest temperature.
+// Nil readings are ignored; an empty map yields "" and nil.
+func ColdestLocation(results map[string]*WeatherData) (string, *WeatherData) {
+	return extremeLocation(results, func(a, b float64) bool { return a < b })
+}
+
+// extremeLocation scans results for the reading that beats all others.
+// Ties are broken by the lexically smallest key so results are deterministic.
+func extremeLocation(results map[string]*WeatherData, better func(a, b float64) bool) (string, *WeatherData) {
+	var bestKey string
+	var best *WeatherData
+	for k, w := range results {
+		if w == nil {
+			continue
+		}
+		if best == nil || better(w.TemperatureC, best.TemperatureC) ||
+			(w.TemperatureC == best.TemperatureC && k < bestKey) {
+			bestKey, best = k, w
+		}
+	}
+	return bestKey, best
+}
@@ -0,0 +1,91 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FallbackProvider is a WeatherProvider that tries several providers in
+// priority order, returning the first result. A provider is skipped while
+// its upstream circuit is open, and abandoned for the next one when it fails
+// or exceeds the per-provider timeout. WeatherData.Provider names the
+// provider that served the result.
+type FallbackProvider struct {
+	providers []WeatherProvider
+	timeout   time.Duration
+}
+
+// NewFallbackProvider creates a FallbackProvider trying providers in order,
+// e.g. NWS then Open-Meteo. timeout bounds each provider's attempt; zero
+// leaves attempts bounded by the caller's context alone.
+func NewFallbackProvider(timeout time.Duration, providers ...WeatherProvider) *FallbackProvider {
+	return &FallbackProvider{providers: providers, timeout: timeout}
+}
+
+// Fetch fetches current conditions at coords from the first provider that
+// succeeds. When all fail, the error joins each provider's error.
+func (f *FallbackProvider) Fetch(ctx context.Context, coords Coordinates) (*WeatherData, error) {
+	var errs []error
+	for _, p := range f.providers {
+		name := providerName(p)
+		if tripped(p) {
+			packageLogger.Load().InfoContext(ctx, "skipping weather provider with open circuit", "provider", name)
+			errs = append(errs, fmt.Errorf("%s: %w", name, ErrCircuitOpen))
+			continue
+		}
+		w, err := f.attempt(ctx, p, coords)
+		if err == nil {
+			if w.Provider == "" {
+				w.Provider = name
+			}
+			return w, nil
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		packageLogger.Load().WarnContext(ctx, "weather provider failed, falling back", "provider", name, "error", err)
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("no weather providers configured")
+	}
+	return nil, fmt.Errorf("all weather providers failed: %w", errors.Join(errs...))
+}
+
+// attempt calls one provider within the per-provider timeout
+func (f *FallbackProvider) attempt(ctx context.Context, p WeatherProvider, coords Coordinates) (*WeatherData, error) {
+	if f.timeout <= 0 {
+		return p.Fetch(ctx, coords)
+	}
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+	w, err := p.Fetch(ctx, coords)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && !errors.Is(err, ErrTimeout) {
+		err = withKind(ErrTimeout, fmt.Errorf("no response within %s: %w", f.timeout, err))
+	}
+	return w, err
+}
+
+// circuitOpen reports whether every provider is skipped for an open circuit
+func (f *FallbackProvider) circuitOpen() bool {
+	for _, p := range f.providers {
+		if !tripped(p) {
+			return false
+		}
+	}
+	return len(f.providers) > 0
+}
+
+// circuitReporter is implemented by providers that know whether their
+// upstream's circuit breaker is refusing requests
+type circuitReporter interface {
+	circuitOpen() bool
+}
+
+// tripped reports whether p's upstream circuit is open
+func tripped(p WeatherProvider) bool {
+	r, ok := p.(circuitReporter)
+	return ok && r.circuitOpen()
+}
@@ -0,0 +1,257 @@
+package feeds
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Cache lookup results reported to a MetricsRecorder
+const (
+	CacheHit   = "hit"   // served from the cache
+	CacheStale = "stale" // served stale, while revalidating or after an upstream error
+	CacheMiss  = "miss"  // fetched from the upstream
+)
+
+// MetricsRecorder receives measurements of a Client's upstream requests.
+// Metrics implements it in the Prometheus text format; other implementations
+// can bridge to an application's own metrics library. Methods are called
+// concurrently from requesting goroutines and must not block.
+type MetricsRecorder interface {
+	// ObserveFetch records an upstream request, retries included, and its
+	// error if it failed. Requests refused by an open circuit have a zero
+	// duration.
+	ObserveFetch(host string, duration time.Duration, err error)
+	// ObserveDecodeError records an upstream response that failed to parse
+	ObserveDecodeError(host string)
+	// ObserveCache records a cache lookup result: CacheHit, CacheStale or CacheMiss
+	ObserveCache(host string, result string)
+	// ObserveCircuit records an upstream circuit's current state
+	ObserveCircuit(host string, state CircuitState)
+}
+
+// WithMetrics sets where the Client reports request metrics. Several Clients
+// may share one recorder; hosts tell their upstreams apart.
+func WithMetrics(m MetricsRecorder) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// hostOf returns the host of an upstream URL
+func hostOf(target string) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// ErrorKind classifies an error returned by a Client for metrics and logs:
+// "circuit_open", "timeout", "canceled", "http_4xx", "http_5xx", "too_large",
+// "decode", "network" or "other"
+func ErrorKind(err error) string {
+	var status *UpstreamStatusError
+	var ne net.Error
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, ErrTimeout):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &status):
+		if status.Code >= 500 {
+			return "http_5xx"
+		}
+		return "http_4xx"
+	case errors.Is(err, ErrResponseTooLarge):
+		return "too_large"
+	case errors.Is(err, ErrDecode):
+		return "decode"
+	case errors.As(err, &ne):
+		return "network"
+	default:
+		return "other"
+	}
+}
+
+// defaultLatencyBuckets are the upper bounds, in seconds, of the request
+// duration histogram
+var defaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics is a MetricsRecorder that serves its measurements in the
+// Prometheus text exposition format. Create one with NewMetrics, pass it to
+// clients with WithMetrics and mount it as the /metrics handler.
+type Metrics struct {
+	mu        sync.Mutex
+	requests  map[[2]string]uint64 // host, outcome
+	errors    map[[2]string]uint64 // host, kind
+	latencies map[string]*histogram
+	cache     map[[2]string]uint64 // host, result
+	circuits  map[string]CircuitState
+}
+
+// histogram is a cumulative Prometheus histogram
+type histogram struct {
+	counts []uint64 // per bucket of defaultLatencyBuckets, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// NewMetrics creates an empty Metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests:  make(map[[2]string]uint64),
+		errors:    make(map[[2]string]uint64),
+		latencies: make(map[string]*histogram),
+		cache:     make(map[[2]string]uint64),
+		circuits:  make(map[string]CircuitState),
+	}
+}
+
+// ObserveFetch implements MetricsRecorder
+func (m *Metrics) ObserveFetch(host string, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+		m.errors[[2]string{host, ErrorKind(err)}]++
+	}
+	m.requests[[2]string{host, outcome}]++
+	if errors.Is(err, ErrCircuitOpen) {
+		return // never reached the upstream
+	}
+	h, ok := m.latencies[host]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(defaultLatencyBuckets))}
+		m.latencies[host] = h
+	}
+	secs := d.Seconds()
+	if i, _ := slices.BinarySearch(defaultLatencyBuckets, secs); i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.sum += secs
+	h.count++
+}
+
+// ObserveDecodeError implements MetricsRecorder
+func (m *Metrics) ObserveDecodeError(host string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[[2]string{host, "decode"}]++
+}
+
+// ObserveCache implements MetricsRecorder
+func (m *Metrics) ObserveCache(host, result string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cache[[2]string{host, result}]++
+}
+
+// ObserveCircuit implements MetricsRecorder
+func (m *Metrics) ObserveCircuit(host string, state CircuitState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.circuits[host] = state
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WriteText(w)
+}
+
+// WriteText writes the metrics in the Prometheus text format, with # HELP
+// and # TYPE headers
+func (m *Metrics) WriteText(out io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := &promWriter{w: out}
+	p.header("reef_upstream_requests_total", "counter", "Upstream requests, retries included, by outcome.")
+	for _, k := range sortedPairs(m.requests) {
+		p.sample("reef_upstream_requests_total", map[string]string{"host": k[0], "outcome": k[1]}, float64(m.requests[k]))
+	}
+	p.header("reef_upstream_errors_total", "counter", "Failed upstream requests and unparseable responses by error kind.")
+	for _, k := range sortedPairs(m.errors) {
+		p.sample("reef_upstream_errors_total", map[string]string{"host": k[0], "kind": k[1]}, float64(m.errors[k]))
+	}
+
+	p.header("reef_upstream_request_duration_seconds", "histogram", "Upstream request latency, retries included.")
+	for _, host := range slices.Sorted(maps.Keys(m.latencies)) {
+		h := m.latencies[host]
+		var cumulative uint64
+		for i, le := range defaultLatencyBuckets {
+			cumulative += h.counts[i]
+			p.sample("reef_upstream_request_duration_seconds_bucket",
+				map[string]string{"host": host, "le": strconv.FormatFloat(le, 'g', -1, 64)}, float64(cumulative))
+		}
+		p.sample("reef_upstream_request_duration_seconds_bucket", map[string]string{"host": host, "le": "+Inf"}, float64(h.count))
+		p.sample("reef_upstream_request_duration_seconds_sum", map[string]string{"host": host}, h.sum)
+		p.sample("reef_upstream_request_duration_seconds_count", map[string]string{"host": host}, float64(h.count))
+	}
+
+	p.header("reef_cache_lookups_total", "counter", "Response cache lookups by result (hit, stale or miss).")
+	for _, k := range sortedPairs(m.cache) {
+		p.sample("reef_cache_lookups_total", map[string]string{"host": k[0], "result": k[1]}, float64(m.cache[k]))
+	}
+	p.header("reef_cache_hit_ratio", "gauge", "Share of cache lookups served without contacting the upstream.")
+	lookups := make(map[string][2]uint64) // served from cache, total
+	for k, n := range m.cache {
+		v := lookups[k[0]]
+		if k[1] != CacheMiss {
+			v[0] += n
+		}
+		v[1] += n
+		lookups[k[0]] = v
+	}
+	for _, host := range slices.Sorted(maps.Keys(lookups)) {
+		v := lookups[host]
+		p.sample("reef_cache_hit_ratio", map[string]string{"host": host}, float64(v[0])/float64(v[1]))
+	}
+
+	p.header("reef_circuit_state", "gauge", "Upstream circuit breaker state: 0 closed, 1 open, 2 half-open.")
+	for _, host := range slices.Sorted(maps.Keys(m.circuits)) {
+		p.sample("reef_circuit_state", map[string]string{"host": host}, float64(m.circuits[host]))
+	}
+	return p.err
+}
+
+// sortedPairs returns the keys of a two-label counter in order
+func sortedPairs(m map[[2]string]uint64) [][2]string {
+	return slices.SortedFunc(maps.Keys(m), func(a, b [2]string) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+}
+
+// promWriter writes text-format lines, keeping the first error
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) header(name, typ, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (p *promWriter) sample(name string, labels map[string]string, v float64) {
+	p.printf("%s%s %s\n", name, formatLabels(labels), strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+func (p *promWriter) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
@@ -0,0 +1,142 @@
+package feeds
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// fileCacheExt is the extension of FileCache entry files
+const fileCacheExt = ".json"
+
+// FileCache is a Cache that keeps one JSON file per entry in a directory, so
+// responses survive restarts. Files are named after a hash of the key and do
+// not contain the key itself, since request URLs can carry API keys. Entries
+// are written atomically; unreadable or corrupt files are treated as missing.
+type FileCache struct {
+	dir        string
+	maxEntries int
+}
+
+// fileCacheEntry is the on-disk form of a cache entry
+type fileCacheEntry struct {
+	Fetched      time.Time `json:"fetched"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Body         []byte    `json:"body"`
+}
+
+// NewFileCache creates a FileCache in dir holding at most maxEntries
+// responses (unbounded if maxEntries is zero or negative), creating the
+// directory if needed. When full, the least recently written entry is evicted.
+func NewFileCache(dir string, maxEntries int) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &FileCache{dir: dir, maxEntries: maxEntries}, nil
+}
+
+// path returns the entry file for key, named after the key's SHA-256 hash
+func (f *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:])+fileCacheExt)
+}
+
+// Get returns the entry for key
+func (f *FileCache) Get(key string) (CacheEntry, bool) {
+	data, err := os.ReadFile(f.path(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	var e fileCacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return CacheEntry{}, false
+	}
+	return CacheEntry{Body: e.Body, Fetched: e.Fetched, ETag: e.ETag, LastModified: e.LastModified}, true
+}
+
+// Set stores entry under key, evicting the oldest entries if the cache is
+// full. Write errors are ignored; the entry is simply fetched again next time.
+func (f *FileCache) Set(key string, entry CacheEntry) {
+	data, err := json.Marshal(fileCacheEntry{
+		Fetched:      entry.Fetched,
+		ETag:         entry.ETag,
+		LastModified: entry.LastModified,
+		Body:         entry.Body,
+	})
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(f.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || os.Rename(tmp.Name(), f.path(key)) != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	f.evict()
+}
+
+// evict removes the least recently written entries beyond maxEntries
+func (f *FileCache) evict() {
+	if f.maxEntries <= 0 {
+		return
+	}
+	names := f.entryFiles()
+	if len(names) <= f.maxEntries {
+		return
+	}
+	written := make(map[string]time.Time, len(names))
+	for _, name := range names {
+		if fi, err := os.Stat(filepath.Join(f.dir, name)); err == nil {
+			written[name] = fi.ModTime()
+		}
+	}
+	slices.SortFunc(names, func(a, b string) int { return written[a].Compare(written[b]) })
+	for _, name := range names[:len(names)-f.maxEntries] {
+		os.Remove(filepath.Join(f.dir, name))
+	}
+}
+
+// Delete removes the entry for key
+func (f *FileCache) Delete(key string) {
+	os.Remove(f.path(key))
+}
+
+// Flush removes every entry
+func (f *FileCache) Flush() {
+	for _, name := range f.entryFiles() {
+		os.Remove(filepath.Join(f.dir, name))
+	}
+}
+
+// Len returns the number of entries
+func (f *FileCache) Len() int {
+	return len(f.entryFiles())
+}
+
+// entryFiles lists the entry file names in the cache directory
+func (f *FileCache) entryFiles() []string {
+	dirEntries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, d := range dirEntries {
+		if !d.IsDir() && strings.HasSuffix(d.Name(), fileCacheExt) {
+			names = append(names, d.Name())
+		}
+	}
+	return names
+}
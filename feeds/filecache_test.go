@@ -0,0 +1,85 @@
+package feeds
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileCache(t *testing.T) {
+	dir := t.TempDir()
+	f, err := NewFileCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := "https://api.eia.gov/v2/petroleum/pri/gnd/data/?api_key=s3cret&frequency=weekly"
+	fetched := time.Date(2026, 6, 21, 12, 0, 0, 0, time.UTC)
+	f.Set(key, CacheEntry{Body: []byte(`{"ok":true}`), Fetched: fetched, ETag: `"v1"`})
+
+	got, ok := f.Get(key)
+	if !ok || string(got.Body) != `{"ok":true}` || !got.Fetched.Equal(fetched) || got.ETag != `"v1"` {
+		t.Errorf("Get = %+v, %v", got, ok)
+	}
+	if _, ok := f.Get(key + "&x=1"); ok {
+		t.Error("Get found an entry for another key")
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("cache directory has %d files, want 1", len(files))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(files[0].Name(), "s3cret") || strings.Contains(string(data), "s3cret") ||
+		strings.Contains(string(data), "api.eia.gov") {
+		t.Errorf("entry file %s stores the request URL: %s", files[0].Name(), data)
+	}
+
+	f.Delete(key)
+	if f.Len() != 0 {
+		t.Errorf("Len after Delete = %d, want 0", f.Len())
+	}
+}
+
+func TestFileCacheEviction(t *testing.T) {
+	f, err := NewFileCache(t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Set("a", CacheEntry{Body: []byte("a")})
+	f.Set("b", CacheEntry{Body: []byte("b")})
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(f.path("a"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	f.Set("c", CacheEntry{Body: []byte("c")})
+	if n := f.Len(); n != 2 {
+		t.Errorf("Len = %d, want 2", n)
+	}
+	if _, ok := f.Get("a"); ok {
+		t.Error("oldest entry a was not evicted")
+	}
+	for _, key := range []string{"b", "c"} {
+		if _, ok := f.Get(key); !ok {
+			t.Errorf("entry %s was evicted", key)
+		}
+	}
+
+	// replacing an entry does not evict another
+	f.Set("b", CacheEntry{Body: []byte("b2")})
+	if e, ok := f.Get("c"); !ok || string(e.Body) != "c" {
+		t.Error("replacing b evicted c")
+	}
+	f.Flush()
+	if n := f.Len(); n != 0 {
+		t.Errorf("Len after Flush = %d, want 0", n)
+	}
+}
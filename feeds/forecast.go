@@ -0,0 +1,77 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxForecastSummaryDays is the longest forecast FetchForecast returns
+const maxForecastSummaryDays = 7
+
+// FetchForecast fetches a multi-day forecast using the default Client
+func FetchForecast(country string, days int) (*ForecastData, error) {
+	return defaultClient.FetchForecast(country, days)
+}
+
+// FetchForecast fetches daily highs, lows, precipitation probability and
+// weather codes for today and the following days (1–7 days in total)
+func (c *Client) FetchForecast(country string, days int) (*ForecastData, error) {
+	if days < 1 || days > maxForecastSummaryDays {
+		return nil, fmt.Errorf("forecast days must be between 1 and %d, got %d", maxForecastSummaryDays, days)
+	}
+
+	coords, _, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+
+	q := coords.query()
+	q.Set("daily", forecastDailyVariables)
+	q.Set("forecast_days", fmt.Sprint(days))
+
+	return c.getDaily(context.Background(), c.forecastBaseURL+"/v1/forecast", q)
+}
+
+// forecastFeedTTL is how often the forecast feed is worth refetching
+const forecastFeedTTL = time.Hour
+
+// ForecastFeed returns a Feed named "forecast" of the daily forecast for
+// today and the following days (1–7 days in total) at the location, fetched
+// through c (the default Client if nil)
+func ForecastFeed(c *Client, days int) Feed {
+	if c == nil {
+		c = defaultClient
+	}
+	days = min(max(days, 1), maxForecastSummaryDays)
+	return NewFeed("forecast", forecastFeedTTL, func(ctx context.Context, loc Location) (any, error) {
+		q := loc.query()
+		q.Set("daily", forecastDailyVariables)
+		q.Set("forecast_days", fmt.Sprint(days))
+		return c.getDaily(ctx, c.forecastBaseURL+"/v1/forecast", q)
+	})
+}
+
+// FetchForecastRange fetches a forecast date range using the default Client
+func FetchForecastRange(country string, start, end time.Time) ([]DailyForecast, error) {
+	return defaultClient.FetchForecastRange(country, start, end)
+}
+
+// FetchForecastRange fetches forecast daily highs, lows and weather codes for
+// every day between start and end (inclusive). The range must lie within
+// Open-Meteo's forecast window of today plus the following 15 days.
+func (c *Client) FetchForecastRange(country string, start, end time.Time) ([]DailyForecast, error) {
+	start, end = truncateDay(start), truncateDay(end)
+	today := truncateDay(time.Now())
+	if start.After(end) {
+		return nil, fmt.Errorf("%w: forecast range start must not be after end", ErrInvalidDateRange)
+	}
+	if start.Before(today) {
+		return nil, fmt.Errorf("%w: forecast range must not start in the past", ErrInvalidDateRange)
+	}
+	if !end.Before(today.AddDate(0, 0, maxForecastDays)) {
+		return nil, fmt.Errorf("%w: forecast range extends beyond the 16-day forecast window", ErrInvalidDateRange)
+	}
+
+	return c.fetchDailyRange(context.Background(), c.forecastBaseURL+"/v1/forecast", forecastDailyVariables, country, start, end)
+}
@@ -0,0 +1,25 @@
+package feeds
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFetchForecastRangeInvalid(t *testing.T) {
+	c := NewClient(WithBaseURL("http://127.0.0.1:0"))
+	today := time.Now()
+	tests := []struct {
+		name       string
+		start, end time.Time
+	}{
+		{"start after end", today.AddDate(0, 0, 2), today.AddDate(0, 0, 1)},
+		{"in the past", today.AddDate(0, 0, -1), today},
+		{"beyond the window", today, today.AddDate(0, 0, maxForecastDays)},
+	}
+	for _, tt := range tests {
+		if _, err := c.FetchForecastRange("US", tt.start, tt.end); !errors.Is(err, ErrInvalidDateRange) {
+			t.Errorf("%s: error = %v, want ErrInvalidDateRange", tt.name, err)
+		}
+	}
+}
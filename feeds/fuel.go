@@ -0,0 +1,312 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultEIABaseURL is the U.S. Energy Information Administration open data host
+	defaultEIABaseURL = "https://api.eia.gov"
+	// defaultCREBaseURL hosts the Comisión Reguladora de Energía station price publication
+	defaultCREBaseURL = "https://publicacionexterna.azurewebsites.net"
+)
+
+// Fuel grades
+const (
+	FuelGradeRegular  = "regular"
+	FuelGradeMidgrade = "midgrade"
+	FuelGradePremium  = "premium"
+	FuelGradeDiesel   = "diesel"
+)
+
+// fuelGradeOrder sorts grades from regular to diesel
+var fuelGradeOrder = []string{FuelGradeRegular, FuelGradeMidgrade, FuelGradePremium, FuelGradeDiesel}
+
+// WithEIAKey sets the EIA open data API key used for US fuel prices, which
+// are unavailable without one
+func WithEIAKey(key string) Option {
+	return func(c *Client) {
+		c.eiaKey = key
+	}
+}
+
+// SetEIAKey replaces the EIA API key, e.g. after rotating it; requests
+// already sent keep the old key
+func (c *Client) SetEIAKey(key string) {
+	c.keyMu.Lock()
+	defer c.keyMu.Unlock()
+	c.eiaKey = key
+}
+
+// eiaAPIKey returns the current EIA API key
+func (c *Client) eiaAPIKey() string {
+	c.keyMu.RLock()
+	defer c.keyMu.RUnlock()
+	return c.eiaKey
+}
+
+// WithEIABaseURL points EIA requests at another host
+func WithEIABaseURL(base string) Option {
+	return func(c *Client) {
+		c.eiaBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// WithCREBaseURL points Mexican fuel price requests at another host
+func WithCREBaseURL(base string) Option {
+	return func(c *Client) {
+		c.creBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// FuelPriceProvider fetches average retail fuel prices for a country's regions
+type FuelPriceProvider interface {
+	FuelPrices(ctx context.Context, region string) (*FuelPrices, error)
+}
+
+// WithFuelPriceProvider sets the provider used for a country's fuel prices,
+// replacing the built-in source (EIA for US, CRE for MX). Canada has no
+// built-in source, so CA prices need a provider.
+func WithFuelPriceProvider(country string, p FuelPriceProvider) Option {
+	return func(c *Client) {
+		if c.fuelProviders == nil {
+			c.fuelProviders = make(map[string]FuelPriceProvider)
+		}
+		c.fuelProviders[strings.ToUpper(country)] = p
+	}
+}
+
+// FuelPrices holds average retail fuel prices in a region
+type FuelPrices struct {
+	Country    string           `json:"country"`
+	Region     string           `json:"region,omitempty"` // empty for the national average
+	RegionName string           `json:"regionName"`
+	Currency   string           `json:"currency"`
+	Unit       string           `json:"unit"`   // "gal" or "L"
+	Period     time.Time        `json:"period"` // survey week, or when a snapshot source was read
+	Grades     []FuelGradePrice `json:"grades"`
+}
+
+// FuelGradePrice is the average price of one fuel grade
+type FuelGradePrice struct {
+	Grade        string   `json:"grade"` // one of the FuelGrade constants
+	Price        float64  `json:"price"`
+	WeeklyChange *float64 `json:"weeklyChange,omitempty"` // price change from the previous week, when the source has history
+}
+
+// Price returns the price of a grade, or false if the region has none
+func (p *FuelPrices) Price(grade string) (float64, bool) {
+	for _, g := range p.Grades {
+		if g.Grade == grade {
+			return g.Price, true
+		}
+	}
+	return 0, false
+}
+
+// eiaRegion is an EIA gasoline and diesel survey area
+type eiaRegion struct {
+	duoarea, name string
+}
+
+// eiaRegions maps region codes accepted by FetchFuelPrices to EIA areas:
+// PADD regions and subregions by slug, and the states EIA surveys by code
+var eiaRegions = map[string]eiaRegion{
+	"":                 {"NUS", "United States"},
+	"east-coast":       {"R10", "East Coast (PADD 1)"},
+	"new-england":      {"R1X", "New England (PADD 1A)"},
+	"central-atlantic": {"R1Y", "Central Atlantic (PADD 1B)"},
+	"lower-atlantic":   {"R1Z", "Lower Atlantic (PADD 1C)"},
+	"midwest":          {"R20", "Midwest (PADD 2)"},
+	"gulf-coast":       {"R30", "Gulf Coast (PADD 3)"},
+	"rocky-mountain":   {"R40", "Rocky Mountain (PADD 4)"},
+	"west-coast":       {"R50", "West Coast (PADD 5)"},
+	"CA":               {"SCA", "California"},
+	"CO":               {"SCO", "Colorado"},
+	"FL":               {"SFL", "Florida"},
+	"MA":               {"SMA", "Massachusetts"},
+	"MN":               {"SMN", "Minnesota"},
+	"NY":               {"SNY", "New York"},
+	"OH":               {"SOH", "Ohio"},
+	"TX":               {"STX", "Texas"},
+	"WA":               {"SWA", "Washington"},
+}
+
+// eiaProducts maps EIA retail product codes to fuel grades
+var eiaProducts = map[string]string{
+	"EPMR":  FuelGradeRegular,
+	"EPMM":  FuelGradeMidgrade,
+	"EPMP":  FuelGradePremium,
+	"EPD2D": FuelGradeDiesel,
+}
+
+// EIARegions returns the US region codes FetchFuelPrices accepts, sorted
+func EIARegions() []string {
+	return slices.Sorted(maps.Keys(eiaRegions))
+}
+
+// EIAPriceResponse represents an EIA v2 petroleum/pri/gnd data response
+type EIAPriceResponse struct {
+	Response struct {
+		Data []struct {
+			Period  string    `json:"period"` // YYYY-MM-DD, the survey's Monday
+			Duoarea string    `json:"duoarea"`
+			Product string    `json:"product"`
+			Value   nhcNumber `json:"value"` // EIA, like NHC, sends numbers bare or as strings
+			Units   string    `json:"units"`
+		} `json:"data"`
+	} `json:"response"`
+}
+
+// CREPricesResponse represents the CRE station price publication
+type CREPricesResponse struct {
+	Places []struct {
+		ID     string `xml:"place_id,attr"`
+		Prices []struct {
+			Type  string `xml:"type,attr"` // "regular", "premium" or "diesel"
+			Value string `xml:",chardata"`
+		} `xml:"gas_price"`
+	} `xml:"place"`
+}
+
+// FetchFuelPrices fetches average fuel prices using the default Client
+func FetchFuelPrices(country, region string) (*FuelPrices, error) {
+	return defaultClient.FetchFuelPrices(country, region)
+}
+
+// FetchFuelPrices returns average retail fuel prices per grade in a country's
+// region, or nationally when region is empty. US regions are listed by
+// EIARegions and need WithEIAKey; Mexico has national prices only, averaged
+// over every station reporting to the CRE. Countries without a source return
+// ErrFuelPricesUnavailable.
+func (c *Client) FetchFuelPrices(country, region string) (*FuelPrices, error) {
+	return c.fetchFuelPrices(context.Background(), strings.ToUpper(country), region)
+}
+
+// fetchFuelPrices dispatches to the country's provider or built-in source
+func (c *Client) fetchFuelPrices(ctx context.Context, country, region string) (*FuelPrices, error) {
+	if p, ok := c.fuelProviders[country]; ok {
+		return p.FuelPrices(ctx, region)
+	}
+	switch country {
+	case "US":
+		return c.fetchEIAFuelPrices(ctx, region)
+	case "MX":
+		return c.fetchCREFuelPrices(ctx, region)
+	}
+	return nil, fmt.Errorf("%w: no fuel price source for %q", ErrFuelPricesUnavailable, country)
+}
+
+// fetchEIAFuelPrices requests the two latest weekly retail prices of each
+// grade in a US region
+func (c *Client) fetchEIAFuelPrices(ctx context.Context, region string) (*FuelPrices, error) {
+	key := c.eiaAPIKey()
+	if key == "" {
+		return nil, fmt.Errorf("%w: US prices need an EIA API key (WithEIAKey)", ErrFuelPricesUnavailable)
+	}
+	if len(region) == 2 {
+		region = strings.ToUpper(region)
+	} else {
+		region = strings.ToLower(region)
+	}
+	r, ok := eiaRegions[region]
+	if !ok {
+		return nil, fmt.Errorf("%w: no EIA fuel price region %q", ErrUnknownRegion, region)
+	}
+
+	q := url.Values{}
+	q.Set("api_key", key)
+	q.Set("frequency", "weekly")
+	q.Set("data[0]", "value")
+	q.Set("facets[duoarea][]", r.duoarea)
+	q.Set("facets[process][]", "PTE") // retail sales
+	q["facets[product][]"] = slices.Sorted(maps.Keys(eiaProducts))
+	q.Set("sort[0][column]", "period")
+	q.Set("sort[0][direction]", "desc")
+	q.Set("length", strconv.Itoa(2*len(eiaProducts)))
+
+	var apiResp EIAPriceResponse
+	if err := c.getJSON(ctx, c.eiaBaseURL+"/v2/petroleum/pri/gnd/data/", q, &apiResp); err != nil {
+		return nil, err
+	}
+
+	// Rows arrive newest first: the first per product is current, the second last week's
+	latest := make(map[string]float64)
+	previous := make(map[string]float64)
+	prices := &FuelPrices{Country: "US", Region: region, RegionName: r.name, Currency: "USD", Unit: "gal"}
+	for _, row := range apiResp.Response.Data {
+		grade, ok := eiaProducts[row.Product]
+		if !ok || row.Value <= 0 {
+			continue
+		}
+		if _, ok := latest[grade]; !ok {
+			latest[grade] = float64(row.Value)
+			if t, err := time.Parse(time.DateOnly, row.Period); err == nil && t.After(prices.Period) {
+				prices.Period = t
+			}
+		} else if _, ok := previous[grade]; !ok {
+			previous[grade] = float64(row.Value)
+		}
+	}
+	if len(latest) == 0 {
+		return nil, fmt.Errorf("%w: EIA returned no prices for %s", ErrFuelPricesUnavailable, r.name)
+	}
+	for _, grade := range fuelGradeOrder {
+		price, ok := latest[grade]
+		if !ok {
+			continue
+		}
+		g := FuelGradePrice{Grade: grade, Price: price}
+		if prev, ok := previous[grade]; ok {
+			change := roundTo(price-prev, 3)
+			g.WeeklyChange = &change
+		}
+		prices.Grades = append(prices.Grades, g)
+	}
+	return prices, nil
+}
+
+// fetchCREFuelPrices averages today's prices across Mexican stations. The
+// publication is a current snapshot, so there is no weekly change.
+func (c *Client) fetchCREFuelPrices(ctx context.Context, region string) (*FuelPrices, error) {
+	if region != "" {
+		return nil, fmt.Errorf("%w: Mexican fuel prices are national only, not %q", ErrUnknownRegion, region)
+	}
+
+	var apiResp CREPricesResponse
+	if err := c.getXML(ctx, c.creBaseURL+"/publicaciones/prices", nil, &apiResp); err != nil {
+		return nil, err
+	}
+
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, place := range apiResp.Places {
+		for _, p := range place.Prices {
+			v, err := strconv.ParseFloat(strings.TrimSpace(p.Value), 64)
+			if err != nil || v <= 0 {
+				continue
+			}
+			grade := strings.ToLower(p.Type)
+			sums[grade] += v
+			counts[grade]++
+		}
+	}
+	if len(counts) == 0 {
+		return nil, fmt.Errorf("%w: CRE returned no station prices", ErrFuelPricesUnavailable)
+	}
+
+	prices := &FuelPrices{Country: "MX", RegionName: "Mexico", Currency: "MXN", Unit: "L", Period: time.Now()}
+	for _, grade := range fuelGradeOrder {
+		if n := counts[grade]; n > 0 {
+			prices.Grades = append(prices.Grades, FuelGradePrice{Grade: grade, Price: roundTo(sums[grade]/float64(n), 2)})
+		}
+	}
+	return prices, nil
+}
@@ -0,0 +1,121 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// defaultFXBaseURL is the Frankfurter exchange rate API host, which publishes
+// European Central Bank reference rates without an API key
+const defaultFXBaseURL = "https://api.frankfurter.app"
+
+// defaultCurrencies are the three major North American currencies
+var defaultCurrencies = []string{"USD", "CAD", "MXN"}
+
+// WithFXBaseURL points exchange rate requests at another Frankfurter-compatible host
+func WithFXBaseURL(base string) Option {
+	return func(c *Client) {
+		c.fxBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// WithCurrencies sets the ISO 4217 currencies FetchRates returns
+// (default USD, CAD and MXN)
+func WithCurrencies(codes ...string) Option {
+	return func(c *Client) {
+		c.currencies = make([]string, 0, len(codes))
+		for _, code := range codes {
+			c.currencies = append(c.currencies, strings.ToUpper(code))
+		}
+	}
+}
+
+// RatesData is a set of exchange rates: one unit of Base buys Rates[code] of
+// each currency. Base itself is included with a rate of 1.
+type RatesData struct {
+	Base  string             `json:"base"`
+	Date  time.Time          `json:"date"` // day the reference rates were published
+	Rates map[string]float64 `json:"rates"`
+}
+
+// FrankfurterResponse represents a /latest response from the Frankfurter API
+type FrankfurterResponse struct {
+	Amount float64            `json:"amount"`
+	Base   string             `json:"base"`
+	Date   string             `json:"date"`
+	Rates  map[string]float64 `json:"rates"`
+}
+
+// Rate returns how many units of to one unit of from buys
+func (r *RatesData) Rate(from, to string) (float64, error) {
+	fromRate, ok := r.Rates[strings.ToUpper(from)]
+	if !ok || fromRate == 0 {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, from)
+	}
+	toRate, ok := r.Rates[strings.ToUpper(to)]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, to)
+	}
+	return toRate / fromRate, nil
+}
+
+// Convert converts amount from one currency to another
+func (r *RatesData) Convert(amount float64, from, to string) (float64, error) {
+	rate, err := r.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// FetchRates fetches exchange rates using the default Client
+func FetchRates(base string) (*RatesData, error) {
+	return defaultClient.FetchRates(base)
+}
+
+// FetchRates fetches the latest exchange rates from base (e.g. "USD") to the
+// Client's currencies (see WithCurrencies)
+func (c *Client) FetchRates(base string) (*RatesData, error) {
+	return c.fetchRates(context.Background(), base)
+}
+
+// fetchRates requests the latest rates from base to the configured currencies
+func (c *Client) fetchRates(ctx context.Context, base string) (*RatesData, error) {
+	base = strings.ToUpper(strings.TrimSpace(base))
+	if base == "" {
+		return nil, fmt.Errorf("%w: empty base", ErrUnknownCurrency)
+	}
+
+	// Frankfurter rejects the base currency among the symbols
+	symbols := slices.DeleteFunc(slices.Clone(c.currencies), func(code string) bool { return code == base })
+	q := url.Values{}
+	q.Set("from", base)
+	if len(symbols) > 0 {
+		q.Set("to", strings.Join(symbols, ","))
+	}
+
+	var apiResp FrankfurterResponse
+	if err := c.getJSON(ctx, c.fxBaseURL+"/latest", q, &apiResp); err != nil {
+		var statusErr *UpstreamStatusError
+		if errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownCurrency, base)
+		}
+		return nil, err
+	}
+	date, err := time.Parse(time.DateOnly, apiResp.Date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rate date: %w", withKind(ErrDecode, err))
+	}
+
+	rates := make(map[string]float64, len(apiResp.Rates)+1)
+	maps.Copy(rates, apiResp.Rates)
+	rates[base] = 1
+	return &RatesData{Base: base, Date: date, Rates: rates}, nil
+}
@@ -0,0 +1,149 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultGeocodeTTL is how long resolved places are cached; place coordinates
+// are effectively static so this can be long
+const defaultGeocodeTTL = 24 * time.Hour
+
+// postalCodePattern matches US/MX five-digit codes (optionally ZIP+4) and Canadian postal codes
+var postalCodePattern = regexp.MustCompile(`^(\d{5}(-\d{4})?|[A-Za-z]\d[A-Za-z] ?\d[A-Za-z]\d)$`)
+
+// OpenMeteoGeocodingResponse represents a search response from the Open-Meteo geocoding API
+type OpenMeteoGeocodingResponse struct {
+	Results []struct {
+		Name        string  `json:"name"`
+		Latitude    float64 `json:"latitude"`
+		Longitude   float64 `json:"longitude"`
+		CountryCode string  `json:"country_code"`
+		Country     string  `json:"country"`
+		Admin1      string  `json:"admin1"`
+	} `json:"results"`
+}
+
+// geocodeEntry is a cached geocoding result
+type geocodeEntry struct {
+	coords  Coordinates
+	expires time.Time
+}
+
+// WithGeocodeCacheTTL sets how long resolved places are cached (default 24h).
+// A zero or negative TTL disables the cache.
+func WithGeocodeCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.geocodeTTL = ttl
+	}
+}
+
+// Geocode resolves a place using the default Client
+func Geocode(query string) (Coordinates, error) {
+	return defaultClient.Geocode(query)
+}
+
+// Geocode resolves a free-form place such as "Austin, TX", "Toronto" or a
+// ZIP/postal code to coordinates using the Open-Meteo geocoding API. Text
+// after the first comma narrows the match by state/province or country.
+// Results are cached per query; ErrPlaceNotFound is returned if nothing matches.
+func (c *Client) Geocode(query string) (Coordinates, error) {
+	return c.geocode(context.Background(), query)
+}
+
+// geocode resolves query, consulting the cache first
+func (c *Client) geocode(ctx context.Context, query string) (Coordinates, error) {
+	key := strings.ToLower(strings.TrimSpace(query))
+	if coords, ok := c.cachedGeocode(key); ok {
+		return coords, nil
+	}
+
+	name, qualifier, _ := strings.Cut(query, ",")
+	name, qualifier = strings.TrimSpace(name), strings.TrimSpace(qualifier)
+	if name == "" {
+		return Coordinates{}, fmt.Errorf("%w: empty query", ErrPlaceNotFound)
+	}
+	if isPostalCode(name) && len(name) == 10 {
+		name = name[:5] // search ZIP+4 by its five-digit ZIP
+	}
+
+	q := url.Values{}
+	q.Set("name", name)
+	q.Set("count", "10")
+	q.Set("language", "en")
+	q.Set("format", "json")
+
+	var apiResp OpenMeteoGeocodingResponse
+	if err := c.getJSON(ctx, c.geocodingBaseURL+"/v1/search", q, &apiResp); err != nil {
+		return Coordinates{}, err
+	}
+	if len(apiResp.Results) == 0 {
+		return Coordinates{}, fmt.Errorf("%w: %q", ErrPlaceNotFound, query)
+	}
+
+	best := apiResp.Results[0]
+	if qualifier != "" {
+		for _, r := range apiResp.Results {
+			if strings.EqualFold(r.Admin1, qualifier) || strings.EqualFold(r.CountryCode, qualifier) ||
+				strings.EqualFold(r.Country, qualifier) {
+				best = r
+				break
+			}
+		}
+	}
+
+	coords := Coordinates{Lat: best.Latitude, Lon: best.Longitude}
+	c.storeGeocode(key, coords)
+	return coords, nil
+}
+
+// cachedGeocode returns an unexpired cached result for key
+func (c *Client) cachedGeocode(key string) (Coordinates, bool) {
+	c.geocodeMu.Lock()
+	defer c.geocodeMu.Unlock()
+
+	e, ok := c.geocodeCache[key]
+	if !ok || time.Now().After(e.expires) {
+		return Coordinates{}, false
+	}
+	return e.coords, true
+}
+
+// storeGeocode caches a result for key if caching is enabled
+func (c *Client) storeGeocode(key string, coords Coordinates) {
+	if c.geocodeTTL <= 0 {
+		return
+	}
+	c.geocodeMu.Lock()
+	defer c.geocodeMu.Unlock()
+	c.geocodeCache[key] = geocodeEntry{coords: coords, expires: time.Now().Add(c.geocodeTTL)}
+}
+
+// isPostalCode reports whether s looks like a US/MX ZIP or Canadian postal code
+func isPostalCode(s string) bool {
+	return postalCodePattern.MatchString(s)
+}
+
+// FetchWeatherByPlace fetches weather for a free-form place using the default Client
+func FetchWeatherByPlace(query string) (*WeatherData, error) {
+	return defaultClient.FetchWeatherByPlace(query)
+}
+
+// FetchWeatherByPlace geocodes a place name or ZIP/postal code and fetches its weather
+func (c *Client) FetchWeatherByPlace(query string) (*WeatherData, error) {
+	ctx := context.Background()
+	coords, err := c.geocode(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	source := SourceGeocode
+	if name, _, _ := strings.Cut(query, ","); isPostalCode(strings.TrimSpace(name)) {
+		source = SourceZIP
+	}
+	return c.fetchCurrent(ctx, coords, source)
+}
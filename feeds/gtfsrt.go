@@ -0,0 +1,379 @@
+package feeds
+
+import "reef-na/internal/protowire"
+
+// This file decodes the subset of the GTFS-Realtime protobuf schema
+// (gtfs-realtime.proto) used for departures and alerts. Unknown fields and
+// extensions are skipped, as protobuf requires.
+
+// gtfsFeed is a FeedMessage
+type gtfsFeed struct {
+	timestamp uint64
+	entities  []gtfsEntity
+}
+
+// gtfsEntity is a FeedEntity; at most one of tripUpdate and alert is set here
+type gtfsEntity struct {
+	id         string
+	deleted    bool
+	tripUpdate *gtfsTripUpdate
+	alert      *gtfsAlert
+}
+
+// gtfsTrip is a TripDescriptor
+type gtfsTrip struct {
+	tripID, routeID string
+	directionID     uint64
+}
+
+// gtfsTripUpdate is a TripUpdate
+type gtfsTripUpdate struct {
+	trip      gtfsTrip
+	stopTimes []gtfsStopTime
+}
+
+// gtfsStopTime is a StopTimeUpdate
+type gtfsStopTime struct {
+	stopID             string
+	arrival, departure *gtfsStopTimeEvent
+	skipped            bool // schedule_relationship SKIPPED
+}
+
+// gtfsStopTimeEvent is a StopTimeEvent
+type gtfsStopTimeEvent struct {
+	delay int32 // seconds
+	time  int64 // Unix seconds
+}
+
+// gtfsAlert is an Alert
+type gtfsAlert struct {
+	activePeriods []gtfsTimeRange
+	informed      []gtfsEntitySelector
+	cause, effect uint64
+	severity      uint64
+	url           string
+	header        string
+	description   string
+}
+
+// gtfsTimeRange is a TimeRange; zero bounds are open
+type gtfsTimeRange struct {
+	start, end uint64
+}
+
+// gtfsEntitySelector is an EntitySelector
+type gtfsEntitySelector struct {
+	agencyID, routeID, stopID string
+}
+
+// decodeGTFSFeed decodes a FeedMessage
+func decodeGTFSFeed(b []byte) (*gtfsFeed, error) {
+	feed := &gtfsFeed{}
+	err := protowire.Fields(b, func(r *protowire.Reader, num, wire int) (bool, error) {
+		if wire != protowire.Bytes {
+			return false, nil
+		}
+		switch num {
+		case 1: // header
+			v, err := r.Bytes()
+			if err != nil {
+				return true, err
+			}
+			return true, protowire.Fields(v, func(r *protowire.Reader, num, wire int) (bool, error) {
+				if num == 3 && wire == protowire.Varint { // timestamp
+					var err error
+					feed.timestamp, err = r.Varint()
+					return true, err
+				}
+				return false, nil
+			})
+		case 2: // entity
+			v, err := r.Bytes()
+			if err != nil {
+				return true, err
+			}
+			e, err := decodeGTFSEntity(v)
+			if err != nil {
+				return true, err
+			}
+			feed.entities = append(feed.entities, e)
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// decodeGTFSEntity decodes a FeedEntity
+func decodeGTFSEntity(b []byte) (gtfsEntity, error) {
+	var e gtfsEntity
+	err := protowire.Fields(b, func(r *protowire.Reader, num, wire int) (bool, error) {
+		switch {
+		case num == 1 && wire == protowire.Bytes:
+			v, err := r.Bytes()
+			e.id = string(v)
+			return true, err
+		case num == 2 && wire == protowire.Varint:
+			v, err := r.Varint()
+			e.deleted = v != 0
+			return true, err
+		case num == 3 && wire == protowire.Bytes:
+			v, err := r.Bytes()
+			if err != nil {
+				return true, err
+			}
+			e.tripUpdate, err = decodeGTFSTripUpdate(v)
+			return true, err
+		case num == 5 && wire == protowire.Bytes:
+			v, err := r.Bytes()
+			if err != nil {
+				return true, err
+			}
+			e.alert, err = decodeGTFSAlert(v)
+			return true, err
+		}
+		return false, nil
+	})
+	return e, err
+}
+
+// decodeGTFSTripUpdate decodes a TripUpdate
+func decodeGTFSTripUpdate(b []byte) (*gtfsTripUpdate, error) {
+	tu := &gtfsTripUpdate{}
+	err := protowire.Fields(b, func(r *protowire.Reader, num, wire int) (bool, error) {
+		if wire != protowire.Bytes {
+			return false, nil
+		}
+		switch num {
+		case 1: // trip
+			v, err := r.Bytes()
+			if err != nil {
+				return true, err
+			}
+			tu.trip, err = decodeGTFSTrip(v)
+			return true, err
+		case 2: // stop_time_update
+			v, err := r.Bytes()
+			if err != nil {
+				return true, err
+			}
+			st, err := decodeGTFSStopTime(v)
+			tu.stopTimes = append(tu.stopTimes, st)
+			return true, err
+		}
+		return false, nil
+	})
+	return tu, err
+}
+
+// decodeGTFSTrip decodes a TripDescriptor
+func decodeGTFSTrip(b []byte) (gtfsTrip, error) {
+	var t gtfsTrip
+	err := protowire.Fields(b, func(r *protowire.Reader, num, wire int) (bool, error) {
+		switch {
+		case num == 1 && wire == protowire.Bytes:
+			v, err := r.Bytes()
+			t.tripID = string(v)
+			return true, err
+		case num == 5 && wire == protowire.Bytes:
+			v, err := r.Bytes()
+			t.routeID = string(v)
+			return true, err
+		case num == 6 && wire == protowire.Varint:
+			var err error
+			t.directionID, err = r.Varint()
+			return true, err
+		}
+		return false, nil
+	})
+	return t, err
+}
+
+// decodeGTFSStopTime decodes a StopTimeUpdate
+func decodeGTFSStopTime(b []byte) (gtfsStopTime, error) {
+	var st gtfsStopTime
+	err := protowire.Fields(b, func(r *protowire.Reader, num, wire int) (bool, error) {
+		switch {
+		case (num == 2 || num == 3) && wire == protowire.Bytes:
+			v, err := r.Bytes()
+			if err != nil {
+				return true, err
+			}
+			ev, err := decodeGTFSStopTimeEvent(v)
+			if num == 2 {
+				st.arrival = ev
+			} else {
+				st.departure = ev
+			}
+			return true, err
+		case num == 4 && wire == protowire.Bytes:
+			v, err := r.Bytes()
+			st.stopID = string(v)
+			return true, err
+		case num == 5 && wire == protowire.Varint:
+			v, err := r.Varint()
+			st.skipped = v == 1 // SKIPPED
+			return true, err
+		}
+		return false, nil
+	})
+	return st, err
+}
+
+// decodeGTFSStopTimeEvent decodes a StopTimeEvent
+func decodeGTFSStopTimeEvent(b []byte) (*gtfsStopTimeEvent, error) {
+	ev := &gtfsStopTimeEvent{}
+	err := protowire.Fields(b, func(r *protowire.Reader, num, wire int) (bool, error) {
+		if wire != protowire.Varint {
+			return false, nil
+		}
+		switch num {
+		case 1: // delay, int32
+			v, err := r.Varint()
+			ev.delay = int32(v)
+			return true, err
+		case 2: // time, int64
+			v, err := r.Varint()
+			ev.time = int64(v)
+			return true, err
+		}
+		return false, nil
+	})
+	return ev, err
+}
+
+// decodeGTFSAlert decodes an Alert
+func decodeGTFSAlert(b []byte) (*gtfsAlert, error) {
+	a := &gtfsAlert{}
+	err := protowire.Fields(b, func(r *protowire.Reader, num, wire int) (bool, error) {
+		if wire == protowire.Varint {
+			var err error
+			switch num {
+			case 6:
+				a.cause, err = r.Varint()
+			case 7:
+				a.effect, err = r.Varint()
+			case 14:
+				a.severity, err = r.Varint()
+			default:
+				return false, nil
+			}
+			return true, err
+		}
+		if wire != protowire.Bytes {
+			return false, nil
+		}
+		switch num {
+		case 1, 5, 8, 10, 11:
+		default:
+			return false, nil
+		}
+		v, err := r.Bytes()
+		if err != nil {
+			return true, err
+		}
+		switch num {
+		case 1:
+			tr, err := decodeGTFSTimeRange(v)
+			a.activePeriods = append(a.activePeriods, tr)
+			return true, err
+		case 5:
+			sel, err := decodeGTFSEntitySelector(v)
+			a.informed = append(a.informed, sel)
+			return true, err
+		case 8:
+			a.url, err = decodeTranslatedString(v)
+		case 10:
+			a.header, err = decodeTranslatedString(v)
+		case 11:
+			a.description, err = decodeTranslatedString(v)
+		}
+		return true, err
+	})
+	return a, err
+}
+
+// decodeGTFSTimeRange decodes a TimeRange
+func decodeGTFSTimeRange(b []byte) (gtfsTimeRange, error) {
+	var tr gtfsTimeRange
+	err := protowire.Fields(b, func(r *protowire.Reader, num, wire int) (bool, error) {
+		if wire != protowire.Varint || (num != 1 && num != 2) {
+			return false, nil
+		}
+		v, err := r.Varint()
+		if num == 1 {
+			tr.start = v
+		} else {
+			tr.end = v
+		}
+		return true, err
+	})
+	return tr, err
+}
+
+// decodeGTFSEntitySelector decodes an EntitySelector
+func decodeGTFSEntitySelector(b []byte) (gtfsEntitySelector, error) {
+	var sel gtfsEntitySelector
+	err := protowire.Fields(b, func(r *protowire.Reader, num, wire int) (bool, error) {
+		if wire != protowire.Bytes {
+			return false, nil
+		}
+		var dst *string
+		switch num {
+		case 1:
+			dst = &sel.agencyID
+		case 2:
+			dst = &sel.routeID
+		case 5:
+			dst = &sel.stopID
+		default:
+			return false, nil
+		}
+		v, err := r.Bytes()
+		*dst = string(v)
+		return true, err
+	})
+	return sel, err
+}
+
+// decodeTranslatedString returns the English translation of a
+// TranslatedString, or the first one if there is no English text
+func decodeTranslatedString(b []byte) (string, error) {
+	var first, english string
+	err := protowire.Fields(b, func(r *protowire.Reader, num, wire int) (bool, error) {
+		if num != 1 || wire != protowire.Bytes {
+			return false, nil
+		}
+		v, err := r.Bytes()
+		if err != nil {
+			return true, err
+		}
+		var text, lang string
+		err = protowire.Fields(v, func(r *protowire.Reader, num, wire int) (bool, error) {
+			if wire != protowire.Bytes || (num != 1 && num != 2) {
+				return false, nil
+			}
+			v, err := r.Bytes()
+			if num == 1 {
+				text = string(v)
+			} else {
+				lang = string(v)
+			}
+			return true, err
+		})
+		if first == "" {
+			first = text
+		}
+		if english == "" && (lang == "" || lang == "en" || len(lang) > 2 && lang[:3] == "en-") {
+			english = text
+		}
+		return true, err
+	})
+	if english != "" {
+		return english, err
+	}
+	return first, err
+}
@@ -0,0 +1,215 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxHistoricalRangeDays caps archive queries to keep responses reasonably sized
+const maxHistoricalRangeDays = 366
+
+// archiveFirstYear is the year the Open-Meteo archive starts, on January 1
+const archiveFirstYear = 1940
+
+// ErrInvalidDateRange is returned for date ranges an endpoint cannot serve
+var ErrInvalidDateRange = errors.New("invalid date range")
+
+// observationDailyVariables are the daily variables requested by FetchHistoricalWeather
+const observationDailyVariables = "weather_code,temperature_2m_max,temperature_2m_min,temperature_2m_mean," +
+	"precipitation_sum,rain_sum,snowfall_sum,wind_speed_10m_max,wind_gusts_10m_max"
+
+// DailyObservation is the observed weather for a single day
+type DailyObservation struct {
+	Date            time.Time `json:"date"`
+	Summary         string    `json:"summary"`
+	WeatherCode     int       `json:"weatherCode"`
+	HighC           float64   `json:"highC"`
+	LowC            float64   `json:"lowC"`
+	MeanC           float64   `json:"meanC"`           // midpoint of high and low if the archive has no mean
+	PrecipitationMM float64   `json:"precipitationMM"` // rain, showers and snowfall water equivalent
+	RainMM          float64   `json:"rainMM"`
+	SnowfallCM      float64   `json:"snowfallCM"`
+	WindSpeedMaxKmh float64   `json:"windSpeedMaxKmh"`
+	WindGustsMaxKmh float64   `json:"windGustsMaxKmh"`
+}
+
+// HistoricalWeather is the observed daily weather at a location over a date range
+type HistoricalWeather struct {
+	Latitude  float64            `json:"latitude"`
+	Longitude float64            `json:"longitude"`
+	Timezone  string             `json:"timezone"`
+	Days      []DailyObservation `json:"days"`
+}
+
+// Day returns the observation for the calendar date of t, such as the same
+// day last year
+func (h *HistoricalWeather) Day(t time.Time) (DailyObservation, bool) {
+	y, m, d := t.Date()
+	for _, day := range h.Days {
+		if dy, dm, dd := day.Date.Date(); dy == y && dm == m && dd == d {
+			return day, true
+		}
+	}
+	return DailyObservation{}, false
+}
+
+// OpenMeteoArchiveResponse represents a daily response from the Open-Meteo
+// archive API. Values are null for days the archive does not cover yet.
+type OpenMeteoArchiveResponse struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	Timezone         string  `json:"timezone"`
+	UTCOffsetSeconds int     `json:"utc_offset_seconds"`
+	Daily            struct {
+		Time             []Timestamp `json:"time"`
+		WeatherCode      []*int      `json:"weather_code"`
+		TemperatureMax   []*float64  `json:"temperature_2m_max"`
+		TemperatureMin   []*float64  `json:"temperature_2m_min"`
+		TemperatureMean  []*float64  `json:"temperature_2m_mean"`
+		PrecipitationSum []*float64  `json:"precipitation_sum"`
+		RainSum          []*float64  `json:"rain_sum"`
+		SnowfallSum      []*float64  `json:"snowfall_sum"`
+		WindSpeedMax     []*float64  `json:"wind_speed_10m_max"`
+		WindGustsMax     []*float64  `json:"wind_gusts_10m_max"`
+	} `json:"daily"`
+}
+
+// validateHistoricalRange truncates start and end to days and checks that
+// the range lies within the archive and is no longer than maxHistoricalRangeDays
+func validateHistoricalRange(start, end time.Time) (time.Time, time.Time, error) {
+	start, end = truncateDay(start), truncateDay(end)
+	switch {
+	case start.After(end):
+		return start, end, fmt.Errorf("%w: historical range start must not be after end", ErrInvalidDateRange)
+	case !end.Before(truncateDay(time.Now())):
+		return start, end, fmt.Errorf("%w: historical range must be entirely in the past", ErrInvalidDateRange)
+	case start.Year() < archiveFirstYear:
+		return start, end, fmt.Errorf("%w: historical range must not start before %d", ErrInvalidDateRange, archiveFirstYear)
+	}
+	if days := int(end.Sub(start).Hours()/24) + 1; days > maxHistoricalRangeDays {
+		return start, end, fmt.Errorf("%w: historical range of %d days exceeds maximum of %d", ErrInvalidDateRange, days, maxHistoricalRangeDays)
+	}
+	return start, end, nil
+}
+
+// FetchHistoricalRange fetches a historical date range using the default Client
+func FetchHistoricalRange(country string, start, end time.Time) ([]DailyForecast, error) {
+	return defaultClient.FetchHistoricalRange(country, start, end)
+}
+
+// FetchHistoricalRange fetches observed daily highs, lows and weather codes for
+// every day between start and end (inclusive) using the Open-Meteo archive API
+func (c *Client) FetchHistoricalRange(country string, start, end time.Time) ([]DailyForecast, error) {
+	start, end, err := validateHistoricalRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fetchDailyRange(context.Background(), c.archiveBaseURL+"/v1/archive", archiveDailyVariables, country, start, end)
+}
+
+// FetchHistoricalWeather fetches historical observations using the default Client
+func FetchHistoricalWeather(coords Coordinates, start, end time.Time) (*HistoricalWeather, error) {
+	return defaultClient.FetchHistoricalWeather(coords, start, end)
+}
+
+// FetchHistoricalWeather fetches the observed daily weather at coords for
+// every day between start and end (inclusive) from the Open-Meteo archive API,
+// such as the same day last year or the past month for a trend. The range
+// must be in the past, from 1940 on and at most 366 days long; the archive
+// lags a few days behind, and days it does not cover yet are left out.
+func (c *Client) FetchHistoricalWeather(coords Coordinates, start, end time.Time) (*HistoricalWeather, error) {
+	return c.FetchHistoricalWeatherContext(context.Background(), coords, start, end)
+}
+
+// FetchHistoricalWeatherContext is FetchHistoricalWeather with a context
+func (c *Client) FetchHistoricalWeatherContext(ctx context.Context, coords Coordinates, start, end time.Time) (*HistoricalWeather, error) {
+	if err := coords.Validate(); err != nil {
+		return nil, err
+	}
+	start, end, err := validateHistoricalRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	q := coords.query()
+	q.Set("start_date", start.Format(dateLayout))
+	q.Set("end_date", end.Format(dateLayout))
+	q.Set("daily", observationDailyVariables)
+	q.Set("timezone", "auto")
+
+	var apiResp OpenMeteoArchiveResponse
+	if err := c.getOpenMeteo(ctx, c.archiveBaseURL+"/v1/archive", q, &apiResp); err != nil {
+		return nil, err
+	}
+	days, err := apiResp.toDailyObservations()
+	if err != nil {
+		return nil, err
+	}
+	for i := range days {
+		days[i].HighC = c.roundTemperature(days[i].HighC)
+		days[i].LowC = c.roundTemperature(days[i].LowC)
+		days[i].MeanC = c.roundTemperature(days[i].MeanC)
+		days[i].Summary = c.summary(days[i].WeatherCode, days[i].Summary)
+	}
+
+	return &HistoricalWeather{
+		Latitude:  apiResp.Latitude,
+		Longitude: apiResp.Longitude,
+		Timezone:  apiResp.Timezone,
+		Days:      days,
+	}, nil
+}
+
+// toDailyObservations converts the parallel daily arrays into DailyObservation
+// entries, skipping days without temperatures
+func (r *OpenMeteoArchiveResponse) toDailyObservations() ([]DailyObservation, error) {
+	d := r.Daily
+	n := len(d.Time)
+	for _, l := range []int{len(d.WeatherCode), len(d.TemperatureMax), len(d.TemperatureMin), len(d.TemperatureMean),
+		len(d.PrecipitationSum), len(d.RainSum), len(d.SnowfallSum), len(d.WindSpeedMax), len(d.WindGustsMax)} {
+		if l != n {
+			return nil, withKind(ErrDecode, errors.New("historical weather response has mismatched array lengths"))
+		}
+	}
+
+	value := func(v *float64) float64 {
+		if v == nil {
+			return 0
+		}
+		return *v
+	}
+	loc := apiLocation(r.Timezone, r.UTCOffsetSeconds)
+	days := make([]DailyObservation, 0, n)
+	for i := range n {
+		if d.TemperatureMax[i] == nil || d.TemperatureMin[i] == nil {
+			continue // not in the archive yet
+		}
+		date, err := d.Time[i].In(loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse daily date: %w", err)
+		}
+		day := DailyObservation{
+			Date:            date,
+			HighC:           *d.TemperatureMax[i],
+			LowC:            *d.TemperatureMin[i],
+			MeanC:           (*d.TemperatureMax[i] + *d.TemperatureMin[i]) / 2,
+			PrecipitationMM: value(d.PrecipitationSum[i]),
+			RainMM:          value(d.RainSum[i]),
+			SnowfallCM:      value(d.SnowfallSum[i]),
+			WindSpeedMaxKmh: value(d.WindSpeedMax[i]),
+			WindGustsMaxKmh: value(d.WindGustsMax[i]),
+		}
+		if mean := d.TemperatureMean[i]; mean != nil {
+			day.MeanC = *mean
+		}
+		if code := d.WeatherCode[i]; code != nil {
+			day.WeatherCode = *code
+			day.Summary = describeWeatherCode(*code)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
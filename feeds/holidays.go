@@ -0,0 +1,188 @@
+package feeds
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Holiday is a national public holiday. Date is the holiday itself; when it
+// falls on a weekend and the day off moves, Observed is the day off.
+type Holiday struct {
+	Country   string    `json:"country"`
+	Name      string    `json:"name"`
+	LocalName string    `json:"localName"`
+	Date      time.Time `json:"date"`
+	Observed  time.Time `json:"observed,omitzero"`
+	Today     bool      `json:"today"`    // Date or Observed is today in the country
+	ThisWeek  bool      `json:"thisWeek"` // Date or Observed is in the current Monday–Sunday week
+}
+
+// holidayCalendar computes a country's public holidays for a year
+type holidayCalendar struct {
+	zone     string // where "today" is judged
+	holidays func(year int) []Holiday
+}
+
+// holidayCalendars are the built-in national calendars
+var holidayCalendars = map[string]holidayCalendar{
+	"US": {zone: "America/New_York", holidays: usHolidays},
+	"CA": {zone: "America/Toronto", holidays: caHolidays},
+	"MX": {zone: "America/Mexico_City", holidays: mxHolidays},
+}
+
+// usHolidays are the US federal holidays; weekend holidays are observed on
+// the nearest weekday
+func usHolidays(year int) []Holiday {
+	days := []Holiday{
+		{Name: "New Year's Day", Date: date(year, time.January, 1)},
+		{Name: "Martin Luther King Jr. Day", Date: nthWeekday(year, time.January, time.Monday, 3)},
+		{Name: "Washington's Birthday", Date: nthWeekday(year, time.February, time.Monday, 3)},
+		{Name: "Memorial Day", Date: nthWeekday(year, time.May, time.Monday, -1)},
+		{Name: "Juneteenth", Date: date(year, time.June, 19)},
+		{Name: "Independence Day", Date: date(year, time.July, 4)},
+		{Name: "Labor Day", Date: nthWeekday(year, time.September, time.Monday, 1)},
+		{Name: "Columbus Day", Date: nthWeekday(year, time.October, time.Monday, 2)},
+		{Name: "Veterans Day", Date: date(year, time.November, 11)},
+		{Name: "Thanksgiving Day", Date: nthWeekday(year, time.November, time.Thursday, 4)},
+		{Name: "Christmas Day", Date: date(year, time.December, 25)},
+	}
+	if year < 2021 {
+		days = slices.DeleteFunc(days, func(h Holiday) bool { return h.Name == "Juneteenth" })
+	}
+	for i := range days {
+		days[i].LocalName = days[i].Name
+		if obs := observedNearest(days[i].Date); !obs.Equal(days[i].Date) {
+			days[i].Observed = obs
+		}
+	}
+	return days
+}
+
+// caHolidays are the Canadian federal statutory holidays; weekend holidays
+// are observed on the following weekday
+func caHolidays(year int) []Holiday {
+	days := []Holiday{
+		{Name: "New Year's Day", LocalName: "Jour de l'An", Date: date(year, time.January, 1)},
+		{Name: "Good Friday", LocalName: "Vendredi saint", Date: easterSunday(year).AddDate(0, 0, -2)},
+		{Name: "Victoria Day", LocalName: "Fête de la Reine", Date: weekdayOnOrBefore(date(year, time.May, 24), time.Monday)},
+		{Name: "Canada Day", LocalName: "Fête du Canada", Date: date(year, time.July, 1)},
+		{Name: "Labour Day", LocalName: "Fête du Travail", Date: nthWeekday(year, time.September, time.Monday, 1)},
+		{Name: "National Day for Truth and Reconciliation", LocalName: "Journée nationale de la vérité et de la réconciliation", Date: date(year, time.September, 30)},
+		{Name: "Thanksgiving", LocalName: "Action de grâce", Date: nthWeekday(year, time.October, time.Monday, 2)},
+		{Name: "Remembrance Day", LocalName: "Jour du Souvenir", Date: date(year, time.November, 11)},
+		{Name: "Christmas Day", LocalName: "Noël", Date: date(year, time.December, 25)},
+		{Name: "Boxing Day", LocalName: "Lendemain de Noël", Date: date(year, time.December, 26)},
+	}
+	if year < 2021 {
+		days = slices.DeleteFunc(days, func(h Holiday) bool { return h.Name == "National Day for Truth and Reconciliation" })
+	}
+	dates := make([]time.Time, len(days))
+	for i, h := range days {
+		dates[i] = h.Date
+	}
+	for i, obs := range observedForward(dates) {
+		if !obs.Equal(days[i].Date) {
+			days[i].Observed = obs
+		}
+	}
+	return days
+}
+
+// mxHolidays are Mexico's mandatory rest days (Ley Federal del Trabajo,
+// art. 74). Weekend holidays are not moved.
+func mxHolidays(year int) []Holiday {
+	days := []Holiday{
+		{Name: "New Year's Day", LocalName: "Año Nuevo", Date: date(year, time.January, 1)},
+		{Name: "Constitution Day", LocalName: "Día de la Constitución", Date: nthWeekday(year, time.February, time.Monday, 1)},
+		{Name: "Benito Juárez's Birthday", LocalName: "Natalicio de Benito Juárez", Date: nthWeekday(year, time.March, time.Monday, 3)},
+		{Name: "Labour Day", LocalName: "Día del Trabajo", Date: date(year, time.May, 1)},
+		{Name: "Independence Day", LocalName: "Día de la Independencia", Date: date(year, time.September, 16)},
+		{Name: "Revolution Day", LocalName: "Día de la Revolución", Date: nthWeekday(year, time.November, time.Monday, 3)},
+		{Name: "Christmas Day", LocalName: "Navidad", Date: date(year, time.December, 25)},
+	}
+	// The presidential inauguration every six years is a rest day
+	if year >= 2024 && year%6 == 0 {
+		days = append(days, Holiday{Name: "Inauguration Day", LocalName: "Transmisión del Poder Ejecutivo Federal", Date: date(year, time.October, 1)})
+	}
+	return days
+}
+
+// Holidays returns a country's (US, CA or MX) public holidays for a year in date order
+func Holidays(country string, year int) ([]Holiday, error) {
+	return holidaysAt(country, year, time.Now())
+}
+
+// UpcomingHolidays returns a country's public holidays from today through
+// the next 12 months, so a dashboard can show "Markets closed — Thanksgiving"
+// from the first entry's Today flag
+func UpcomingHolidays(country string) ([]Holiday, error) {
+	return upcomingHolidays(country, time.Now())
+}
+
+// upcomingHolidays returns the holidays in the year starting on now's date in the country
+func upcomingHolidays(country string, now time.Time) ([]Holiday, error) {
+	cal, err := holidayCalendarFor(country)
+	if err != nil {
+		return nil, err
+	}
+	today := civilDate(now.In(cal.location()))
+
+	var upcoming []Holiday
+	for _, year := range []int{today.Year(), today.Year() + 1} {
+		days, err := holidaysAt(country, year, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range days {
+			day := h.Date
+			if !h.Observed.IsZero() && h.Observed.After(day) {
+				day = h.Observed
+			}
+			if !day.Before(today) && day.Before(today.AddDate(1, 0, 0)) {
+				upcoming = append(upcoming, h)
+			}
+		}
+	}
+	return upcoming, nil
+}
+
+// holidaysAt returns a year's holidays with Today and ThisWeek judged at now
+func holidaysAt(country string, year int, now time.Time) ([]Holiday, error) {
+	cal, err := holidayCalendarFor(country)
+	if err != nil {
+		return nil, err
+	}
+	today := civilDate(now.In(cal.location()))
+	weekStart := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	inWeek := func(d time.Time) bool { return !d.IsZero() && !d.Before(weekStart) && d.Before(weekEnd) }
+
+	days := cal.holidays(year)
+	for i := range days {
+		h := &days[i]
+		h.Country = strings.ToUpper(country)
+		h.Today = h.Date.Equal(today) || h.Observed.Equal(today)
+		h.ThisWeek = inWeek(h.Date) || inWeek(h.Observed)
+	}
+	slices.SortStableFunc(days, func(a, b Holiday) int { return a.Date.Compare(b.Date) })
+	return days, nil
+}
+
+// holidayCalendarFor returns a country's calendar
+func holidayCalendarFor(country string) (holidayCalendar, error) {
+	cal, ok := holidayCalendars[strings.ToUpper(country)]
+	if !ok {
+		return holidayCalendar{}, fmt.Errorf("%w: no holiday calendar for %q", ErrUnknownCountry, country)
+	}
+	return cal, nil
+}
+
+// location returns the calendar's time zone, or UTC if it cannot be loaded
+func (h holidayCalendar) location() *time.Location {
+	if loc, err := time.LoadLocation(h.zone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
@@ -0,0 +1,201 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxForecastDays is the furthest ahead Open-Meteo forecasts
+const maxForecastDays = 16
+
+// maxHourlyForecastHours is the longest hourly forecast FetchHourlyWeather returns
+const maxHourlyForecastHours = 48
+
+// HourlyForecast represents the forecast conditions for a single hour
+type HourlyForecast struct {
+	Time            time.Time `json:"time"`
+	Summary         string    `json:"summary"`
+	WeatherCode     int       `json:"weatherCode"`
+	TemperatureC    float64   `json:"temperatureC"`
+	FeelsLikeC      float64   `json:"feelsLikeC"`
+	PrecipitationMM float64   `json:"precipitationMM"`
+}
+
+// OpenMeteoHourlyResponse represents an hourly response from Open-Meteo
+type OpenMeteoHourlyResponse struct {
+	Timezone         string `json:"timezone"`
+	UTCOffsetSeconds int    `json:"utc_offset_seconds"`
+	Hourly           struct {
+		Time                []Timestamp `json:"time"`
+		Temperature         []float64   `json:"temperature_2m"`
+		ApparentTemperature []float64   `json:"apparent_temperature"`
+		Precipitation       []float64   `json:"precipitation"`
+		WeatherCode         []int       `json:"weather_code"`
+	} `json:"hourly"`
+}
+
+// FetchHourlyWeather fetches the hourly forecast using the default Client
+func FetchHourlyWeather(country string, hours int) ([]HourlyForecast, error) {
+	return defaultClient.FetchHourlyWeather(country, hours)
+}
+
+// FetchHourlyWeather fetches temperature, apparent temperature, precipitation
+// and weather code for the next hours (1–48), starting with the current hour
+func (c *Client) FetchHourlyWeather(country string, hours int) ([]HourlyForecast, error) {
+	if hours < 1 || hours > maxHourlyForecastHours {
+		return nil, fmt.Errorf("forecast hours must be between 1 and %d, got %d", maxHourlyForecastHours, hours)
+	}
+
+	coords, _, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+	// Three days always covers the next 48 hours from any time of day
+	all, err := c.fetchHourly(context.Background(), coords, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i, h := range all {
+		if h.Time.Add(time.Hour).After(now) {
+			return all[i:min(i+hours, len(all))], nil
+		}
+	}
+	return nil, nil
+}
+
+// FetchWeatherAtTime fetches the forecast for a specific time using the default Client
+func FetchWeatherAtTime(country string, when time.Time) (*WeatherData, error) {
+	return defaultClient.FetchWeatherAtTime(country, when)
+}
+
+// FetchWeatherAtTime returns the forecast conditions for the hour nearest to when.
+// It returns an error if when falls outside the available forecast window.
+func (c *Client) FetchWeatherAtTime(country string, when time.Time) (*WeatherData, error) {
+	coords, source, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+	// finishWeather rounds and localizes the chosen hour, so fetch it as reported
+	hours, err := c.fetchHourlyRaw(context.Background(), coords, maxForecastDays)
+	if err != nil {
+		return nil, err
+	}
+	if len(hours) == 0 {
+		return nil, withKind(ErrDecode, errors.New("hourly forecast is empty"))
+	}
+
+	first, last := hours[0].Time, hours[len(hours)-1].Time
+	if when.Before(first.Add(-30*time.Minute)) || when.After(last.Add(30*time.Minute)) {
+		return nil, fmt.Errorf("time %s is outside the forecast window %s to %s",
+			when.Format(time.RFC3339), first.Format(time.RFC3339), last.Format(time.RFC3339))
+	}
+
+	nearest := hours[0]
+	for _, h := range hours[1:] {
+		if absDuration(h.Time.Sub(when)) < absDuration(nearest.Time.Sub(when)) {
+			nearest = h
+		}
+	}
+
+	w := &WeatherData{
+		Summary:         nearest.Summary,
+		WeatherCode:     nearest.WeatherCode,
+		TemperatureC:    nearest.TemperatureC,
+		FeelsLikeC:      nearest.FeelsLikeC,
+		PrecipitationMM: nearest.PrecipitationMM,
+		Latitude:        coords.Lat,
+		Longitude:       coords.Lon,
+		Source:          source,
+		ObservedAt:      nearest.Time, // so the icon shows day or night at that hour
+	}
+	c.finishWeather(w)
+	return w, nil
+}
+
+// fetchHourly fetches the hourly forecast at coords for the given number of
+// days, with temperatures rounded and summaries in the Client's language
+func (c *Client) fetchHourly(ctx context.Context, coords Coordinates, days int) ([]HourlyForecast, error) {
+	hours, err := c.fetchHourlyRaw(ctx, coords, days)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hours {
+		hours[i].TemperatureC = c.roundTemperature(hours[i].TemperatureC)
+		hours[i].FeelsLikeC = c.roundTemperature(hours[i].FeelsLikeC)
+		hours[i].Summary = c.summary(hours[i].WeatherCode, hours[i].Summary)
+	}
+	return hours, nil
+}
+
+// fetchHourlyRaw fetches the hourly forecast at coords for the given number
+// of days as Open-Meteo reports it
+func (c *Client) fetchHourlyRaw(ctx context.Context, coords Coordinates, days int) ([]HourlyForecast, error) {
+	q := coords.query()
+	q.Set("hourly", "temperature_2m,apparent_temperature,precipitation,weather_code")
+	q.Set("forecast_days", fmt.Sprint(days))
+	q.Set("timezone", "auto")
+
+	var apiResp OpenMeteoHourlyResponse
+	if err := c.getOpenMeteo(ctx, c.forecastBaseURL+"/v1/forecast", q, &apiResp); err != nil {
+		return nil, err
+	}
+	return apiResp.toHourlyForecasts()
+}
+
+// toHourlyForecasts converts the parallel hourly arrays into HourlyForecast entries
+func (r *OpenMeteoHourlyResponse) toHourlyForecasts() ([]HourlyForecast, error) {
+	h := r.Hourly
+	n := len(h.Time)
+	if len(h.Temperature) != n || len(h.ApparentTemperature) != n ||
+		len(h.Precipitation) != n || len(h.WeatherCode) != n {
+		return nil, withKind(ErrDecode, errors.New("hourly weather response has mismatched array lengths"))
+	}
+
+	loc := apiLocation(r.Timezone, r.UTCOffsetSeconds)
+	hours := make([]HourlyForecast, 0, n)
+	for i := range n {
+		t, err := h.Time[i].In(loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse hourly time: %w", err)
+		}
+		hours = append(hours, HourlyForecast{
+			Time:            t,
+			Summary:         describeWeatherCode(h.WeatherCode[i]),
+			WeatherCode:     h.WeatherCode[i],
+			TemperatureC:    h.Temperature[i],
+			FeelsLikeC:      h.ApparentTemperature[i],
+			PrecipitationMM: h.Precipitation[i],
+		})
+	}
+	return hours, nil
+}
+
+// PeakTemperatureTime returns the times of the hottest and coldest hours in
+// the forecast (the earliest hour wins ties). Empty input yields zero times.
+func PeakTemperatureTime(hours []HourlyForecast) (hot, cold time.Time) {
+	if len(hours) == 0 {
+		return time.Time{}, time.Time{}
+	}
+	hottest, coldest := hours[0], hours[0]
+	for _, h := range hours[1:] {
+		if h.TemperatureC > hottest.TemperatureC {
+			hottest = h
+		}
+		if h.TemperatureC < coldest.TemperatureC {
+			coldest = h
+		}
+	}
+	return hottest.Time, coldest.Time
+}
+
+// absDuration returns the absolute value of d
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
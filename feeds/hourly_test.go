@@ -0,0 +1,99 @@
+package feeds
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// hourlyServer serves an hourly forecast for New York on 21 June 2026, every
+// hour clear at 20°C
+func hourlyServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var times []string
+	var temps, precipitation []float64
+	var codes []int
+	for h := range 24 {
+		times = append(times, fmt.Sprintf("2026-06-21T%02d:00", h))
+		temps = append(temps, 20)
+		precipitation = append(precipitation, 0)
+		codes = append(codes, 0)
+	}
+	body, err := json.Marshal(map[string]any{
+		"timezone":           "America/New_York",
+		"utc_offset_seconds": -4 * 3600,
+		"hourly": map[string]any{
+			"time":                 times,
+			"temperature_2m":       temps,
+			"apparent_temperature": temps,
+			"precipitation":        precipitation,
+			"weather_code":         codes,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/forecast" || r.URL.Query().Get("hourly") == "" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchWeatherAtTimeIcon(t *testing.T) {
+	srv := hourlyServer(t)
+	c := NewClient(WithBaseURL(srv.URL))
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+
+	tests := []struct {
+		hour int
+		want string
+	}{
+		{3, IconClearNight},
+		{12, IconClearDay},
+		{22, IconClearNight},
+	}
+	for _, tt := range tests {
+		when := time.Date(2026, 6, 21, tt.hour, 10, 0, 0, newYork)
+		w, err := c.FetchWeatherAtTime("US", when)
+		if err != nil {
+			t.Fatalf("FetchWeatherAtTime(%v): %v", when, err)
+		}
+		if w.Icon != tt.want {
+			t.Errorf("FetchWeatherAtTime(%v).Icon = %q, want %q", when, w.Icon, tt.want)
+		}
+		if w.ConditionSeverity != ConditionSeverityNone {
+			t.Errorf("FetchWeatherAtTime(%v).ConditionSeverity = %q, want %q", when, w.ConditionSeverity, ConditionSeverityNone)
+		}
+		if want := when.Truncate(time.Hour); !w.ObservedAt.Equal(want) {
+			t.Errorf("FetchWeatherAtTime(%v).ObservedAt = %v, want %v", when, w.ObservedAt, want)
+		}
+	}
+}
+
+func TestFetchWeatherAtTimeImperial(t *testing.T) {
+	srv := hourlyServer(t)
+	c := NewClient(WithBaseURL(srv.URL), WithUnits(UnitsImperial), WithTemperaturePrecision(0))
+	when := time.Date(2026, 6, 21, 16, 0, 0, 0, time.UTC)
+	w, err := c.FetchWeatherAtTime("US", when)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Imperial == nil {
+		t.Fatal("FetchWeatherAtTime with UnitsImperial returned no Imperial block")
+	}
+	if w.TemperatureC != 20 || w.Imperial.TemperatureF != 68 {
+		t.Errorf("temperature = %v°C, %v°F, want 20°C, 68°F", w.TemperatureC, w.Imperial.TemperatureF)
+	}
+}
@@ -0,0 +1,298 @@
+package feeds
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// Language selects the language of descriptions such as weather summaries,
+// alert severities and air quality categories
+type Language string
+
+// Supported languages: the official languages of North America
+const (
+	LanguageEnglish Language = "en" // the default
+	LanguageSpanish Language = "es"
+	LanguageFrench  Language = "fr"
+)
+
+// WithLanguage selects the language of the descriptions the Client returns:
+// WeatherData, forecast and historical summaries, WeatherAlert severity
+// labels and air quality categories. Codes and severity values stay the
+// same in every language. Summaries in other languages come from the WMO
+// code, so they replace provider text such as ECCC's conditions.
+func WithLanguage(lang Language) Option {
+	return func(c *Client) {
+		c.language = lang
+	}
+}
+
+// ParseLanguage returns the supported language of a tag such as "es",
+// "fr-CA" or "en_US", ignoring case and region
+func ParseLanguage(tag string) (Language, error) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	base, _, _ = strings.Cut(base, "_")
+	switch lang := Language(base); lang {
+	case LanguageEnglish, LanguageSpanish, LanguageFrench:
+		return lang, nil
+	}
+	return "", fmt.Errorf("unsupported language %q, want en, es or fr", tag)
+}
+
+// MatchLanguage returns the first supported language in an Accept-Language
+// header value, such as "fr-CA,fr;q=0.9,en;q=0.8", or English if none is.
+// Quality values are not weighed; the header's order is taken as preference.
+func MatchLanguage(acceptLanguage string) Language {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ := strings.Cut(part, ";")
+		if lang, err := ParseLanguage(tag); err == nil {
+			return lang
+		}
+	}
+	return LanguageEnglish
+}
+
+// summary returns the description of a weather code in the Client's
+// language, or english, as the provider described it, for English
+func (c *Client) summary(code int, english string) string {
+	if c.language.english() {
+		return english
+	}
+	return DescribeWeatherCode(code, c.language)
+}
+
+// english reports whether lang is English, the language descriptions are
+// produced in
+func (lang Language) english() bool {
+	return lang == "" || lang == LanguageEnglish
+}
+
+// Weather code descriptions in languages other than English
+var weatherCodeTranslations = map[Language]map[int]string{
+	LanguageSpanish: {
+		0:  "Cielo despejado",
+		1:  "Mayormente despejado",
+		2:  "Parcialmente nublado",
+		3:  "Nublado",
+		45: "Niebla",
+		48: "Niebla con escarcha",
+		51: "Llovizna ligera",
+		53: "Llovizna moderada",
+		55: "Llovizna densa",
+		61: "Lluvia ligera",
+		63: "Lluvia moderada",
+		65: "Lluvia intensa",
+		71: "Nevada ligera",
+		73: "Nevada moderada",
+		75: "Nevada intensa",
+		77: "Granos de nieve",
+		80: "Chubascos ligeros",
+		81: "Chubascos moderados",
+		82: "Chubascos violentos",
+		85: "Chubascos de nieve ligeros",
+		86: "Chubascos de nieve intensos",
+		95: "Tormenta eléctrica",
+		96: "Tormenta eléctrica con granizo ligero",
+		99: "Tormenta eléctrica con granizo fuerte",
+	},
+	LanguageFrench: {
+		0:  "Ciel dégagé",
+		1:  "Généralement dégagé",
+		2:  "Partiellement nuageux",
+		3:  "Couvert",
+		45: "Brouillard",
+		48: "Brouillard givrant",
+		51: "Bruine légère",
+		53: "Bruine modérée",
+		55: "Bruine forte",
+		61: "Pluie faible",
+		63: "Pluie modérée",
+		65: "Pluie forte",
+		71: "Neige faible",
+		73: "Neige modérée",
+		75: "Neige forte",
+		77: "Neige en grains",
+		80: "Averses de pluie faibles",
+		81: "Averses de pluie modérées",
+		82: "Averses de pluie violentes",
+		85: "Averses de neige faibles",
+		86: "Averses de neige fortes",
+		95: "Orage",
+		96: "Orage avec grêle faible",
+		99: "Orage avec grêle forte",
+	},
+}
+
+// unknownWeatherCode describes codes without a description, by language
+var unknownWeatherCode = map[Language]string{
+	LanguageSpanish: "Desconocido",
+	LanguageFrench:  "Inconnu",
+}
+
+// alertSeverityLabels name the alert severities, by language
+var alertSeverityLabels = map[Language]map[string]string{
+	LanguageEnglish: {
+		AlertSeverityExtreme:  "Extreme",
+		AlertSeveritySevere:   "Severe",
+		AlertSeverityModerate: "Moderate",
+		AlertSeverityMinor:    "Minor",
+		AlertSeverityUnknown:  "Unknown",
+	},
+	LanguageSpanish: {
+		AlertSeverityExtreme:  "Extrema",
+		AlertSeveritySevere:   "Grave",
+		AlertSeverityModerate: "Moderada",
+		AlertSeverityMinor:    "Menor",
+		AlertSeverityUnknown:  "Desconocida",
+	},
+	LanguageFrench: {
+		AlertSeverityExtreme:  "Extrême",
+		AlertSeveritySevere:   "Sévère",
+		AlertSeverityModerate: "Modérée",
+		AlertSeverityMinor:    "Mineure",
+		AlertSeverityUnknown:  "Inconnue",
+	},
+}
+
+// aqiCategoryTranslations translate the EPA air quality categories, by
+// language, following the EPA's and AirNow's own translations
+var aqiCategoryTranslations = map[Language]map[string]string{
+	LanguageSpanish: {
+		"Good":                           "Buena",
+		"Moderate":                       "Moderada",
+		"Unhealthy for Sensitive Groups": "Dañina a la salud para grupos sensibles",
+		"Unhealthy":                      "Dañina a la salud",
+		"Very Unhealthy":                 "Muy dañina a la salud",
+		"Hazardous":                      "Peligrosa",
+	},
+	LanguageFrench: {
+		"Good":                           "Bon",
+		"Moderate":                       "Modéré",
+		"Unhealthy for Sensitive Groups": "Mauvais pour les groupes sensibles",
+		"Unhealthy":                      "Mauvais",
+		"Very Unhealthy":                 "Très mauvais",
+		"Hazardous":                      "Dangereux",
+	},
+}
+
+// DescribeWeatherCode returns the description of a WMO weather code in
+// lang. English descriptions follow SetWeatherCodeDescription; unsupported
+// languages get English.
+func DescribeWeatherCode(code int, lang Language) string {
+	descriptions, ok := weatherCodeTranslations[lang]
+	if !ok {
+		return describeWeatherCode(code)
+	}
+	if d, ok := descriptions[code]; ok {
+		return d
+	}
+	return unknownWeatherCode[lang]
+}
+
+// AlertSeverityLabel returns the display name of an alert severity, one of
+// the AlertSeverity constants, in lang
+func AlertSeverityLabel(severity string, lang Language) string {
+	labels, ok := alertSeverityLabels[lang]
+	if !ok {
+		labels = alertSeverityLabels[LanguageEnglish]
+	}
+	if label, ok := labels[severity]; ok {
+		return label
+	}
+	return labels[AlertSeverityUnknown]
+}
+
+// LocalizeAQICategory translates an English EPA category, as AQICategory
+// returns, into lang; other categories are returned unchanged
+func LocalizeAQICategory(category string, lang Language) string {
+	if t, ok := aqiCategoryTranslations[lang][category]; ok {
+		return t
+	}
+	return category
+}
+
+// Localize returns a copy of v with its descriptions in lang, for feed
+// values whose descriptions can be translated: *WeatherData,
+// *ForecastData, []DailyForecast, []HourlyForecast, *HistoricalWeather,
+// []WeatherAlert, *AirQualityData and *Snapshot, whose weather and feeds
+// are localized in turn. Other values, and all values when lang is
+// English, are returned as they are. v itself is not modified, so Localize
+// is safe on shared values such as the Scheduler's snapshots.
+func Localize(v any, lang Language) any {
+	if lang.english() {
+		return v
+	}
+	switch v := v.(type) {
+	case *WeatherData:
+		if v == nil {
+			return v
+		}
+		w := *v
+		w.Summary = DescribeWeatherCode(w.WeatherCode, lang)
+		return &w
+	case *ForecastData:
+		if v == nil {
+			return v
+		}
+		f := *v
+		f.Days = localizeDays(v.Days, lang)
+		return &f
+	case []DailyForecast:
+		return localizeDays(v, lang)
+	case []HourlyForecast:
+		hours := make([]HourlyForecast, len(v))
+		for i, h := range v {
+			h.Summary = DescribeWeatherCode(h.WeatherCode, lang)
+			hours[i] = h
+		}
+		return hours
+	case *HistoricalWeather:
+		if v == nil {
+			return v
+		}
+		h := *v
+		h.Days = make([]DailyObservation, len(v.Days))
+		for i, d := range v.Days {
+			d.Summary = DescribeWeatherCode(d.WeatherCode, lang)
+			h.Days[i] = d
+		}
+		return &h
+	case []WeatherAlert:
+		alerts := make([]WeatherAlert, len(v))
+		for i, a := range v {
+			a.SeverityLabel = AlertSeverityLabel(a.Severity, lang)
+			alerts[i] = a
+		}
+		return alerts
+	case *AirQualityData:
+		if v == nil {
+			return v
+		}
+		aq := *v
+		aq.Category = LocalizeAQICategory(aq.Category, lang)
+		return &aq
+	case *Snapshot:
+		if v == nil {
+			return v
+		}
+		s := *v
+		s.Weather = Localize(v.Weather, lang).(*WeatherData)
+		s.Feeds = maps.Clone(v.Feeds)
+		for name, f := range s.Feeds {
+			s.Feeds[name] = Localize(f, lang)
+		}
+		return &s
+	}
+	return v
+}
+
+// localizeDays returns a copy of days with summaries in lang
+func localizeDays(days []DailyForecast, lang Language) []DailyForecast {
+	out := make([]DailyForecast, len(days))
+	for i, d := range days {
+		d.Summary = DescribeWeatherCode(d.WeatherCode, lang)
+		out[i] = d
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package feeds
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Weather icon names: a stable set for frontends to map to their own
+// artwork. Icons for conditions that look different at night come in -day
+// and -night variants.
+const (
+	IconClearDay          = "clear-day"
+	IconClearNight        = "clear-night"
+	IconPartlyCloudyDay   = "partly-cloudy-day"
+	IconPartlyCloudyNight = "partly-cloudy-night"
+	IconCloudy            = "cloudy"
+	IconFog               = "fog"
+	IconDrizzle           = "drizzle"
+	IconRain              = "rain"
+	IconShowersDay        = "showers-day"
+	IconShowersNight      = "showers-night"
+	IconSleet             = "sleet" // freezing drizzle and freezing rain
+	IconSnow              = "snow"
+	IconSnowShowersDay    = "snow-showers-day"
+	IconSnowShowersNight  = "snow-showers-night"
+	IconThunderstorm      = "thunderstorm"
+	IconThunderstormHail  = "thunderstorm-hail"
+	IconUnknown           = "unknown"
+)
+
+// weatherCodeIcons maps WMO weather codes to their daytime and nighttime icons
+var weatherCodeIcons = map[int][2]string{
+	0:  {IconClearDay, IconClearNight},
+	1:  {IconClearDay, IconClearNight},
+	2:  {IconPartlyCloudyDay, IconPartlyCloudyNight},
+	3:  {IconCloudy, IconCloudy},
+	45: {IconFog, IconFog},
+	48: {IconFog, IconFog},
+	51: {IconDrizzle, IconDrizzle},
+	53: {IconDrizzle, IconDrizzle},
+	55: {IconDrizzle, IconDrizzle},
+	56: {IconSleet, IconSleet},
+	57: {IconSleet, IconSleet},
+	61: {IconRain, IconRain},
+	63: {IconRain, IconRain},
+	65: {IconRain, IconRain},
+	66: {IconSleet, IconSleet},
+	67: {IconSleet, IconSleet},
+	71: {IconSnow, IconSnow},
+	73: {IconSnow, IconSnow},
+	75: {IconSnow, IconSnow},
+	77: {IconSnow, IconSnow},
+	80: {IconShowersDay, IconShowersNight},
+	81: {IconShowersDay, IconShowersNight},
+	82: {IconShowersDay, IconShowersNight},
+	85: {IconSnowShowersDay, IconSnowShowersNight},
+	86: {IconSnowShowersDay, IconSnowShowersNight},
+	95: {IconThunderstorm, IconThunderstorm},
+	96: {IconThunderstormHail, IconThunderstormHail},
+	99: {IconThunderstormHail, IconThunderstormHail},
+}
+
+// WeatherIcon returns the icon name of a WMO weather code by day or night,
+// or IconUnknown for codes without one
+func WeatherIcon(code int, daytime bool) string {
+	icons, ok := weatherCodeIcons[code]
+	switch {
+	case !ok:
+		return IconUnknown
+	case daytime:
+		return icons[0]
+	}
+	return icons[1]
+}
+
+// iconAt returns the icon of w's conditions at t at w's location
+func (w *WeatherData) iconAt(t time.Time) string {
+	return WeatherIcon(w.WeatherCode, IsDaytime(Coordinates{Lat: w.Latitude, Lon: w.Longitude}, t))
+}
+
+// isNightIcon reports whether icon is a nighttime variant
+func isNightIcon(icon string) bool {
+	return strings.HasSuffix(icon, "-night")
+}
+
+// sunriseAltitude is the Sun's altitude, in degrees, at sunrise and sunset:
+// its upper edge on the horizon, allowing for atmospheric refraction
+const sunriseAltitude = -0.833
+
+// IsDaytime reports whether t falls between sunrise and sunset at coords,
+// from the Sun's computed position rather than a fetched SunTimes. Polar
+// day counts as daytime throughout and polar night as nighttime. The
+// approximation is good to about a minute of the true sunrise and sunset.
+func IsDaytime(coords Coordinates, t time.Time) bool {
+	return sunAltitude(coords, t) > sunriseAltitude
+}
+
+// sunAltitude returns the Sun's altitude above the horizon at coords and t,
+// in degrees, using the low-precision solar coordinates of the Astronomical
+// Almanac
+func sunAltitude(coords Coordinates, t time.Time) float64 {
+	const rad = math.Pi / 180
+	d := float64(t.UTC().UnixNano())/float64(24*time.Hour) - 10957.5 // days since J2000.0
+
+	g := (357.529 + 0.98560028*d) * rad                               // mean anomaly
+	q := 280.459 + 0.98564736*d                                       // mean longitude
+	l := (q + 1.915*math.Sin(g) + 0.020*math.Sin(2*g)) * rad          // ecliptic longitude
+	e := (23.439 - 0.00000036*d) * rad                                // obliquity of the ecliptic
+	ra := math.Atan2(math.Cos(e)*math.Sin(l), math.Cos(l))            // right ascension
+	dec := math.Asin(math.Sin(e) * math.Sin(l))                       // declination
+	gmst := math.Mod(18.697374558+24.06570982441908*d, 24) * 15 * rad // sidereal time
+	hourAngle := gmst + coords.Lon*rad - ra
+
+	lat := coords.Lat * rad
+	return math.Asin(math.Sin(lat)*math.Sin(dec)+math.Cos(lat)*math.Cos(dec)*math.Cos(hourAngle)) / rad
+}
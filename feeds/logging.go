@@ -0,0 +1,86 @@
+package feeds
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// Log levels used by the package: Debug for every upstream request and
+// cache lookup, Info for retries and stale responses, Warn for failed
+// requests and feeds, unparseable responses, circuit changes and location
+// fallbacks, Error for panicking feeds.
+
+// logLevel is the minimum level of the package logger, Warn by default
+var logLevel = func() *slog.LevelVar {
+	v := new(slog.LevelVar)
+	v.Set(slog.LevelWarn)
+	return v
+}()
+
+// packageLogger is the logger of Clients created without WithLogger. Until
+// SetLogger is called it writes to whatever slog.Default is at the time.
+var packageLogger atomic.Pointer[slog.Logger]
+
+func init() {
+	packageLogger.Store(slog.New(&levelHandler{level: logLevel}))
+}
+
+// SetLogger sets the logger of every Client created without WithLogger,
+// including the default Client, filtered to SetLogLevel's level. The
+// default is slog.Default.
+func SetLogger(l *slog.Logger) {
+	packageLogger.Store(slog.New(&levelHandler{level: logLevel, next: l.Handler()}))
+}
+
+// SetLogLevel sets the minimum level SetLogger's logger receives; the
+// default is slog.LevelWarn, so only failures are logged
+func SetLogLevel(level slog.Level) {
+	logLevel.Set(level)
+}
+
+// WithLogger sets the Client's logger. Its handler alone decides which
+// levels are written; SetLogLevel does not apply.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// log returns the Client's logger
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return packageLogger.Load()
+}
+
+// levelHandler drops records below a minimum level before they reach the
+// wrapped handler, or slog.Default's handler when next is nil
+type levelHandler struct {
+	level slog.Leveler
+	next  slog.Handler
+}
+
+func (h *levelHandler) handler() slog.Handler {
+	if h.next == nil {
+		return slog.Default().Handler()
+	}
+	return h.next
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.handler().Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler().Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{level: h.level, next: h.handler().WithAttrs(attrs)}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{level: h.level, next: h.handler().WithGroup(name)}
+}
@@ -0,0 +1,389 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Marine source hosts
+const (
+	// defaultMarineBaseURL is the Open-Meteo marine API host
+	defaultMarineBaseURL = "https://marine-api.open-meteo.com"
+	// defaultCOOPSBaseURL is the NOAA CO-OPS (Tides and Currents) API host
+	defaultCOOPSBaseURL = "https://api.tidesandcurrents.noaa.gov"
+	// defaultIWLSBaseURL is the Fisheries and Oceans Canada water level API host
+	defaultIWLSBaseURL = "https://api-iwls.dfo-mpo.gc.ca"
+)
+
+// Tide station sources
+const (
+	TideSourceNOAA = "NOAA" // NOAA CO-OPS, US stations
+	TideSourceDFO  = "DFO"  // Fisheries and Oceans Canada, Canadian stations
+)
+
+// tideWindow is how far ahead tide predictions are returned
+const tideWindow = 48 * time.Hour
+
+// tideMaxStationDistanceKm bounds how far the nearest tide station may be from
+// the requested coordinates
+const tideMaxStationDistanceKm = 150
+
+// WithCOOPSBaseURL points NOAA tide requests at another host
+func WithCOOPSBaseURL(base string) Option {
+	return func(c *Client) {
+		c.coopsBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// WithIWLSBaseURL points Canadian tide requests at another host
+func WithIWLSBaseURL(base string) Option {
+	return func(c *Client) {
+		c.iwlsBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// TideStation is a coastal water level station with tide predictions
+type TideStation struct {
+	ID     string // NOAA station ID (e.g. 8518750) or DFO station code (e.g. 00490)
+	Name   string
+	Source string // TideSourceNOAA or TideSourceDFO
+	Coordinates
+}
+
+var (
+	tideStationsMu sync.RWMutex
+
+	// tideStations holds reference tide stations for major North American ports
+	tideStations = map[string]TideStation{
+		"8518750": {ID: "8518750", Name: "The Battery, NY", Source: TideSourceNOAA, Coordinates: Coordinates{Lat: 40.7006, Lon: -74.0142}},
+		"8443970": {ID: "8443970", Name: "Boston, MA", Source: TideSourceNOAA, Coordinates: Coordinates{Lat: 42.3539, Lon: -71.0503}},
+		"8665530": {ID: "8665530", Name: "Charleston, SC", Source: TideSourceNOAA, Coordinates: Coordinates{Lat: 32.7808, Lon: -79.9236}},
+		"8723214": {ID: "8723214", Name: "Virginia Key, FL", Source: TideSourceNOAA, Coordinates: Coordinates{Lat: 25.7314, Lon: -80.1618}},
+		"8724580": {ID: "8724580", Name: "Key West, FL", Source: TideSourceNOAA, Coordinates: Coordinates{Lat: 24.5557, Lon: -81.8079}},
+		"8771450": {ID: "8771450", Name: "Galveston Pier 21, TX", Source: TideSourceNOAA, Coordinates: Coordinates{Lat: 29.3101, Lon: -94.7935}},
+		"9410170": {ID: "9410170", Name: "San Diego, CA", Source: TideSourceNOAA, Coordinates: Coordinates{Lat: 32.7142, Lon: -117.1736}},
+		"9414290": {ID: "9414290", Name: "San Francisco, CA", Source: TideSourceNOAA, Coordinates: Coordinates{Lat: 37.8063, Lon: -122.4659}},
+		"9447130": {ID: "9447130", Name: "Seattle, WA", Source: TideSourceNOAA, Coordinates: Coordinates{Lat: 47.6026, Lon: -122.3393}},
+		"9455920": {ID: "9455920", Name: "Anchorage, AK", Source: TideSourceNOAA, Coordinates: Coordinates{Lat: 61.2378, Lon: -149.8900}},
+		"1612340": {ID: "1612340", Name: "Honolulu, HI", Source: TideSourceNOAA, Coordinates: Coordinates{Lat: 21.3033, Lon: -157.8645}},
+		"00490":   {ID: "00490", Name: "Halifax, NS", Source: TideSourceDFO, Coordinates: Coordinates{Lat: 44.6667, Lon: -63.5833}},
+		"00905":   {ID: "00905", Name: "St. John's, NL", Source: TideSourceDFO, Coordinates: Coordinates{Lat: 47.5667, Lon: -52.7167}},
+		"01700":   {ID: "01700", Name: "Charlottetown, PE", Source: TideSourceDFO, Coordinates: Coordinates{Lat: 46.2317, Lon: -63.1192}},
+		"07120":   {ID: "07120", Name: "Victoria Harbour, BC", Source: TideSourceDFO, Coordinates: Coordinates{Lat: 48.4240, Lon: -123.3710}},
+		"07735":   {ID: "07735", Name: "Vancouver, BC", Source: TideSourceDFO, Coordinates: Coordinates{Lat: 49.2870, Lon: -123.1100}},
+		"09354":   {ID: "09354", Name: "Prince Rupert, BC", Source: TideSourceDFO, Coordinates: Coordinates{Lat: 54.3170, Lon: -130.3240}},
+	}
+)
+
+// RegisterTideStation adds or replaces a tide station used by FetchTides
+func RegisterTideStation(station TideStation) error {
+	if station.ID == "" || (station.Source != TideSourceNOAA && station.Source != TideSourceDFO) {
+		return fmt.Errorf("invalid tide station %q from %q", station.ID, station.Source)
+	}
+	if err := station.Validate(); err != nil {
+		return err
+	}
+
+	tideStationsMu.Lock()
+	defer tideStationsMu.Unlock()
+	tideStations[station.ID] = station
+	return nil
+}
+
+// TideStations returns the registered tide stations sorted by ID
+func TideStations() []TideStation {
+	tideStationsMu.RLock()
+	defer tideStationsMu.RUnlock()
+	stations := make([]TideStation, 0, len(tideStations))
+	for _, s := range tideStations {
+		stations = append(stations, s)
+	}
+	slices.SortFunc(stations, func(a, b TideStation) int { return strings.Compare(a.ID, b.ID) })
+	return stations
+}
+
+// nearestTideStation returns the registered station closest to coords and its distance in km
+func nearestTideStation(coords Coordinates) (TideStation, float64) {
+	tideStationsMu.RLock()
+	defer tideStationsMu.RUnlock()
+
+	var best TideStation
+	bestKm := math.Inf(1)
+	for _, s := range tideStations {
+		if d := distanceKm(coords, s.Coordinates); d < bestKm || (d == bestKm && s.ID < best.ID) {
+			best, bestKm = s, d
+		}
+	}
+	return best, bestKm
+}
+
+// TideEvent is a predicted high or low tide
+type TideEvent struct {
+	Time    time.Time `json:"time"`
+	HeightM float64   `json:"heightM"` // above the station's chart datum
+	Type    string    `json:"type"`    // "high" or "low"
+}
+
+// TideData is the tide prediction for a station
+type TideData struct {
+	StationID   string      `json:"stationId"`
+	StationName string      `json:"stationName"`
+	Source      string      `json:"source"`
+	Latitude    float64     `json:"latitude"`
+	Longitude   float64     `json:"longitude"`
+	Events      []TideEvent `json:"events"` // the next 48 hours, in time order
+}
+
+// MarineData represents current sea conditions. Values are nil where the
+// model has none, e.g. sea surface temperature in ice-covered waters.
+type MarineData struct {
+	WaveHeightM      *float64 `json:"waveHeightM"`
+	WavePeriodS      *float64 `json:"wavePeriodS"`
+	WaveDirectionDeg *float64 `json:"waveDirectionDeg"`
+	WaterTempC       *float64 `json:"waterTempC"`
+	Latitude         float64  `json:"latitude,omitempty"`
+	Longitude        float64  `json:"longitude,omitempty"`
+	Timezone         string   `json:"timezone,omitempty"`
+}
+
+// COOPSPredictionsResponse represents a tide predictions response from the NOAA CO-OPS data API
+type COOPSPredictionsResponse struct {
+	Predictions []struct {
+		T    string `json:"t"` // "2006-01-02 15:04" in GMT
+		V    string `json:"v"` // height in metres
+		Type string `json:"type"`
+	} `json:"predictions"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// IWLSStation represents a station from the DFO IWLS API
+type IWLSStation struct {
+	ID           string `json:"id"`
+	Code         string `json:"code"`
+	OfficialName string `json:"officialName"`
+}
+
+// IWLSDataPoint represents a water level value from the DFO IWLS API
+type IWLSDataPoint struct {
+	EventDate time.Time `json:"eventDate"`
+	Value     float64   `json:"value"`
+}
+
+// OpenMeteoMarineResponse represents a current-conditions response from the Open-Meteo marine API
+type OpenMeteoMarineResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+	Current   struct {
+		Time                  Timestamp `json:"time"`
+		WaveHeight            *float64  `json:"wave_height"`
+		WavePeriod            *float64  `json:"wave_period"`
+		WaveDirection         *float64  `json:"wave_direction"`
+		SeaSurfaceTemperature *float64  `json:"sea_surface_temperature"`
+	} `json:"current"`
+}
+
+// FetchTides fetches tide predictions for a station using the default Client
+func FetchTides(stationID string) (*TideData, error) {
+	return defaultClient.FetchTides(stationID)
+}
+
+// FetchTides fetches the next 48 hours of high and low tides at a registered
+// station (see TideStations)
+func (c *Client) FetchTides(stationID string) (*TideData, error) {
+	tideStationsMu.RLock()
+	station, ok := tideStations[stationID]
+	tideStationsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: no tide station %q", ErrUnknownLocation, stationID)
+	}
+	return c.fetchTides(context.Background(), station)
+}
+
+// FetchTidesByCoords fetches tide predictions near coordinates using the default Client
+func FetchTidesByCoords(lat, lon float64) (*TideData, error) {
+	return defaultClient.FetchTidesByCoords(lat, lon)
+}
+
+// FetchTidesByCoords fetches tide predictions at the registered station
+// nearest the given coordinates. It fails when no station lies within 150 km.
+func (c *Client) FetchTidesByCoords(lat, lon float64) (*TideData, error) {
+	coords := Coordinates{Lat: lat, Lon: lon}
+	if err := coords.Validate(); err != nil {
+		return nil, err
+	}
+	station, km := nearestTideStation(coords)
+	if km > tideMaxStationDistanceKm {
+		return nil, fmt.Errorf("%w: no tide station within %d km of %.4f,%.4f", ErrUnknownLocation, tideMaxStationDistanceKm, lat, lon)
+	}
+	return c.fetchTides(context.Background(), station)
+}
+
+// fetchTides requests predictions from the station's source and keeps the
+// events in the next 48 hours
+func (c *Client) fetchTides(ctx context.Context, station TideStation) (*TideData, error) {
+	// Requests cover whole UTC days so the URL, and so the cache key, is stable for a day
+	now := time.Now().UTC()
+	from := now.Truncate(24 * time.Hour)
+
+	var events []TideEvent
+	var err error
+	switch station.Source {
+	case TideSourceNOAA:
+		events, err = c.fetchCOOPSTides(ctx, station.ID, from)
+	case TideSourceDFO:
+		events, err = c.fetchIWLSTides(ctx, station.ID, from)
+	default:
+		err = fmt.Errorf("unsupported tide source %q", station.Source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events = slices.DeleteFunc(events, func(e TideEvent) bool {
+		return e.Time.Before(now) || e.Time.After(now.Add(tideWindow))
+	})
+	return &TideData{
+		StationID:   station.ID,
+		StationName: station.Name,
+		Source:      station.Source,
+		Latitude:    station.Lat,
+		Longitude:   station.Lon,
+		Events:      events,
+	}, nil
+}
+
+// fetchCOOPSTides requests three days of NOAA high/low predictions from the start of from's day
+func (c *Client) fetchCOOPSTides(ctx context.Context, station string, from time.Time) ([]TideEvent, error) {
+	q := url.Values{}
+	q.Set("product", "predictions")
+	q.Set("station", station)
+	q.Set("begin_date", from.Format("20060102"))
+	q.Set("range", "72")
+	q.Set("datum", "MLLW")
+	q.Set("interval", "hilo")
+	q.Set("units", "metric")
+	q.Set("time_zone", "gmt")
+	q.Set("format", "json")
+	q.Set("application", "reef-na")
+
+	var apiResp COOPSPredictionsResponse
+	if err := c.getJSON(ctx, c.coopsBaseURL+"/api/prod/datagetter", q, &apiResp); err != nil {
+		return nil, err
+	}
+	if apiResp.Error != nil {
+		return nil, fmt.Errorf("%w: NOAA station %s: %s", ErrUnknownLocation, station, apiResp.Error.Message)
+	}
+
+	events := make([]TideEvent, 0, len(apiResp.Predictions))
+	for _, p := range apiResp.Predictions {
+		t, err := time.Parse("2006-01-02 15:04", p.T)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse tide prediction: %w", withKind(ErrDecode, err))
+		}
+		height, err := strconv.ParseFloat(p.V, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse tide prediction: %w", withKind(ErrDecode, err))
+		}
+		typ := "low"
+		if strings.EqualFold(p.Type, "H") {
+			typ = "high"
+		}
+		events = append(events, TideEvent{Time: t, HeightM: height, Type: typ})
+	}
+	return events, nil
+}
+
+// fetchIWLSTides resolves a DFO station code and requests three days of
+// high/low predictions from the start of from's day. The API does not label
+// events, so each is classed by comparison with its neighbours.
+func (c *Client) fetchIWLSTides(ctx context.Context, code string, from time.Time) ([]TideEvent, error) {
+	q := url.Values{}
+	q.Set("code", code)
+	var stations []IWLSStation
+	if err := c.getJSON(ctx, c.iwlsBaseURL+"/api/v1/stations", q, &stations); err != nil {
+		return nil, err
+	}
+	if len(stations) == 0 {
+		return nil, fmt.Errorf("%w: no DFO station %q", ErrUnknownLocation, code)
+	}
+
+	q = url.Values{}
+	q.Set("time-series-code", "wlp-hilo")
+	q.Set("from", from.Format(time.RFC3339))
+	q.Set("to", from.Add(72*time.Hour).Format(time.RFC3339))
+	var points []IWLSDataPoint
+	if err := c.getJSON(ctx, c.iwlsBaseURL+"/api/v1/stations/"+url.PathEscape(stations[0].ID)+"/data", q, &points); err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, withKind(ErrDecode, errors.New("DFO tide response has no predictions"))
+	}
+
+	slices.SortFunc(points, func(a, b IWLSDataPoint) int { return a.EventDate.Compare(b.EventDate) })
+	events := make([]TideEvent, len(points))
+	for i, p := range points {
+		neighbour := i + 1
+		if neighbour == len(points) {
+			neighbour = i - 1
+		}
+		typ := "low"
+		if neighbour >= 0 && p.Value > points[neighbour].Value {
+			typ = "high"
+		}
+		events[i] = TideEvent{Time: p.EventDate.UTC(), HeightM: p.Value, Type: typ}
+	}
+	return events, nil
+}
+
+// FetchMarineConditions fetches sea conditions using the default Client
+func FetchMarineConditions(lat, lon float64) (*MarineData, error) {
+	return defaultClient.FetchMarineConditions(lat, lon)
+}
+
+// FetchMarineConditions fetches the current wave height, period and direction
+// and sea surface temperature at the given coordinates. Inland coordinates
+// fail with ErrUnknownLocation.
+func (c *Client) FetchMarineConditions(lat, lon float64) (*MarineData, error) {
+	coords := Coordinates{Lat: lat, Lon: lon}
+	if err := coords.Validate(); err != nil {
+		return nil, err
+	}
+	return c.fetchMarine(context.Background(), coords)
+}
+
+// fetchMarine requests current sea conditions at coords
+func (c *Client) fetchMarine(ctx context.Context, coords Coordinates) (*MarineData, error) {
+	q := coords.query()
+	q.Set("current", "wave_height,wave_period,wave_direction,sea_surface_temperature")
+	q.Set("timezone", "auto")
+
+	var apiResp OpenMeteoMarineResponse
+	if err := c.getOpenMeteo(ctx, c.marineBaseURL+"/v1/marine", q, &apiResp); err != nil {
+		return nil, err
+	}
+	cur := apiResp.Current
+	if cur.WaveHeight == nil && cur.SeaSurfaceTemperature == nil {
+		return nil, fmt.Errorf("%w: no marine data at %.4f,%.4f", ErrUnknownLocation, coords.Lat, coords.Lon)
+	}
+
+	return &MarineData{
+		WaveHeightM:      cur.WaveHeight,
+		WavePeriodS:      cur.WavePeriod,
+		WaveDirectionDeg: cur.WaveDirection,
+		WaterTempC:       cur.SeaSurfaceTemperature,
+		Latitude:         apiResp.Latitude,
+		Longitude:        apiResp.Longitude,
+		Timezone:         apiResp.Timezone,
+	}, nil
+}
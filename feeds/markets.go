@@ -0,0 +1,322 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // exchange hours must not depend on the host's zone database
+)
+
+// defaultMarketsBaseURL is the Yahoo Finance chart API host
+const defaultMarketsBaseURL = "https://query1.finance.yahoo.com"
+
+// MarketIndex is the current value of a stock market index
+type MarketIndex struct {
+	Symbol    string    `json:"symbol"`
+	Name      string    `json:"name"`
+	Country   string    `json:"country"`
+	Exchange  string    `json:"exchange"`
+	Currency  string    `json:"currency,omitempty"`
+	Value     float64   `json:"value"`
+	Change    float64   `json:"change"`    // since the previous close
+	ChangePct float64   `json:"changePct"` // percent change since the previous close
+	UpdatedAt time.Time `json:"updatedAt,omitzero"`
+	MarketSession
+}
+
+// MarketSession reports whether an exchange is trading
+type MarketSession struct {
+	Open    bool   `json:"open"`
+	Holiday string `json:"holiday,omitempty"` // set when a weekday is an exchange holiday
+}
+
+// MarketQuote is a provider's latest price for a symbol
+type MarketQuote struct {
+	Price         float64
+	PreviousClose float64
+	Currency      string
+	Time          time.Time
+}
+
+// MarketProvider fetches index quotes
+type MarketProvider interface {
+	Quote(ctx context.Context, symbol string) (*MarketQuote, error)
+}
+
+// marketIndex is a tracked index
+type marketIndex struct {
+	symbol, name, country, exchange string
+}
+
+// naIndices are the key North American indices, keyed by Yahoo Finance symbol
+var naIndices = []marketIndex{
+	{"^GSPC", "S&P 500", "US", "NYSE"},
+	{"^DJI", "Dow Jones Industrial Average", "US", "NYSE"},
+	{"^IXIC", "Nasdaq Composite", "US", "NASDAQ"},
+	{"^GSPTSE", "S&P/TSX Composite", "CA", "TSX"},
+	{"^MXX", "S&P/BMV IPC", "MX", "BMV"},
+}
+
+// namedDay is a dated holiday
+type namedDay struct {
+	date time.Time
+	name string
+}
+
+// exchangeCalendar describes an exchange's regular session and holidays
+type exchangeCalendar struct {
+	zone        string
+	open, close time.Duration // since local midnight
+	holidays    func(year int) []namedDay
+}
+
+// exchanges are the calendars of the exchanges in naIndices. Early closes
+// are not modelled. The BMV keeps its session aligned with New York, so it
+// uses New York time.
+var exchanges = map[string]exchangeCalendar{
+	"NYSE":   {zone: "America/New_York", open: 9*time.Hour + 30*time.Minute, close: 16 * time.Hour, holidays: nyseHolidays},
+	"NASDAQ": {zone: "America/New_York", open: 9*time.Hour + 30*time.Minute, close: 16 * time.Hour, holidays: nyseHolidays},
+	"TSX":    {zone: "America/Toronto", open: 9*time.Hour + 30*time.Minute, close: 16 * time.Hour, holidays: tsxHolidays},
+	"BMV":    {zone: "America/New_York", open: 9*time.Hour + 30*time.Minute, close: 16 * time.Hour, holidays: bmvHolidays},
+}
+
+// nyseHolidays are the NYSE and Nasdaq full-day closures
+func nyseHolidays(year int) []namedDay {
+	days := []namedDay{
+		{date(year, time.January, 1), "New Year's Day"},
+		{nthWeekday(year, time.January, time.Monday, 3), "Martin Luther King Jr. Day"},
+		{nthWeekday(year, time.February, time.Monday, 3), "Washington's Birthday"},
+		{easterSunday(year).AddDate(0, 0, -2), "Good Friday"},
+		{nthWeekday(year, time.May, time.Monday, -1), "Memorial Day"},
+		{observedNearest(date(year, time.July, 4)), "Independence Day"},
+		{nthWeekday(year, time.September, time.Monday, 1), "Labor Day"},
+		{nthWeekday(year, time.November, time.Thursday, 4), "Thanksgiving Day"},
+		{observedNearest(date(year, time.December, 25)), "Christmas Day"},
+	}
+	// A Saturday New Year's Day is not observed on the Friday, which closes the prior year
+	if days[0].date.Weekday() == time.Sunday {
+		days[0].date = days[0].date.AddDate(0, 0, 1)
+	}
+	if year >= 2022 {
+		days = append(days, namedDay{observedNearest(date(year, time.June, 19)), "Juneteenth"})
+	}
+	return days
+}
+
+// tsxHolidays are the Toronto Stock Exchange closures
+func tsxHolidays(year int) []namedDay {
+	days := []namedDay{
+		{date(year, time.January, 1), "New Year's Day"},
+		{nthWeekday(year, time.February, time.Monday, 3), "Family Day"},
+		{easterSunday(year).AddDate(0, 0, -2), "Good Friday"},
+		{weekdayOnOrBefore(date(year, time.May, 24), time.Monday), "Victoria Day"},
+		{date(year, time.July, 1), "Canada Day"},
+		{nthWeekday(year, time.August, time.Monday, 1), "Civic Holiday"},
+		{nthWeekday(year, time.September, time.Monday, 1), "Labour Day"},
+		{nthWeekday(year, time.October, time.Monday, 2), "Thanksgiving"},
+		{date(year, time.December, 25), "Christmas Day"},
+		{date(year, time.December, 26), "Boxing Day"},
+	}
+	dates := make([]time.Time, len(days))
+	for i, d := range days {
+		dates[i] = d.date
+	}
+	for i, d := range observedForward(dates) {
+		days[i].date = d
+	}
+	return days
+}
+
+// bmvHolidays are the Mexican Stock Exchange closures. Holidays falling on a
+// weekend are not moved.
+func bmvHolidays(year int) []namedDay {
+	easter := easterSunday(year)
+	return []namedDay{
+		{date(year, time.January, 1), "Año Nuevo"},
+		{nthWeekday(year, time.February, time.Monday, 1), "Día de la Constitución"},
+		{nthWeekday(year, time.March, time.Monday, 3), "Natalicio de Benito Juárez"},
+		{easter.AddDate(0, 0, -3), "Jueves Santo"},
+		{easter.AddDate(0, 0, -2), "Viernes Santo"},
+		{date(year, time.May, 1), "Día del Trabajo"},
+		{date(year, time.September, 16), "Día de la Independencia"},
+		{date(year, time.November, 2), "Día de Muertos"},
+		{nthWeekday(year, time.November, time.Monday, 3), "Día de la Revolución"},
+		{date(year, time.December, 12), "Día de la Virgen de Guadalupe"},
+		{date(year, time.December, 25), "Navidad"},
+	}
+}
+
+// MarketStatus reports whether an exchange (NYSE, NASDAQ, TSX or BMV) is in
+// its regular trading session at t
+func MarketStatus(exchange string, t time.Time) (MarketSession, error) {
+	cal, ok := exchanges[strings.ToUpper(exchange)]
+	if !ok {
+		return MarketSession{}, fmt.Errorf("%w: exchange %q", ErrUnknownMarket, exchange)
+	}
+	loc, err := time.LoadLocation(cal.zone)
+	if err != nil {
+		return MarketSession{}, fmt.Errorf("failed to load exchange time zone: %w", err)
+	}
+
+	local := t.In(loc)
+	day := civilDate(local)
+	if isWeekend(day) {
+		return MarketSession{}, nil
+	}
+	for _, h := range cal.holidays(day.Year()) {
+		if h.date.Equal(day) {
+			return MarketSession{Holiday: h.name}, nil
+		}
+	}
+	sinceMidnight := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	return MarketSession{Open: sinceMidnight >= cal.open && sinceMidnight < cal.close}, nil
+}
+
+// WithMarketProvider sets the provider used by FetchMarkets. The default
+// reads Yahoo Finance's public chart API.
+func WithMarketProvider(p MarketProvider) Option {
+	return func(c *Client) {
+		c.marketProvider = p
+	}
+}
+
+// WithMarketsBaseURL points the default market provider at another host
+func WithMarketsBaseURL(base string) Option {
+	return func(c *Client) {
+		c.marketsBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// FetchMarkets fetches the key North American indices using the default Client
+func FetchMarkets() ([]MarketIndex, error) {
+	return defaultClient.FetchMarkets()
+}
+
+// FetchMarkets fetches the current value and day change of the S&P 500, Dow,
+// Nasdaq Composite, S&P/TSX Composite and S&P/BMV IPC, with whether each
+// exchange is open. Indices that fail are skipped as long as one succeeds.
+func (c *Client) FetchMarkets() ([]MarketIndex, error) {
+	return c.fetchMarkets(context.Background())
+}
+
+// fetchMarkets quotes every index concurrently
+func (c *Client) fetchMarkets(ctx context.Context) ([]MarketIndex, error) {
+	now := time.Now()
+	results := make([]*MarketIndex, len(naIndices))
+	errs := make([]error, len(naIndices))
+	var wg sync.WaitGroup
+	for i, idx := range naIndices {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = c.fetchMarketIndex(ctx, idx, now)
+		}()
+	}
+	wg.Wait()
+
+	var indices []MarketIndex
+	for _, r := range results {
+		if r != nil {
+			indices = append(indices, *r)
+		}
+	}
+	if len(indices) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return indices, nil
+}
+
+// fetchMarketIndex quotes one index and adds its exchange status at now
+func (c *Client) fetchMarketIndex(ctx context.Context, idx marketIndex, now time.Time) (*MarketIndex, error) {
+	quote, err := c.marketProvider.Quote(ctx, idx.symbol)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", idx.name, err)
+	}
+	session, err := MarketStatus(idx.exchange, now)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MarketIndex{
+		Symbol:        idx.symbol,
+		Name:          idx.name,
+		Country:       idx.country,
+		Exchange:      idx.exchange,
+		Currency:      quote.Currency,
+		Value:         quote.Price,
+		UpdatedAt:     quote.Time,
+		MarketSession: session,
+	}
+	if quote.PreviousClose != 0 {
+		m.Change = roundTo(quote.Price-quote.PreviousClose, 2)
+		m.ChangePct = roundTo((quote.Price-quote.PreviousClose)/quote.PreviousClose*100, 2)
+	}
+	return m, nil
+}
+
+// YahooChartResponse represents a /v8/finance/chart/{symbol} response from Yahoo Finance
+type YahooChartResponse struct {
+	Chart struct {
+		Result []struct {
+			Meta struct {
+				Currency           string  `json:"currency"`
+				RegularMarketPrice float64 `json:"regularMarketPrice"`
+				RegularMarketTime  int64   `json:"regularMarketTime"`
+				PreviousClose      float64 `json:"previousClose"`
+				ChartPreviousClose float64 `json:"chartPreviousClose"`
+			} `json:"meta"`
+		} `json:"result"`
+		Error *struct {
+			Code        string `json:"code"`
+			Description string `json:"description"`
+		} `json:"error"`
+	} `json:"chart"`
+}
+
+// YahooMarketProvider is the default MarketProvider, backed by Yahoo
+// Finance's keyless chart API
+type YahooMarketProvider struct {
+	client *Client
+}
+
+// NewYahooMarketProvider creates a Yahoo Finance provider whose requests use the given Client options
+func NewYahooMarketProvider(options ...Option) *YahooMarketProvider {
+	return &YahooMarketProvider{client: NewClient(options...)}
+}
+
+// Quote returns the latest regular-session price for symbol
+func (p *YahooMarketProvider) Quote(ctx context.Context, symbol string) (*MarketQuote, error) {
+	q := url.Values{}
+	q.Set("range", "1d")
+	q.Set("interval", "1d")
+
+	var resp YahooChartResponse
+	endpoint := p.client.marketsBaseURL + "/v8/finance/chart/" + url.PathEscape(symbol)
+	if err := p.client.getJSON(ctx, endpoint, q, &resp); err != nil {
+		return nil, err
+	}
+	if e := resp.Chart.Error; e != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrUnknownMarket, e.Code, e.Description)
+	}
+	if len(resp.Chart.Result) == 0 {
+		return nil, withKind(ErrDecode, errors.New("chart response has no result"))
+	}
+
+	meta := resp.Chart.Result[0].Meta
+	quote := &MarketQuote{
+		Price:         meta.RegularMarketPrice,
+		PreviousClose: meta.PreviousClose,
+		Currency:      meta.Currency,
+	}
+	if quote.PreviousClose == 0 {
+		quote.PreviousClose = meta.ChartPreviousClose
+	}
+	if meta.RegularMarketTime > 0 {
+		quote.Time = time.Unix(meta.RegularMarketTime, 0).UTC()
+	}
+	return quote, nil
+}
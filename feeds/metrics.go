@@ -0,0 +1,76 @@
+package feeds
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// labelEscaper escapes label values per the Prometheus text exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteMetrics writes the weather reading as Prometheus text-format samples,
+// e.g. weather_temperature_celsius{country="US"} 25.5
+//
+// Only sample lines are written (no # HELP / # TYPE headers) so the output of
+// several locations can be concatenated into a single /metrics response.
+// Series that are not set are left out rather than reported as 0: a zero
+// WeatherData, such as one not yet fetched, writes nothing, and the wind
+// direction is left out when there is no wind.
+func (w WeatherData) WriteMetrics(out io.Writer, labels map[string]string) error {
+	if w == (WeatherData{}) {
+		return nil
+	}
+	lbl := formatLabels(labels)
+	calm := w.WindSpeedKmh == 0 && w.WindGustsKmh == 0
+
+	samples := []struct {
+		name  string
+		value float64
+		unset bool
+	}{
+		{"weather_temperature_celsius", w.TemperatureC, false},
+		{"weather_feels_like_celsius", w.FeelsLikeC, false},
+		{"weather_precipitation_mm", w.PrecipitationMM, false},
+		{"weather_relative_humidity_percent", w.HumidityPct, false},
+		{"weather_cloud_cover_percent", w.CloudCoverPct, false},
+		{"weather_wind_speed_kmh", w.WindSpeedKmh, false},
+		{"weather_wind_direction_degrees", w.WindDirectionDeg, calm},
+		{"weather_wind_gusts_kmh", w.WindGustsKmh, false},
+	}
+
+	for _, s := range samples {
+		if s.unset {
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "%s%s %s\n", s.name, lbl, strconv.FormatFloat(s.value, 'g', -1, 64)); err != nil {
+			return fmt.Errorf("failed to write metric %s: %w", s.name, err)
+		}
+	}
+	return nil
+}
+
+// formatLabels renders labels as {k="v",...} with keys sorted for stable output
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, k, labelEscaper.Replace(labels[k]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
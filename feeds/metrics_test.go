@@ -0,0 +1,174 @@
+package feeds
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// sample is a line of the Prometheus text exposition format
+type sample struct {
+	name   string
+	labels map[string]string
+	value  float64
+}
+
+var (
+	sampleLine = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(?:\{(.*)\})? (\S+)$`)
+	labelName  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// parseSamples parses text in the Prometheus exposition format, failing on
+// anything that is not a comment, a blank line or a well-formed sample
+func parseSamples(text string) ([]sample, error) {
+	var samples []sample
+	sc := bufio.NewScanner(strings.NewReader(text))
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m := sampleLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("line %d: not a sample: %q", n, line)
+		}
+		labels, err := parseLabels(m[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		value, err := strconv.ParseFloat(m[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		samples = append(samples, sample{m[1], labels, value})
+	}
+	return samples, sc.Err()
+}
+
+// parseLabels parses the label pairs between a sample's braces
+func parseLabels(s string) (map[string]string, error) {
+	labels := map[string]string{}
+	for s != "" {
+		name, rest, ok := strings.Cut(s, `="`)
+		if !ok || !labelName.MatchString(name) {
+			return nil, fmt.Errorf("invalid label in %q", s)
+		}
+		var value strings.Builder
+		i := 0
+		for ; i < len(rest) && rest[i] != '"'; i++ {
+			if rest[i] != '\\' {
+				value.WriteByte(rest[i])
+				continue
+			}
+			if i++; i == len(rest) {
+				break
+			}
+			switch rest[i] {
+			case '\\', '"':
+				value.WriteByte(rest[i])
+			case 'n':
+				value.WriteByte('\n')
+			default:
+				return nil, fmt.Errorf("invalid escape \\%c in label %s", rest[i], name)
+			}
+		}
+		if i >= len(rest) {
+			return nil, fmt.Errorf("unterminated value of label %s", name)
+		}
+		if _, dup := labels[name]; dup {
+			return nil, fmt.Errorf("duplicate label %s", name)
+		}
+		labels[name] = value.String()
+		s = strings.TrimPrefix(rest[i+1:], ",")
+	}
+	return labels, nil
+}
+
+func TestWriteMetrics(t *testing.T) {
+	w := WeatherData{
+		TemperatureC: -2.5, FeelsLikeC: -7, PrecipitationMM: 0, HumidityPct: 81, CloudCoverPct: 100,
+		WindSpeedKmh: 18.4, WindDirectionDeg: 270, WindGustsKmh: 35, Provider: "open-meteo",
+	}
+	labels := map[string]string{"country": "CA", "city": `Saint-"Jean"` + "\n" + `C:\`}
+	var b strings.Builder
+	if err := w.WriteMetrics(&b, labels); err != nil {
+		t.Fatal(err)
+	}
+	samples, err := parseSamples(b.String())
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, b.String())
+	}
+	want := map[string]float64{
+		"weather_temperature_celsius":       -2.5,
+		"weather_feels_like_celsius":        -7,
+		"weather_precipitation_mm":          0,
+		"weather_relative_humidity_percent": 81,
+		"weather_cloud_cover_percent":       100,
+		"weather_wind_speed_kmh":            18.4,
+		"weather_wind_direction_degrees":    270,
+		"weather_wind_gusts_kmh":            35,
+	}
+	got := map[string]float64{}
+	for _, s := range samples {
+		if _, dup := got[s.name]; dup {
+			t.Errorf("%s written twice", s.name)
+		}
+		got[s.name] = s.value
+		if !maps.Equal(s.labels, labels) {
+			t.Errorf("%s labels = %q, want %q", s.name, s.labels, labels)
+		}
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("samples = %v, want %v", got, want)
+	}
+}
+
+func TestWriteMetricsUnset(t *testing.T) {
+	var b strings.Builder
+	if err := (WeatherData{}).WriteMetrics(&b, map[string]string{"country": "US"}); err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() > 0 {
+		t.Errorf("zero WeatherData wrote\n%s", b.String())
+	}
+
+	calm := WeatherData{TemperatureC: 12, WindDirectionDeg: 0, Provider: "nws"}
+	if err := calm.WriteMetrics(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	samples, err := parseSamples(b.String())
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, b.String())
+	}
+	for _, s := range samples {
+		if s.name == "weather_wind_direction_degrees" {
+			t.Error("wind direction written with no wind")
+		}
+		if len(s.labels) > 0 {
+			t.Errorf("%s has labels %v, want none", s.name, s.labels)
+		}
+	}
+	if len(samples) != 7 {
+		t.Errorf("wrote %d samples, want 7", len(samples))
+	}
+}
+
+func TestWriteMetricsSpecialValues(t *testing.T) {
+	var b strings.Builder
+	w := WeatherData{TemperatureC: math.NaN(), FeelsLikeC: math.Inf(-1), Provider: "eccc"}
+	if err := w.WriteMetrics(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	samples, err := parseSamples(b.String())
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, b.String())
+	}
+	if !math.IsNaN(samples[0].value) || !math.IsInf(samples[1].value, -1) {
+		t.Errorf("got %v and %v, want NaN and -Inf", samples[0].value, samples[1].value)
+	}
+}
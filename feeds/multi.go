@@ -0,0 +1,51 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MultiLocationResponse represents the Open-Meteo response to a request with
+// comma-separated latitude/longitude lists. Each element carries its own
+// coordinates, timezone and current block. A single-location request returns
+// a plain object, which is decoded as a one-element response.
+type MultiLocationResponse []OpenMeteoResponse
+
+// UnmarshalJSON accepts both the array form and a single-location object
+func (m *MultiLocationResponse) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var single OpenMeteoResponse
+		if err := json.Unmarshal(trimmed, &single); err != nil {
+			return err
+		}
+		*m = MultiLocationResponse{single}
+		return nil
+	}
+	var many []OpenMeteoResponse
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*m = many
+	return nil
+}
+
+// Validate checks every location in the response
+func (m MultiLocationResponse) Validate() error {
+	for i := range m {
+		if err := m[i].validate(); err != nil {
+			return fmt.Errorf("location %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ToWeatherData converts every location in the response, in request order,
+// keeping each location's coordinates and timezone
+func (m MultiLocationResponse) ToWeatherData() []*WeatherData {
+	results := make([]*WeatherData, 0, len(m))
+	for i := range m {
+		results = append(results, m[i].toWeatherData())
+	}
+	return results
+}
@@ -0,0 +1,71 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// multiBody is an Open-Meteo response for New York and Ottawa, in that order
+const multiBody = `[
+{"latitude":40.71,"longitude":-74.01,"timezone":"America/New_York","utc_offset_seconds":-14400,
+ "current":{"time":"2026-06-21T12:00","temperature_2m":27.5,"weather_code":1,"wind_speed_10m":12.2}},
+{"latitude":45.42,"longitude":-75.7,"timezone":"America/Toronto","utc_offset_seconds":-14400,
+ "current":{"time":"2026-06-21T12:00","temperature_2m":19.25,"weather_code":61,"wind_speed_10m":20.5}}]`
+
+func TestOpenMeteoMulti(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("latitude") != "40.7128,45.4215" || q.Get("longitude") != "-74.0060,-75.6972" {
+			http.Error(w, "unexpected coordinates "+r.URL.RawQuery, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, multiBody)
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	got, err := c.openMeteoMulti(context.Background(), []Coordinates{
+		{Lat: 40.7128, Lon: -74.0060},
+		{Lat: 45.4215, Lon: -75.6972},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		lat, lon, temp, wind float64
+		code                 int
+		timezone, summary    string
+	}{
+		{40.71, -74.01, 27.5, 12.2, 1, "America/New_York", "Mainly clear"},
+		{45.42, -75.7, 19.25, 20.5, 61, "America/Toronto", "Slight rain"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d locations, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Latitude != w.lat || g.Longitude != w.lon || g.Timezone != w.timezone {
+			t.Errorf("location %d = %v,%v %s, want %v,%v %s", i, g.Latitude, g.Longitude, g.Timezone, w.lat, w.lon, w.timezone)
+		}
+		if g.TemperatureC != w.temp || g.WindSpeedKmh != w.wind || g.WeatherCode != w.code || g.Summary != w.summary {
+			t.Errorf("location %d = %v°C, %v km/h, code %d %q, want %v°C, %v km/h, code %d %q",
+				i, g.TemperatureC, g.WindSpeedKmh, g.WeatherCode, g.Summary, w.temp, w.wind, w.code, w.summary)
+		}
+		if g.ObservedAt.Location().String() != w.timezone || g.ObservedAt.Hour() != 12 {
+			t.Errorf("location %d observed at %v, want 12:00 %s", i, g.ObservedAt, w.timezone)
+		}
+	}
+}
+
+func TestMultiLocationResponseSingleObject(t *testing.T) {
+	var m MultiLocationResponse
+	if err := m.UnmarshalJSON([]byte(currentBody)); err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 1 || m[0].Timezone != "America/New_York" {
+		t.Errorf("decoded %+v, want one New York location", m)
+	}
+}
@@ -0,0 +1,174 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultHeadlineLimit is how many headlines FetchHeadlines returns when limit is not positive
+const defaultHeadlineLimit = 10
+
+// Headline is a news headline
+type Headline struct {
+	Title     string    `json:"title"`
+	Source    string    `json:"source"`
+	URL       string    `json:"url"`
+	Published time.Time `json:"published,omitzero"`
+}
+
+// NewsProvider fetches top headlines for a country, newest first
+type NewsProvider interface {
+	Headlines(ctx context.Context, country string, limit int) ([]Headline, error)
+}
+
+// NewsSource is an RSS or Atom feed of headlines
+type NewsSource struct {
+	Name string
+	URL  string
+}
+
+var (
+	newsSourcesMu sync.RWMutex
+
+	// naNewsSources are the built-in outlets per country
+	naNewsSources = map[string][]NewsSource{
+		"US": {
+			{Name: "NPR", URL: "https://feeds.npr.org/1001/rss.xml"},
+			{Name: "The New York Times", URL: "https://rss.nytimes.com/services/xml/rss/nyt/HomePage.xml"},
+		},
+		"CA": {
+			{Name: "CBC News", URL: "https://www.cbc.ca/webfeed/rss/rss-topstories"},
+			{Name: "Global News", URL: "https://globalnews.ca/feed/"},
+		},
+		"MX": {
+			{Name: "La Jornada", URL: "https://www.jornada.com.mx/rss/edicion.xml"},
+		},
+	}
+)
+
+// RegisterNewsSource adds an RSS outlet to a country's headlines
+func RegisterNewsSource(country string, source NewsSource) {
+	newsSourcesMu.Lock()
+	defer newsSourcesMu.Unlock()
+	country = strings.ToUpper(country)
+	naNewsSources[country] = append(naNewsSources[country], source)
+}
+
+// newsSources returns a copy of a country's outlets
+func newsSources(country string) []NewsSource {
+	newsSourcesMu.RLock()
+	defer newsSourcesMu.RUnlock()
+	return slices.Clone(naNewsSources[strings.ToUpper(country)])
+}
+
+// WithNewsProvider sets the provider used by FetchHeadlines. The default
+// reads the RSS feeds of major outlets registered per country.
+func WithNewsProvider(p NewsProvider) Option {
+	return func(c *Client) {
+		c.newsProvider = p
+	}
+}
+
+// FetchHeadlines fetches top headlines using the default Client
+func FetchHeadlines(country string, limit int) ([]Headline, error) {
+	return defaultClient.FetchHeadlines(country, limit)
+}
+
+// FetchHeadlines fetches up to limit (default 10) of the newest headlines for a country
+func (c *Client) FetchHeadlines(country string, limit int) ([]Headline, error) {
+	if limit <= 0 {
+		limit = defaultHeadlineLimit
+	}
+	return c.newsProvider.Headlines(context.Background(), country, limit)
+}
+
+// newsFeedTTL is how often the news feed is worth refetching
+const newsFeedTTL = 30 * time.Minute
+
+// NewsFeed returns a Feed named "news" of up to limit (default 10) headlines
+// for the location's country, fetched through c (the default Client if nil)
+func NewsFeed(c *Client, limit int) Feed {
+	if c == nil {
+		c = defaultClient
+	}
+	if limit <= 0 {
+		limit = defaultHeadlineLimit
+	}
+	return NewFeed("news", newsFeedTTL, func(ctx context.Context, loc Location) (any, error) {
+		return c.newsProvider.Headlines(ctx, loc.Country, limit)
+	})
+}
+
+// RSSNewsProvider is the default NewsProvider. It merges the RSS feeds of the
+// outlets registered for a country (see RegisterNewsSource), dropping
+// duplicate links. Outlets that fail are skipped as long as one succeeds.
+type RSSNewsProvider struct {
+	client *Client
+}
+
+// NewRSSNewsProvider creates an RSS news provider whose requests use the given Client options
+func NewRSSNewsProvider(options ...Option) *RSSNewsProvider {
+	return &RSSNewsProvider{client: NewClient(options...)}
+}
+
+// Headlines fetches every outlet for country concurrently and returns the newest headlines
+func (p *RSSNewsProvider) Headlines(ctx context.Context, country string, limit int) ([]Headline, error) {
+	sources := newsSources(country)
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("%w: no news sources for %q", ErrUnknownLocation, country)
+	}
+
+	results := make([][]Headline, len(sources))
+	errs := make([]error, len(sources))
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = p.fetchSource(ctx, src)
+		}()
+	}
+	wg.Wait()
+
+	var all []Headline
+	seen := make(map[string]bool)
+	for _, hs := range results {
+		for _, h := range hs {
+			if !seen[h.URL] {
+				seen[h.URL] = true
+				all = append(all, h)
+			}
+		}
+	}
+	if len(all) == 0 {
+		if err := errors.Join(errs...); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	slices.SortStableFunc(all, func(a, b Headline) int { return b.Published.Compare(a.Published) })
+	return all[:min(limit, len(all))], nil
+}
+
+// fetchSource reads one outlet's feed
+func (p *RSSNewsProvider) fetchSource(ctx context.Context, src NewsSource) ([]Headline, error) {
+	items, err := p.client.fetchFeedItems(ctx, src.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src.Name, err)
+	}
+
+	headlines := make([]Headline, 0, len(items))
+	for _, item := range items {
+		if item.Title == "" || item.Link == "" {
+			continue
+		}
+		headlines = append(headlines, Headline{Title: item.Title, Source: src.Name, URL: item.Link, Published: item.Published})
+	}
+	return headlines, nil
+}
@@ -0,0 +1,34 @@
+package feeds
+
+import "math"
+
+// Normalize returns a copy with negative-zero temperatures collapsed to 0
+func (w WeatherData) Normalize() WeatherData {
+	return w.NormalizeWithin(0)
+}
+
+// NormalizeWithin returns a copy with temperatures within eps of a whole
+// number snapped to it (so -0.04 becomes 0 with eps 0.05) and negative zero
+// collapsed to 0. An eps of 0 only collapses negative zero.
+func (w WeatherData) NormalizeWithin(eps float64) WeatherData {
+	w.TemperatureC = normalizeValue(w.TemperatureC, eps)
+	w.FeelsLikeC = normalizeValue(w.FeelsLikeC, eps)
+	return w
+}
+
+// normalizeValue snaps v to the nearest integer if within eps and removes the sign from zero
+func normalizeValue(v, eps float64) float64 {
+	if r := math.Round(v); math.Abs(v-r) <= eps {
+		v = r
+	}
+	if v == 0 {
+		return 0 // also turns -0 into +0
+	}
+	return v
+}
+
+// roundTo rounds v to the given number of decimals
+func roundTo(v float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(v*scale) / scale
+}
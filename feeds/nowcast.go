@@ -0,0 +1,72 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// nowcastSteps is the number of 15-minute steps requested (two hours)
+const nowcastSteps = 8
+
+// NowcastPoint represents precipitation for a single 15-minute interval
+type NowcastPoint struct {
+	Time            time.Time `json:"time"`
+	PrecipitationMM float64   `json:"precipitationMM"`
+}
+
+// OpenMeteoNowcastResponse represents a minutely_15 response from Open-Meteo
+type OpenMeteoNowcastResponse struct {
+	Timezone         string `json:"timezone"`
+	UTCOffsetSeconds int    `json:"utc_offset_seconds"`
+	Minutely15       struct {
+		Time          []Timestamp `json:"time"`
+		Precipitation []float64   `json:"precipitation"`
+	} `json:"minutely_15"`
+}
+
+// FetchNowcast fetches the precipitation nowcast using the default Client
+func FetchNowcast(country string) ([]NowcastPoint, error) {
+	return defaultClient.FetchNowcast(country)
+}
+
+// FetchNowcast fetches 15-minute resolution precipitation for the next two hours.
+// Open-Meteo does not publish a probability at this resolution, only amounts;
+// point times are in the location's own time zone.
+func (c *Client) FetchNowcast(country string) ([]NowcastPoint, error) {
+	ctx := context.Background()
+	coords, _, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+
+	q := coords.query()
+	q.Set("minutely_15", "precipitation")
+	q.Set("forecast_minutely_15", fmt.Sprint(nowcastSteps))
+	q.Set("timezone", "auto")
+
+	var apiResp OpenMeteoNowcastResponse
+	if err := c.getOpenMeteo(ctx, c.forecastBaseURL+"/v1/forecast", q, &apiResp); err != nil {
+		return nil, err
+	}
+
+	m := apiResp.Minutely15
+	if len(m.Precipitation) != len(m.Time) {
+		return nil, withKind(ErrDecode, errors.New("nowcast response has mismatched array lengths"))
+	}
+
+	loc := apiLocation(apiResp.Timezone, apiResp.UTCOffsetSeconds)
+	points := make([]NowcastPoint, 0, len(m.Time))
+	for i, ts := range m.Time {
+		t, err := ts.In(loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse nowcast time: %w", err)
+		}
+		points = append(points, NowcastPoint{
+			Time:            t,
+			PrecipitationMM: m.Precipitation[i],
+		})
+	}
+	return points, nil
+}
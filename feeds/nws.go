@@ -0,0 +1,226 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultNWSBaseURL is the US National Weather Service API host
+const defaultNWSBaseURL = "https://api.weather.gov"
+
+// WithNWSBaseURL points National Weather Service requests at another host
+func WithNWSBaseURL(base string) Option {
+	return func(c *Client) {
+		c.nwsBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// NWSPointResponse represents the /points/{lat},{lon} response from api.weather.gov
+type NWSPointResponse struct {
+	Properties struct {
+		GridID         string `json:"gridId"`
+		GridX          int    `json:"gridX"`
+		GridY          int    `json:"gridY"`
+		Forecast       string `json:"forecast"`
+		ForecastHourly string `json:"forecastHourly"`
+		TimeZone       string `json:"timeZone"`
+	} `json:"properties"`
+}
+
+// NWSForecastResponse represents a gridpoint forecast response from api.weather.gov
+type NWSForecastResponse struct {
+	Properties struct {
+		Periods []struct {
+			StartTime                  string  `json:"startTime"`
+			Temperature                float64 `json:"temperature"`
+			TemperatureUnit            string  `json:"temperatureUnit"`
+			ShortForecast              string  `json:"shortForecast"`
+			ProbabilityOfPrecipitation struct {
+				Value *float64 `json:"value"`
+			} `json:"probabilityOfPrecipitation"`
+			RelativeHumidity struct {
+				Value *float64 `json:"value"`
+			} `json:"relativeHumidity"`
+			WindSpeed     string `json:"windSpeed"`     // e.g. "15 km/h" or "10 to 15 mph"
+			WindDirection string `json:"windDirection"` // compass point, e.g. "NW"
+		} `json:"periods"`
+	} `json:"properties"`
+}
+
+// NWSProvider is a WeatherProvider backed by the US National Weather Service.
+// Coordinates are resolved to an NWS forecast gridpoint on first use and the
+// mapping is remembered. Only US locations are covered; elsewhere NWS returns
+// 404 and Fetch fails.
+type NWSProvider struct {
+	client *Client
+
+	mu     sync.Mutex
+	points map[Coordinates]nwsPoint
+}
+
+// nwsPoint is a resolved NWS gridpoint
+type nwsPoint struct {
+	forecastHourly string
+	timeZone       string
+}
+
+// NewNWSProvider creates an NWS provider whose requests use the given Client options
+func NewNWSProvider(options ...Option) *NWSProvider {
+	return &NWSProvider{
+		client: NewClient(options...),
+		points: make(map[Coordinates]nwsPoint),
+	}
+}
+
+// circuitOpen reports whether the NWS circuit is refusing requests
+func (p *NWSProvider) circuitOpen() bool {
+	return p.client.circuitOpen(p.client.nwsBaseURL)
+}
+
+// Fetch returns the conditions for the current hour of the NWS hourly forecast
+func (p *NWSProvider) Fetch(ctx context.Context, coords Coordinates) (*WeatherData, error) {
+	point, err := p.resolvePoint(ctx, coords)
+	if err != nil {
+		return nil, err
+	}
+
+	q := url.Values{}
+	q.Set("units", "si")
+	var fc NWSForecastResponse
+	if err := p.client.getJSON(ctx, point.forecastHourly, q, &fc); err != nil {
+		return nil, err
+	}
+	if len(fc.Properties.Periods) == 0 {
+		return nil, withKind(ErrDecode, errors.New("NWS hourly forecast has no periods"))
+	}
+
+	period := fc.Properties.Periods[0]
+	temp := period.Temperature
+	if period.TemperatureUnit == "F" {
+		temp = FahrenheitToCelsius(temp)
+	}
+
+	w := &WeatherData{
+		Summary:          period.ShortForecast,
+		WeatherCode:      nwsWeatherCode(period.ShortForecast),
+		TemperatureC:     temp,
+		FeelsLikeC:       temp, // NWS hourly forecasts carry no apparent temperature
+		WindSpeedKmh:     nwsWindSpeed(period.WindSpeed),
+		WindDirectionDeg: compassDegrees(period.WindDirection),
+		Latitude:         coords.Lat,
+		Longitude:        coords.Lon,
+		Timezone:         point.timeZone,
+	}
+	if rh := period.RelativeHumidity.Value; rh != nil {
+		w.HumidityPct = *rh
+	}
+	if t, err := time.Parse(time.RFC3339, period.StartTime); err == nil {
+		w.ObservedAt = t.In(zoneFor(point.timeZone, coords))
+	}
+	return w, nil
+}
+
+// nwsWindSpeed parses an NWS wind speed such as "15 km/h" or "10 to 15 mph"
+// into km/h, taking the upper value of a range. Unparseable input gives 0.
+func nwsWindSpeed(s string) float64 {
+	fields := strings.Fields(s)
+	if len(fields) < 2 {
+		return 0
+	}
+	v, err := strconv.ParseFloat(fields[len(fields)-2], 64)
+	if err != nil {
+		return 0
+	}
+	if fields[len(fields)-1] == "mph" {
+		return MphToKmh(v)
+	}
+	return v
+}
+
+// compassPoints lists the 16 compass points clockwise from north
+var compassPoints = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+// compassDegrees converts a compass point such as "NW" to degrees; unknown points give 0
+func compassDegrees(point string) float64 {
+	for i, p := range compassPoints {
+		if strings.EqualFold(p, point) {
+			return float64(i) * 22.5
+		}
+	}
+	return 0
+}
+
+// resolvePoint maps coordinates to an NWS gridpoint, caching the result
+func (p *NWSProvider) resolvePoint(ctx context.Context, coords Coordinates) (nwsPoint, error) {
+	key := Coordinates{Lat: roundTo(coords.Lat, 4), Lon: roundTo(coords.Lon, 4)}
+
+	p.mu.Lock()
+	point, ok := p.points[key]
+	p.mu.Unlock()
+	if ok {
+		return point, nil
+	}
+
+	var resp NWSPointResponse
+	endpoint := fmt.Sprintf("%s/points/%.4f,%.4f", p.client.nwsBaseURL, key.Lat, key.Lon)
+	if err := p.client.getJSON(ctx, endpoint, nil, &resp); err != nil {
+		return nwsPoint{}, err
+	}
+	if resp.Properties.ForecastHourly == "" {
+		return nwsPoint{}, withKind(ErrDecode, errors.New("NWS point response has no hourly forecast URL"))
+	}
+
+	point = nwsPoint{
+		forecastHourly: resp.Properties.ForecastHourly,
+		timeZone:       resp.Properties.TimeZone,
+	}
+	p.mu.Lock()
+	p.points[key] = point
+	p.mu.Unlock()
+	return point, nil
+}
+
+// nwsWeatherCode approximates the WMO weather code for an NWS short forecast
+func nwsWeatherCode(short string) int {
+	s := strings.ToLower(short)
+	switch {
+	case strings.Contains(s, "thunder"):
+		return 95
+	case strings.Contains(s, "freezing"):
+		return 66
+	case strings.Contains(s, "heavy snow"):
+		return 75
+	case strings.Contains(s, "snow"):
+		if strings.Contains(s, "light") || strings.Contains(s, "chance") {
+			return 71
+		}
+		return 73
+	case strings.Contains(s, "heavy rain"):
+		return 65
+	case strings.Contains(s, "showers"):
+		return 80
+	case strings.Contains(s, "rain"):
+		if strings.Contains(s, "light") || strings.Contains(s, "chance") {
+			return 61
+		}
+		return 63
+	case strings.Contains(s, "drizzle"):
+		return 51
+	case strings.Contains(s, "fog"):
+		return 45
+	case strings.Contains(s, "partly"):
+		return 2
+	case strings.Contains(s, "mostly sunny"), strings.Contains(s, "mostly clear"):
+		return 1
+	case strings.Contains(s, "cloudy"), strings.Contains(s, "overcast"):
+		return 3
+	default:
+		return 0
+	}
+}
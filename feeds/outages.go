@@ -0,0 +1,307 @@
+package feeds
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Outage source formats
+const (
+	// OutageFormatBCHydro is BC Hydro's outage map data (outages-map-data.json)
+	OutageFormatBCHydro = "bchydro"
+	// OutageFormatEagleI is county-level customers-out CSV in the layout of
+	// the DOE EAGLE-I datasets: fips_code, county, state, customers_out (or
+	// sum) and run_start_time columns
+	OutageFormatEagleI = "eaglei"
+)
+
+// OutageSource is one utility outage map or dataset feeding an OutageRegion
+type OutageSource struct {
+	Utility string
+	Format  string // OutageFormatBCHydro or OutageFormatEagleI
+	URL     string
+	State   string // for EAGLE-I data, keeps only this state's counties
+}
+
+// OutageRegion is a region whose outages are summed across its sources
+type OutageRegion struct {
+	ID              string
+	Name            string
+	CustomersServed int // customers in the region, for PercentOut; 0 if unknown
+	Sources         []OutageSource
+}
+
+var (
+	outageRegionsMu sync.RWMutex
+
+	// outageRegions holds regions whose utilities publish open outage data
+	outageRegions = map[string]OutageRegion{
+		"bc": {
+			ID: "bc", Name: "British Columbia", CustomersServed: 2_100_000,
+			Sources: []OutageSource{{
+				Utility: "BC Hydro", Format: OutageFormatBCHydro,
+				URL: "https://www.bchydro.com/power-outages/app/outages-map-data.json",
+			}},
+		},
+	}
+)
+
+// RegisterOutageRegion adds or replaces a region used by FetchOutages, e.g.
+// one aggregating an EAGLE-I export for a state
+func RegisterOutageRegion(region OutageRegion) error {
+	if region.ID == "" || len(region.Sources) == 0 {
+		return fmt.Errorf("invalid outage region %q: ID and at least one source are required", region.ID)
+	}
+	for _, s := range region.Sources {
+		if s.URL == "" || (s.Format != OutageFormatBCHydro && s.Format != OutageFormatEagleI) {
+			return fmt.Errorf("invalid outage source %q for region %q (format %q)", s.Utility, region.ID, s.Format)
+		}
+	}
+	region.ID = strings.ToLower(region.ID)
+	region.Sources = slices.Clone(region.Sources)
+
+	outageRegionsMu.Lock()
+	defer outageRegionsMu.Unlock()
+	outageRegions[region.ID] = region
+	return nil
+}
+
+// Outage is a single outage, or one county's total for EAGLE-I data
+type Outage struct {
+	ID                   string    `json:"id"`
+	Utility              string    `json:"utility"`
+	Area                 string    `json:"area"`
+	Cause                string    `json:"cause,omitempty"`
+	Status               string    `json:"status,omitempty"` // crew status, when published
+	CustomersOut         int       `json:"customersOut"`
+	Latitude             float64   `json:"latitude,omitempty"`
+	Longitude            float64   `json:"longitude,omitempty"`
+	Start                time.Time `json:"start,omitzero"`
+	EstimatedRestoration time.Time `json:"estimatedRestoration,omitzero"`
+}
+
+// OutageReport sums a region's outages
+type OutageReport struct {
+	Region       string  `json:"region"`
+	Name         string  `json:"name"`
+	CustomersOut int     `json:"customersOut"`
+	PercentOut   float64 `json:"percentOut,omitempty"` // of CustomersServed, when known
+	// LatestRestoration is the last estimated restoration time across
+	// outages, i.e. when power should be back everywhere
+	LatestRestoration time.Time `json:"latestRestoration,omitzero"`
+	Outages           []Outage  `json:"outages"` // largest first
+	Updated           time.Time `json:"updated"`
+}
+
+// BCHydroOutage represents an outage in BC Hydro's outage map data
+type BCHydroOutage struct {
+	ID                    int64   `json:"id"`
+	Municipality          string  `json:"municipality"`
+	Area                  string  `json:"area"`
+	Cause                 string  `json:"cause"`
+	NumCustomersOut       int     `json:"numCustomersOut"`
+	CrewStatusDescription string  `json:"crewStatusDescription"`
+	Latitude              float64 `json:"latitude"`
+	Longitude             float64 `json:"longitude"`
+	DateOff               int64   `json:"dateOff"`   // Unix milliseconds
+	EstTimeOn             int64   `json:"estTimeOn"` // Unix milliseconds, 0 when not estimated
+}
+
+// FetchOutages fetches a region's power outages using the default Client
+func FetchOutages(regionID string) (*OutageReport, error) {
+	return defaultClient.FetchOutages(regionID)
+}
+
+// FetchOutages returns the customers without power in a registered region,
+// fetching its sources concurrently. Sources that fail are skipped as long
+// as one succeeds.
+func (c *Client) FetchOutages(regionID string) (*OutageReport, error) {
+	outageRegionsMu.RLock()
+	region, ok := outageRegions[strings.ToLower(regionID)]
+	outageRegionsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: no outage region %q", ErrUnknownLocation, regionID)
+	}
+	return c.fetchOutages(context.Background(), region)
+}
+
+// fetchOutages fetches and sums every source of a region
+func (c *Client) fetchOutages(ctx context.Context, region OutageRegion) (*OutageReport, error) {
+	results := make([][]Outage, len(region.Sources))
+	errs := make([]error, len(region.Sources))
+	var wg sync.WaitGroup
+	for i, src := range region.Sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = c.fetchOutageSource(ctx, src)
+		}()
+	}
+	wg.Wait()
+
+	report := &OutageReport{Region: region.ID, Name: region.Name, Outages: []Outage{}, Updated: time.Now()}
+	succeeded := false
+	for i, outages := range results {
+		if errs[i] != nil {
+			continue
+		}
+		succeeded = true
+		for _, o := range outages {
+			report.CustomersOut += o.CustomersOut
+			if o.EstimatedRestoration.After(report.LatestRestoration) {
+				report.LatestRestoration = o.EstimatedRestoration
+			}
+		}
+		report.Outages = append(report.Outages, outages...)
+	}
+	if !succeeded {
+		return nil, errors.Join(errs...)
+	}
+	if region.CustomersServed > 0 {
+		report.PercentOut = roundTo(100*float64(report.CustomersOut)/float64(region.CustomersServed), 2)
+	}
+	slices.SortFunc(report.Outages, func(a, b Outage) int {
+		return cmp.Or(cmp.Compare(b.CustomersOut, a.CustomersOut), strings.Compare(a.ID, b.ID))
+	})
+	return report, nil
+}
+
+// fetchOutageSource reads one source's outages
+func (c *Client) fetchOutageSource(ctx context.Context, src OutageSource) ([]Outage, error) {
+	switch src.Format {
+	case OutageFormatBCHydro:
+		return c.fetchBCHydroOutages(ctx, src)
+	case OutageFormatEagleI:
+		return c.fetchEagleIOutages(ctx, src)
+	}
+	return nil, fmt.Errorf("unsupported outage format %q", src.Format)
+}
+
+// fetchBCHydroOutages converts BC Hydro's outage map data
+func (c *Client) fetchBCHydroOutages(ctx context.Context, src OutageSource) ([]Outage, error) {
+	var apiResp []BCHydroOutage
+	if err := c.getJSON(ctx, src.URL, nil, &apiResp); err != nil {
+		return nil, err
+	}
+	outages := make([]Outage, 0, len(apiResp))
+	for _, o := range apiResp {
+		out := Outage{
+			ID:           strconv.FormatInt(o.ID, 10),
+			Utility:      src.Utility,
+			Area:         strings.Join(nonEmpty(o.Municipality, o.Area), " – "),
+			Cause:        o.Cause,
+			Status:       o.CrewStatusDescription,
+			CustomersOut: o.NumCustomersOut,
+			Latitude:     o.Latitude,
+			Longitude:    o.Longitude,
+		}
+		if o.DateOff > 0 {
+			out.Start = time.UnixMilli(o.DateOff)
+		}
+		if o.EstTimeOn > 0 {
+			out.EstimatedRestoration = time.UnixMilli(o.EstTimeOn)
+		}
+		outages = append(outages, out)
+	}
+	return outages, nil
+}
+
+// fetchEagleIOutages reads county totals from the latest run in an EAGLE-I
+// style CSV, optionally limited to one state
+func (c *Client) fetchEagleIOutages(ctx context.Context, src OutageSource) ([]Outage, error) {
+	body, err := c.cachedGet(ctx, src.URL)
+	if err != nil {
+		return nil, err
+	}
+	outages, err := parseEagleI(body, src)
+	if err != nil {
+		c.evict(src.URL)
+		return nil, fmt.Errorf("failed to parse outage data: %w", withKind(ErrDecode, err))
+	}
+	return outages, nil
+}
+
+// parseEagleI parses EAGLE-I style CSV, keeping the rows of the most recent
+// run_start_time
+func parseEagleI(body []byte, src OutageSource) ([]Outage, error) {
+	r := csv.NewReader(bytes.NewReader(body))
+	r.ReuseRecord = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	col := make(map[string]int, len(header))
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	countCol, ok := col["customers_out"]
+	if !ok {
+		countCol, ok = col["sum"]
+	}
+	fipsCol, okFIPS := col["fips_code"]
+	countyCol, okCounty := col["county"]
+	stateCol, okState := col["state"]
+	timeCol, okTime := col["run_start_time"]
+	if !ok || !okFIPS || !okCounty || !okState || !okTime {
+		return nil, fmt.Errorf("missing EAGLE-I columns in header %v", header)
+	}
+
+	var latest time.Time
+	var outages []Outage
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		state := strings.TrimSpace(rec[stateCol])
+		if src.State != "" && !strings.EqualFold(state, src.State) {
+			continue
+		}
+		run, err := time.Parse(time.DateTime, strings.TrimSpace(rec[timeCol]))
+		if err != nil {
+			return nil, fmt.Errorf("bad run_start_time %q: %w", rec[timeCol], err)
+		}
+		if run.Before(latest) {
+			continue
+		}
+		if run.After(latest) {
+			latest = run
+			outages = outages[:0]
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(rec[countCol]), 64)
+		if err != nil || n <= 0 {
+			continue
+		}
+		outages = append(outages, Outage{
+			ID:           strings.TrimSpace(rec[fipsCol]),
+			Utility:      src.Utility,
+			Area:         strings.TrimSpace(rec[countyCol]) + ", " + state,
+			CustomersOut: int(n),
+		})
+	}
+	return outages, nil
+}
+
+// nonEmpty returns the non-empty strings among ss
+func nonEmpty(ss ...string) []string {
+	var out []string
+	for _, s := range ss {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
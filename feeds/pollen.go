@@ -0,0 +1,250 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// defaultGooglePollenBaseURL is the Google Pollen API host
+const defaultGooglePollenBaseURL = "https://pollen.googleapis.com"
+
+// Pollen sources reported in PollenData.Source
+const (
+	PollenSourceOpenMeteo = "open-meteo"
+	PollenSourceGoogle    = "google"
+)
+
+// pollenCategories names the PollenLevel.Level values
+var pollenCategories = []string{"None", "Low", "Moderate", "High", "Very High"}
+
+// PollenLevel is the pollen load of one plant group
+type PollenLevel struct {
+	Level       int      `json:"level"`    // 0 (none) to 4 (very high)
+	Category    string   `json:"category"` // None, Low, Moderate, High or Very High
+	GrainsPerM3 *float64 `json:"grainsPerM3,omitempty"`
+}
+
+// PollenData holds current tree, grass and weed pollen levels for a location
+type PollenData struct {
+	Tree      PollenLevel `json:"tree"`
+	Grass     PollenLevel `json:"grass"`
+	Weed      PollenLevel `json:"weed"`
+	Source    string      `json:"source"`
+	Latitude  float64     `json:"latitude"`
+	Longitude float64     `json:"longitude"`
+}
+
+// OpenMeteoPollenResponse represents a pollen response from the Open-Meteo air quality API
+type OpenMeteoPollenResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Current   struct {
+		Alder   *float64 `json:"alder_pollen"`
+		Birch   *float64 `json:"birch_pollen"`
+		Olive   *float64 `json:"olive_pollen"`
+		Grass   *float64 `json:"grass_pollen"`
+		Mugwort *float64 `json:"mugwort_pollen"`
+		Ragweed *float64 `json:"ragweed_pollen"`
+	} `json:"current"`
+}
+
+// GooglePollenResponse represents a forecast:lookup response from the Google Pollen API
+type GooglePollenResponse struct {
+	DailyInfo []struct {
+		PollenTypeInfo []struct {
+			Code      string `json:"code"` // TREE, GRASS or WEED
+			IndexInfo *struct {
+				Value int `json:"value"` // Universal Pollen Index, 0–5
+			} `json:"indexInfo"`
+		} `json:"pollenTypeInfo"`
+	} `json:"dailyInfo"`
+}
+
+// pollenThresholds are the grains/m³ at which each plant group reaches the
+// Low, Moderate, High and Very High levels
+var pollenThresholds = map[string][4]float64{
+	"tree":  {1, 15, 90, 1500},
+	"grass": {1, 5, 20, 200},
+	"weed":  {1, 10, 50, 500},
+}
+
+// WithGooglePollenKey fetches pollen from the Google Pollen API with the given
+// API key instead of Open-Meteo, whose pollen data only covers Europe
+func WithGooglePollenKey(key string) Option {
+	return func(c *Client) {
+		c.googlePollenKey = key
+	}
+}
+
+// SetGooglePollenKey replaces the Google Pollen API key, e.g. after rotating
+// it. An empty key switches back to Open-Meteo.
+func (c *Client) SetGooglePollenKey(key string) {
+	c.keyMu.Lock()
+	defer c.keyMu.Unlock()
+	c.googlePollenKey = key
+}
+
+// googlePollenAPIKey returns the current Google Pollen API key
+func (c *Client) googlePollenAPIKey() string {
+	c.keyMu.RLock()
+	defer c.keyMu.RUnlock()
+	return c.googlePollenKey
+}
+
+// WithGooglePollenBaseURL points Google Pollen API requests at another host
+func WithGooglePollenBaseURL(base string) Option {
+	return func(c *Client) {
+		c.googlePollenBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// FetchPollen fetches pollen levels for a country using the default Client
+func FetchPollen(country string) (*PollenData, error) {
+	return defaultClient.FetchPollen(country)
+}
+
+// FetchPollen fetches current pollen levels at a country's coordinates
+func (c *Client) FetchPollen(country string) (*PollenData, error) {
+	coords, _, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchPollen(context.Background(), coords)
+}
+
+// FetchPollenByCoords fetches pollen levels for coordinates using the default Client
+func FetchPollenByCoords(lat, lon float64) (*PollenData, error) {
+	return defaultClient.FetchPollenByCoords(lat, lon)
+}
+
+// FetchPollenByCoords fetches current tree, grass and weed pollen levels at
+// the given coordinates. Without WithGooglePollenKey the data comes from
+// Open-Meteo, which has no pollen outside Europe, so North American locations
+// return ErrPollenUnavailable.
+func (c *Client) FetchPollenByCoords(lat, lon float64) (*PollenData, error) {
+	coords := Coordinates{Lat: lat, Lon: lon}
+	if err := coords.Validate(); err != nil {
+		return nil, err
+	}
+	return c.fetchPollen(context.Background(), coords)
+}
+
+// fetchPollen fetches pollen levels at coords from the configured source
+func (c *Client) fetchPollen(ctx context.Context, coords Coordinates) (*PollenData, error) {
+	if key := c.googlePollenAPIKey(); key != "" {
+		return c.fetchGooglePollen(ctx, key, coords)
+	}
+	return c.fetchOpenMeteoPollen(ctx, coords)
+}
+
+// fetchOpenMeteoPollen fetches pollen concentrations from the Open-Meteo air
+// quality API, grouping alder, birch and olive as tree and mugwort and
+// ragweed as weed pollen
+func (c *Client) fetchOpenMeteoPollen(ctx context.Context, coords Coordinates) (*PollenData, error) {
+	q := coords.query()
+	q.Set("current", "alder_pollen,birch_pollen,olive_pollen,grass_pollen,mugwort_pollen,ragweed_pollen")
+
+	var apiResp OpenMeteoPollenResponse
+	if err := c.getOpenMeteo(ctx, c.airQualityBaseURL+"/v1/air-quality", q, &apiResp); err != nil {
+		return nil, err
+	}
+
+	cur := apiResp.Current
+	tree := maxPollen(cur.Alder, cur.Birch, cur.Olive)
+	grass := maxPollen(cur.Grass)
+	weed := maxPollen(cur.Mugwort, cur.Ragweed)
+	if tree == nil && grass == nil && weed == nil {
+		return nil, fmt.Errorf("%w at %.4f,%.4f", ErrPollenUnavailable, coords.Lat, coords.Lon)
+	}
+
+	return &PollenData{
+		Tree:      pollenLevelFromGrains("tree", tree),
+		Grass:     pollenLevelFromGrains("grass", grass),
+		Weed:      pollenLevelFromGrains("weed", weed),
+		Source:    PollenSourceOpenMeteo,
+		Latitude:  apiResp.Latitude,
+		Longitude: apiResp.Longitude,
+	}, nil
+}
+
+// fetchGooglePollen fetches today's Universal Pollen Index values from the Google Pollen API
+func (c *Client) fetchGooglePollen(ctx context.Context, key string, coords Coordinates) (*PollenData, error) {
+	q := url.Values{}
+	q.Set("key", key)
+	q.Set("location.latitude", fmt.Sprintf("%.4f", coords.Lat))
+	q.Set("location.longitude", fmt.Sprintf("%.4f", coords.Lon))
+	q.Set("days", "1")
+	q.Set("plantsDescription", "false")
+
+	var apiResp GooglePollenResponse
+	if err := c.getJSON(ctx, c.googlePollenBaseURL+"/v1/forecast:lookup", q, &apiResp); err != nil {
+		return nil, err
+	}
+	if len(apiResp.DailyInfo) == 0 {
+		return nil, fmt.Errorf("%w at %.4f,%.4f", ErrPollenUnavailable, coords.Lat, coords.Lon)
+	}
+
+	data := &PollenData{
+		Tree:      pollenLevel(0),
+		Grass:     pollenLevel(0),
+		Weed:      pollenLevel(0),
+		Source:    PollenSourceGoogle,
+		Latitude:  coords.Lat,
+		Longitude: coords.Lon,
+	}
+	for _, info := range apiResp.DailyInfo[0].PollenTypeInfo {
+		if info.IndexInfo == nil {
+			continue
+		}
+		// The UPI has a separate "Very Low" step (1); fold it into Low
+		upi := info.IndexInfo.Value
+		if upi > 1 {
+			upi--
+		}
+		level := pollenLevel(upi)
+		switch info.Code {
+		case "TREE":
+			data.Tree = level
+		case "GRASS":
+			data.Grass = level
+		case "WEED":
+			data.Weed = level
+		}
+	}
+	return data, nil
+}
+
+// maxPollen returns the highest of the reported concentrations, or nil if none were reported
+func maxPollen(values ...*float64) *float64 {
+	var highest *float64
+	for _, v := range values {
+		if v != nil && (highest == nil || *v > *highest) {
+			highest = v
+		}
+	}
+	return highest
+}
+
+// pollenLevelFromGrains classifies a concentration using the thresholds for group
+func pollenLevelFromGrains(group string, grains *float64) PollenLevel {
+	if grains == nil {
+		return pollenLevel(0)
+	}
+	level := 0
+	for _, t := range pollenThresholds[group] {
+		if *grains >= t {
+			level++
+		}
+	}
+	pl := pollenLevel(level)
+	pl.GrainsPerM3 = grains
+	return pl
+}
+
+// pollenLevel returns the PollenLevel for a 0–4 level, clamping out-of-range values
+func pollenLevel(level int) PollenLevel {
+	level = max(0, min(level, len(pollenCategories)-1))
+	return PollenLevel{Level: level, Category: pollenCategories[level]}
+}
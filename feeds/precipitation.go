@@ -0,0 +1,47 @@
+package feeds
+
+// Precipitation types returned by WeatherData.PrecipitationType
+const (
+	PrecipitationNone     = "none"
+	PrecipitationRain     = "rain"
+	PrecipitationSnow     = "snow"
+	PrecipitationFreezing = "sleet/freezing"
+)
+
+// PrecipitationType classifies the precipitation in the reading:
+//
+//   - freezing drizzle or freezing rain codes (56, 57, 66, 67) are sleet/freezing
+//   - measured rain and snowfall together are sleet/freezing
+//   - measured snowfall alone is snow
+//   - measured rain is rain, or sleet/freezing at or below 0°C
+//   - with nothing measured, snow codes (71–77, 85, 86) are snow and drizzle,
+//     rain, shower and thunderstorm codes are rain (sleet/freezing at or
+//     below 0°C)
+//   - anything else is none
+func (w WeatherData) PrecipitationType() string {
+	code := w.WeatherCode
+	freezing := w.TemperatureC <= 0
+
+	switch {
+	case code == 56 || code == 57 || code == 66 || code == 67:
+		return PrecipitationFreezing
+	case w.RainMM > 0 && w.SnowfallCM > 0:
+		return PrecipitationFreezing
+	case w.SnowfallCM > 0:
+		return PrecipitationSnow
+	case w.RainMM > 0:
+		if freezing {
+			return PrecipitationFreezing
+		}
+		return PrecipitationRain
+	case (code >= 71 && code <= 77) || code == 85 || code == 86:
+		return PrecipitationSnow
+	case (code >= 51 && code <= 65) || (code >= 80 && code <= 82) || code >= 95:
+		if freezing {
+			return PrecipitationFreezing
+		}
+		return PrecipitationRain
+	default:
+		return PrecipitationNone
+	}
+}
@@ -0,0 +1,47 @@
+package feeds
+
+import "context"
+
+// WeatherProvider fetches current conditions for a location. Implementations
+// return raw readings; the Client fills in Source and applies rounding and
+// result transforms.
+type WeatherProvider interface {
+	Fetch(ctx context.Context, coords Coordinates) (*WeatherData, error)
+}
+
+// Built-in provider names reported in WeatherData.Provider. Other providers
+// are named after their type.
+const (
+	ProviderOpenMeteo = "open-meteo"
+	ProviderNWS       = "nws"
+	ProviderECCC      = "eccc"
+)
+
+// WithProvider sets the provider used for current-conditions fetches
+// (FetchWeather, FetchWeatherByCoords, ...). Open-Meteo is the default.
+// Forecast, history and other Open-Meteo specific calls are unaffected.
+func WithProvider(p WeatherProvider) Option {
+	return func(c *Client) {
+		c.provider = p
+	}
+}
+
+// OpenMeteoProvider is the default WeatherProvider backed by the Open-Meteo forecast API
+type OpenMeteoProvider struct {
+	client *Client
+}
+
+// NewOpenMeteoProvider creates an Open-Meteo provider whose requests use the given Client options
+func NewOpenMeteoProvider(options ...Option) *OpenMeteoProvider {
+	return &OpenMeteoProvider{client: NewClient(options...)}
+}
+
+// Fetch fetches current conditions at coords from Open-Meteo
+func (p *OpenMeteoProvider) Fetch(ctx context.Context, coords Coordinates) (*WeatherData, error) {
+	return p.client.openMeteoCurrent(ctx, coords)
+}
+
+// circuitOpen reports whether the Open-Meteo circuit is refusing requests
+func (p *OpenMeteoProvider) circuitOpen() bool {
+	return p.client.circuitOpen(p.client.forecastBaseURL)
+}
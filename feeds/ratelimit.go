@@ -0,0 +1,94 @@
+package feeds
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// rateLimit is a requests-per-second limit with a burst allowance
+type rateLimit struct {
+	perSecond float64
+	burst     int
+}
+
+// tokenBucket is a token-bucket rate limiter for one upstream host
+type tokenBucket struct {
+	limit rateLimit
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// WithRateLimit limits requests to every upstream host to perSecond on
+// average, allowing bursts of up to burst requests. Retries count as
+// requests. Limits are per host, so Open-Meteo and NWS calls do not share a
+// budget; WithHostRateLimit overrides the limit for one host. Requests wait
+// for a token, or fail with the context's error if it ends first.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.defaultRateLimit = &rateLimit{perSecond: perSecond, burst: max(burst, 1)}
+	}
+}
+
+// WithHostRateLimit limits requests to one upstream host, such as
+// "api.weather.gov", to perSecond with bursts of up to burst requests
+func WithHostRateLimit(host string, perSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.hostRateLimits[host] = rateLimit{perSecond: perSecond, burst: max(burst, 1)}
+	}
+}
+
+// wait blocks until a token is available or ctx ends
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = min(float64(b.limit.burst), b.tokens+now.Sub(b.last).Seconds()*b.limit.perSecond)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.limit.perSecond * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// limiterFor returns the rate limiter for target's host, or nil if unlimited
+func (c *Client) limiterFor(target string) *tokenBucket {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil
+	}
+	limit, ok := c.hostRateLimits[u.Host]
+	if !ok {
+		if c.defaultRateLimit == nil {
+			return nil
+		}
+		limit = *c.defaultRateLimit
+	}
+	if limit.perSecond <= 0 {
+		return nil
+	}
+
+	c.limiterMu.Lock()
+	defer c.limiterMu.Unlock()
+	b, ok := c.limiters[u.Host]
+	if !ok {
+		b = &tokenBucket{limit: limit, tokens: float64(limit.burst), last: time.Now()}
+		c.limiters[u.Host] = b
+	}
+	return b
+}
@@ -0,0 +1,124 @@
+package feeds
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// regionsJSON lists the built-in states, provinces and territories by
+// country, each located at its capital
+//
+//go:embed data/regions.json
+var regionsJSON []byte
+
+// Region registries, guarded by coordinatesMu
+var (
+	// naRegionCoordinates maps country and upper-case region code to coordinates
+	naRegionCoordinates map[string]map[string]Coordinates
+
+	// naRegionNames maps country and lower-case region name to region code
+	naRegionNames map[string]map[string]string
+)
+
+func init() {
+	var err error
+	naRegionCoordinates, naRegionNames, err = loadRegions(regionsJSON)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// loadRegions parses the region registry, rejecting malformed codes,
+// duplicates and out-of-range coordinates
+func loadRegions(data []byte) (map[string]map[string]Coordinates, map[string]map[string]string, error) {
+	var byCountry map[string][]placeEntry
+	if err := json.Unmarshal(data, &byCountry); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse region registry: %w", err)
+	}
+
+	regions := make(map[string]map[string]Coordinates, len(byCountry))
+	names := make(map[string]map[string]string, len(byCountry))
+	for country, entries := range byCountry {
+		regions[country] = make(map[string]Coordinates, len(entries))
+		names[country] = make(map[string]string, len(entries))
+		for _, e := range entries {
+			if len(e.Code) < 2 || len(e.Code) > 3 || strings.ToUpper(e.Code) != e.Code {
+				return nil, nil, fmt.Errorf("region registry: %s: invalid code %q", country, e.Code)
+			}
+			if _, dup := regions[country][e.Code]; dup {
+				return nil, nil, fmt.Errorf("region registry: %s: duplicate code %q", country, e.Code)
+			}
+			coords := Coordinates{Lat: e.Lat, Lon: e.Lon}
+			if err := coords.Validate(); err != nil {
+				return nil, nil, fmt.Errorf("region registry: %s-%s (%s): %w", country, e.Code, e.City, err)
+			}
+			regions[country][e.Code] = coords
+			names[country][strings.ToLower(e.Name)] = e.Code
+		}
+	}
+	return regions, names, nil
+}
+
+// lookupRegion returns the registered coordinates for a region, given by
+// code ("TX") or name ("Texas"), ignoring case
+func lookupRegion(country, region string) (Coordinates, bool) {
+	coordinatesMu.RLock()
+	defer coordinatesMu.RUnlock()
+
+	country = strings.ToUpper(country)
+	region = strings.TrimSpace(region)
+	if coords, ok := naRegionCoordinates[country][strings.ToUpper(region)]; ok {
+		return coords, true
+	}
+	code, ok := naRegionNames[country][strings.ToLower(region)]
+	if !ok {
+		return Coordinates{}, false
+	}
+	coords, ok := naRegionCoordinates[country][code]
+	return coords, ok
+}
+
+// Regions returns the registered region codes of a country in sorted order
+func Regions(country string) []string {
+	coordinatesMu.RLock()
+	defer coordinatesMu.RUnlock()
+	return slices.Sorted(maps.Keys(naRegionCoordinates[strings.ToUpper(country)]))
+}
+
+// RegisterRegion adds or replaces the coordinates used for a region code in a country
+func RegisterRegion(country, region string, coords Coordinates) {
+	coordinatesMu.Lock()
+	defer coordinatesMu.Unlock()
+
+	country = strings.ToUpper(country)
+	if naRegionCoordinates[country] == nil {
+		naRegionCoordinates[country] = make(map[string]Coordinates)
+	}
+	naRegionCoordinates[country][strings.ToUpper(region)] = coords
+}
+
+// FetchWeatherForRegion fetches weather for a region using the default Client
+func FetchWeatherForRegion(country, region string) (*WeatherData, error) {
+	return defaultClient.FetchWeatherForRegion(country, region)
+}
+
+// FetchWeatherForRegion fetches weather for a US state, Canadian province or
+// territory, or Mexican state, given by code ("TX", "QC", "JAL") or name, at
+// its capital. Unknown regions fall back to the country's default
+// coordinates, or fail with ErrUnknownRegion if the Client was created
+// WithNoFallback.
+func (c *Client) FetchWeatherForRegion(country, region string) (*WeatherData, error) {
+	ctx := context.Background()
+	if coords, ok := lookupRegion(country, region); ok {
+		return c.fetchCurrent(ctx, coords, SourceRegionMap)
+	}
+	if c.noFallback {
+		return nil, fmt.Errorf("%w: %q in %q", ErrUnknownRegion, region, country)
+	}
+	return c.FetchWeatherContext(ctx, country)
+}
@@ -0,0 +1,140 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Feed is a named source of location data that can be registered at runtime,
+// including by application code, and is fetched by every Aggregator using
+// the Registry
+type Feed interface {
+	// Name identifies the feed; it is the key of its result in Snapshot.Feeds
+	Name() string
+	// Fetch fetches the feed for a location. The context carries the feed's timeout.
+	Fetch(ctx context.Context, loc Location) (any, error)
+	// TTL is how long a result stays fresh, i.e. how often it is worth refetching
+	TTL() time.Duration
+}
+
+// FeedTimeouter is implemented by feeds that need a timeout other than the
+// Aggregator's default of 5s
+type FeedTimeouter interface {
+	Timeout() time.Duration
+}
+
+// funcFeed adapts a FeedFunc to Feed
+type funcFeed struct {
+	name string
+	ttl  time.Duration
+	fn   FeedFunc
+}
+
+// Name returns the feed's name
+func (f funcFeed) Name() string { return f.name }
+
+// TTL returns how long the feed's results stay fresh
+func (f funcFeed) TTL() time.Duration { return f.ttl }
+
+// Fetch calls the feed's function
+func (f funcFeed) Fetch(ctx context.Context, loc Location) (any, error) {
+	return f.fn(ctx, loc)
+}
+
+// NewFeed returns a Feed that calls fn
+func NewFeed(name string, ttl time.Duration, fn FeedFunc) Feed {
+	return funcFeed{name: name, ttl: ttl, fn: fn}
+}
+
+// Registry holds feeds by name. It is safe for concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	feeds map[string]Feed
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{feeds: make(map[string]Feed)}
+}
+
+// DefaultRegistry is the Registry used by Aggregators unless given another
+var DefaultRegistry = NewRegistry()
+
+// RegisterFeed adds or replaces a feed in DefaultRegistry
+func RegisterFeed(f Feed) error {
+	return DefaultRegistry.Register(f)
+}
+
+// Register adds or replaces a feed. "weather" is reserved for the
+// Aggregator's current weather.
+func (r *Registry) Register(f Feed) error {
+	if f == nil {
+		return errors.New("feed is nil")
+	}
+	name := f.Name()
+	if strings.TrimSpace(name) == "" {
+		return errors.New("feed name is empty")
+	}
+	if name == "weather" {
+		return errors.New(`feed name "weather" is reserved`)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.feeds[name] = f
+	return nil
+}
+
+// Unregister removes a feed, reporting whether it was registered
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.feeds[name]
+	delete(r.feeds, name)
+	return ok
+}
+
+// Get returns a registered feed
+func (r *Registry) Get(name string) (Feed, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.feeds[name]
+	return f, ok
+}
+
+// Feeds returns the registered feeds sorted by name
+func (r *Registry) Feeds() []Feed {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	feeds := make([]Feed, 0, len(r.feeds))
+	for _, f := range r.feeds {
+		feeds = append(feeds, f)
+	}
+	slices.SortFunc(feeds, func(a, b Feed) int { return strings.Compare(a.Name(), b.Name()) })
+	return feeds
+}
+
+// DefaultFeeds returns the package's own location feeds, fetched through c
+// (the default Client if nil): airQuality, sun, alerts and holidays
+func DefaultFeeds(c *Client) []Feed {
+	if c == nil {
+		c = defaultClient
+	}
+	return []Feed{
+		NewFeed("airQuality", c.cacheTTL, func(ctx context.Context, loc Location) (any, error) {
+			return c.fetchAirQuality(ctx, loc.Coordinates)
+		}),
+		NewFeed("sun", 6*time.Hour, func(ctx context.Context, loc Location) (any, error) {
+			return c.fetchSunTimes(ctx, loc.Coordinates)
+		}),
+		NewFeed("alerts", c.cacheTTL, func(ctx context.Context, loc Location) (any, error) {
+			return c.fetchAlerts(ctx, loc.Country, loc.Coordinates)
+		}),
+		NewFeed("holidays", 6*time.Hour, func(ctx context.Context, loc Location) (any, error) {
+			return UpcomingHolidays(loc.Country)
+		}),
+	}
+}
@@ -0,0 +1,158 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// defaultRetryPolicy retries transient failures twice, starting at 200ms
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	Jitter:      0.5,
+}
+
+// RetryPolicy controls how transient upstream failures are retried. The
+// delay before retry n is BaseDelay·2ⁿ⁻¹ capped at MaxDelay, reduced by a
+// random fraction of up to Jitter (0–1) so that many clients do not retry in
+// lockstep.
+type RetryPolicy struct {
+	MaxAttempts int // total attempts including the first; 1 disables retries
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      float64
+}
+
+// Delay returns the wait before the given retry (1 for the first retry). A
+// BaseDelay of zero or less retries immediately.
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	shift := max(retry-1, 0)
+	d := p.BaseDelay << shift
+	if shift >= 63 || d>>shift != p.BaseDelay {
+		d = math.MaxInt64 // the shift overflowed
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(float64(d) * min(p.Jitter, 1) * rand.Float64())
+	}
+	return d
+}
+
+// WithRetry sets how upstream requests are retried. Only 5xx responses and
+// network errors are retried; the Client's own timeouts are not, since each
+// attempt already waited the full timeout. The default is 3 attempts with
+// 200ms–2s backoff; WithRetry(RetryPolicy{MaxAttempts: 1}) disables retries.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// fetch performs a GET request against an upstream API through the host's
+// circuit breaker, retrying transient failures according to the Client's
+// retry policy. Backoff waits end early when ctx is done. When prev is non-nil
+// the request is conditional on its validators.
+func (c *Client) fetch(ctx context.Context, target string, prev *CacheEntry) (entry CacheEntry, err error) {
+	host := hostOf(target)
+	ctx, span := c.startSpan(ctx, spanFetch,
+		SpanAttribute{attrProvider, host},
+		SpanAttribute{attrServer, host},
+		SpanAttribute{attrHTTPMethod, http.MethodGet},
+		SpanAttribute{attrURL, redactURL(target)},
+	)
+	defer func() {
+		var status *UpstreamStatusError
+		if errors.As(err, &status) {
+			span.SetAttributes(SpanAttribute{attrHTTPStatus, status.Code})
+		} else if err == nil {
+			span.SetAttributes(SpanAttribute{attrHTTPStatus, http.StatusOK})
+		}
+		endSpan(span, err)
+	}()
+
+	breaker := c.breakerFor(target)
+	if breaker != nil {
+		if err := breaker.allow(); err != nil {
+			err = fmt.Errorf("%w: %s", err, breaker.host)
+			if c.metrics != nil {
+				c.metrics.ObserveFetch(breaker.host, 0, err)
+			}
+			c.log().DebugContext(ctx, "upstream request refused by open circuit", "host", host)
+			return CacheEntry{}, err
+		}
+	}
+
+	url := redactURL(target)
+	c.log().DebugContext(ctx, "upstream request", "host", host, "url", url)
+	start := time.Now()
+	entry, transient, err := c.fetchWithRetry(ctx, target, prev)
+	elapsed := time.Since(start)
+	if c.metrics != nil {
+		c.metrics.ObserveFetch(host, elapsed, err)
+	}
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// The caller gave up; that says nothing about the upstream
+		c.log().DebugContext(ctx, "upstream request abandoned", "host", host, "url", url, "duration", elapsed, "error", err)
+		if breaker != nil {
+			breaker.abandon()
+		}
+	case err != nil:
+		c.log().WarnContext(ctx, "upstream request failed", "host", host, "url", url, "duration", elapsed,
+			"kind", ErrorKind(err), "error", err)
+		fallthrough
+	default:
+		if err == nil {
+			c.log().DebugContext(ctx, "upstream request finished", "host", host, "url", url, "duration", elapsed)
+		}
+		if breaker != nil {
+			breaker.record(err != nil && isUpstreamFailure(transient, err))
+		}
+		c.recordOutcome(err)
+	}
+	return entry, err
+}
+
+// fetchWithRetry performs the request, retrying transient failures. transient
+// reports whether the final error was transient.
+func (c *Client) fetchWithRetry(ctx context.Context, target string, prev *CacheEntry) (entry CacheEntry, transient bool, err error) {
+	limiter := c.limiterFor(target)
+	for attempt := 1; ; attempt++ {
+		if limiter != nil {
+			if err := limiter.wait(ctx); err != nil {
+				return CacheEntry{}, false, fmt.Errorf("waiting for rate limit: %w", contextError(err))
+			}
+		}
+		entry, retry, err := c.fetchOnce(ctx, target, prev)
+		if err == nil || !retry || attempt >= c.retry.MaxAttempts {
+			return entry, retry, err
+		}
+
+		delay := c.retry.Delay(attempt)
+		c.log().InfoContext(ctx, "retrying upstream request", "host", hostOf(target), "url", redactURL(target),
+			"attempt", attempt, "delay", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return CacheEntry{}, retry, contextError(fmt.Errorf("%w (giving up retries after: %v)", ctx.Err(), err))
+		case <-timer.C:
+		}
+	}
+}
+
+// isTransient reports whether a classified transport error is worth retrying
+func isTransient(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && !errors.Is(err, ErrUpstreamTimeout)
+}
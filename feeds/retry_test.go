@@ -0,0 +1,45 @@
+package feeds
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		retry  int
+		want   time.Duration
+	}{
+		{"first retry", RetryPolicy{BaseDelay: 200 * time.Millisecond, MaxDelay: 2 * time.Second}, 1, 200 * time.Millisecond},
+		{"doubles", RetryPolicy{BaseDelay: 200 * time.Millisecond, MaxDelay: 2 * time.Second}, 3, 800 * time.Millisecond},
+		{"capped", RetryPolicy{BaseDelay: 200 * time.Millisecond, MaxDelay: 2 * time.Second}, 5, 2 * time.Second},
+		{"uncapped", RetryPolicy{BaseDelay: time.Second}, 11, 1024 * time.Second},
+		{"no base delay", RetryPolicy{MaxDelay: 2 * time.Second}, 1, 0},
+		{"no base delay, later retry", RetryPolicy{MaxDelay: 2 * time.Second}, 40, 0},
+		{"negative base delay", RetryPolicy{BaseDelay: -time.Second, MaxDelay: 2 * time.Second}, 2, 0},
+		{"overflow capped", RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Hour}, 64, time.Hour},
+		{"overflow to a negative", RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Hour}, 35, time.Hour},
+		{"overflow uncapped", RetryPolicy{BaseDelay: time.Second}, 100, math.MaxInt64},
+		{"retry zero", RetryPolicy{BaseDelay: time.Second}, 0, time.Second},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Delay(tt.retry); got != tt.want {
+			t.Errorf("%s: Delay(%d) = %v, want %v", tt.name, tt.retry, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyDelayJitter(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 4 * time.Second, Jitter: 0.5}
+	for range 100 {
+		if d := p.Delay(2); d <= time.Second || d > 2*time.Second {
+			t.Fatalf("Delay(2) = %v, want within (1s, 2s]", d)
+		}
+	}
+	if d := (RetryPolicy{Jitter: 1}).Delay(1); d != 0 {
+		t.Errorf("Delay with no base delay and jitter = %v, want 0", d)
+	}
+}
@@ -0,0 +1,529 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default Scheduler refresh intervals
+const (
+	// DefaultWeatherInterval is how often the Scheduler refreshes current weather
+	DefaultWeatherInterval = 10 * time.Minute
+	// defaultFeedInterval applies to feeds whose TTL is not positive
+	defaultFeedInterval = 10 * time.Minute
+	// subscriberBuffer is how many updates a slow subscriber may fall behind
+	subscriberBuffer = 16
+)
+
+// SchedulerOption configures a Scheduler
+type SchedulerOption func(*Scheduler)
+
+// WithFeedInterval overrides how often a feed ("weather" included) is
+// refreshed, instead of its TTL
+func WithFeedInterval(name string, interval time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		if interval > 0 {
+			s.intervals[name] = interval
+		}
+	}
+}
+
+// WithSchedulerRegistry sets the Registry whose feeds are refreshed; the
+// default is DefaultRegistry
+func WithSchedulerRegistry(r *Registry) SchedulerOption {
+	return func(s *Scheduler) {
+		s.registry = r
+	}
+}
+
+// Scheduler refreshes current weather and every registered feed for tracked
+// locations in the background, each on its own interval, and keeps the
+// latest Snapshot per location so callers read hot data instead of fetching
+// on demand
+type Scheduler struct {
+	client   *Client
+	registry *Registry
+
+	mu        sync.RWMutex
+	intervals map[string]time.Duration
+	locations map[string]trackedLocation
+	snapshots map[string]*Snapshot
+	due       map[dueKey]time.Time
+	started   map[dueKey]time.Time // when each feed's stored result was fetched
+	history   map[string][]WeatherReading
+
+	subsMu   sync.Mutex
+	subs     map[chan *Snapshot]bool // true for latest-wins subscribers
+	watchers map[chan Change][]ChangeFilter
+
+	wake chan struct{}
+}
+
+// trackedLocation is a location and how its coordinates were resolved
+type trackedLocation struct {
+	loc    Location
+	source string
+}
+
+// dueKey identifies one feed of one tracked location
+type dueKey struct {
+	location, feed string
+}
+
+// scheduledFeed is a feed ready to be run by the Scheduler
+type scheduledFeed struct {
+	name     string
+	fn       FeedFunc
+	timeout  time.Duration
+	interval time.Duration
+}
+
+// NewScheduler creates a Scheduler that fetches weather through c (the
+// default Client if nil). Locations are added with Track or TrackCountry and
+// refreshed once Run is called.
+func NewScheduler(c *Client, options ...SchedulerOption) *Scheduler {
+	if c == nil {
+		c = defaultClient
+	}
+	s := &Scheduler{
+		client:    c,
+		registry:  DefaultRegistry,
+		intervals: make(map[string]time.Duration),
+		locations: make(map[string]trackedLocation),
+		snapshots: make(map[string]*Snapshot),
+		due:       make(map[dueKey]time.Time),
+		started:   make(map[dueKey]time.Time),
+		history:   make(map[string][]WeatherReading),
+		subs:      make(map[chan *Snapshot]bool),
+		watchers:  make(map[chan Change][]ChangeFilter),
+		wake:      make(chan struct{}, 1),
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+// locationKey identifies a location: its country code when set, otherwise
+// its coordinates
+func locationKey(loc Location) string {
+	if loc.Country != "" {
+		return strings.ToUpper(loc.Country)
+	}
+	return fmt.Sprintf("%.4f,%.4f", loc.Lat, loc.Lon)
+}
+
+// Track adds a location to refresh. Locations with a country are keyed by
+// it, so tracking a country twice replaces the first.
+func (s *Scheduler) Track(loc Location) error {
+	if err := loc.Validate(); err != nil {
+		return err
+	}
+	s.track(loc, SourceExplicit)
+	return nil
+}
+
+// TrackCountry adds a country to refresh at its registered coordinates
+func (s *Scheduler) TrackCountry(country string) error {
+	coords, source, err := s.client.resolveCountry(country)
+	if err != nil {
+		return err
+	}
+	if source == SourceFallback {
+		return fmt.Errorf("%w: %q", ErrUnknownCountry, country)
+	}
+	s.track(Location{Country: strings.ToUpper(country), Coordinates: coords}, source)
+	return nil
+}
+
+// track stores a location and wakes Run to refresh it right away
+func (s *Scheduler) track(loc Location, source string) {
+	key := locationKey(loc)
+	s.mu.Lock()
+	s.locations[key] = trackedLocation{loc: loc, source: source}
+	for k := range s.due {
+		if k.location == key {
+			delete(s.due, k)
+		}
+	}
+	s.mu.Unlock()
+	s.poke()
+}
+
+// Untrack stops refreshing a location and drops its snapshot and history
+func (s *Scheduler) Untrack(loc Location) {
+	key := locationKey(loc)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.locations, key)
+	delete(s.snapshots, key)
+	delete(s.history, key)
+	for k := range s.due {
+		if k.location == key {
+			delete(s.due, k)
+		}
+	}
+	for k := range s.started {
+		if k.location == key {
+			delete(s.started, k)
+		}
+	}
+}
+
+// poke wakes Run without blocking
+func (s *Scheduler) poke() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Tracked reports whether a location is tracked
+func (s *Scheduler) Tracked(loc Location) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.locations[locationKey(loc)]
+	return ok
+}
+
+// SetFeedInterval changes how often a feed is refreshed while running, like
+// WithFeedInterval; zero or less reverts to the feed's TTL. Refreshes already
+// scheduled move to the new interval after the feed's last refresh.
+func (s *Scheduler) SetFeedInterval(name string, interval time.Duration) {
+	old := s.Interval(name)
+	s.mu.Lock()
+	if interval > 0 {
+		s.intervals[name] = interval
+	} else {
+		delete(s.intervals, name)
+	}
+	s.mu.Unlock()
+	shift := s.Interval(name) - old
+	if old == 0 || shift == 0 {
+		return
+	}
+	s.mu.Lock()
+	for k, at := range s.due {
+		if k.feed == name {
+			s.due[k] = at.Add(shift)
+		}
+	}
+	s.mu.Unlock()
+	s.poke()
+}
+
+// Interval returns how often a feed is refreshed, or 0 for a feed that is
+// neither "weather" nor in the Scheduler's Registry
+func (s *Scheduler) Interval(name string) time.Duration {
+	if name == "weather" {
+		return s.interval(name, DefaultWeatherInterval)
+	}
+	if s.registry == nil {
+		return 0
+	}
+	f, ok := s.registry.Get(name)
+	if !ok {
+		return 0
+	}
+	return s.interval(name, f.TTL())
+}
+
+// Get returns the latest snapshot of a tracked location, or false before its
+// first refresh. Snapshots are shared and must not be modified.
+func (s *Scheduler) Get(loc Location) (*Snapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snap, ok := s.snapshots[locationKey(loc)]
+	return snap, ok
+}
+
+// History returns the weather readings of a tracked location from the last
+// 25 hours, oldest first, which its snapshots' Trends are computed from
+func (s *Scheduler) History(loc Location) []WeatherReading {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.history[locationKey(loc)])
+}
+
+// Snapshots returns the latest snapshot of every tracked location that has
+// been refreshed, keyed like Change.Location
+func (s *Scheduler) Snapshots() map[string]*Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.snapshots)
+}
+
+// SubscribeOption configures a subscription made with Scheduler.Subscribe
+type SubscribeOption func(*subscription)
+
+// subscription is how a subscriber is sent snapshots
+type subscription struct {
+	latestWins bool
+}
+
+// WithLatestWins makes a subscription hold only the newest snapshot for a
+// slow subscriber, instead of queuing up to 16 and then missing new ones. A
+// snapshot not yet received when the next is published is dropped and
+// replaced by it, so the subscriber always reads the latest snapshot and
+// never works through a stale backlog. With several tracked locations a
+// dropped snapshot may be another location's; such subscribers should read
+// Snapshots when woken rather than rely on the one snapshot received.
+func WithLatestWins() SubscribeOption {
+	return func(sub *subscription) {
+		sub.latestWins = true
+	}
+}
+
+// Subscribe returns a channel receiving each location's snapshot whenever
+// one of its feeds is refreshed, and a function that ends the subscription
+// and closes the channel. A subscriber more than 16 updates behind misses
+// updates rather than stalling the Scheduler, unless it subscribes
+// WithLatestWins.
+func (s *Scheduler) Subscribe(options ...SubscribeOption) (<-chan *Snapshot, func()) {
+	var sub subscription
+	for _, opt := range options {
+		opt(&sub)
+	}
+	size := subscriberBuffer
+	if sub.latestWins {
+		size = 1
+	}
+	ch := make(chan *Snapshot, size)
+	s.subsMu.Lock()
+	s.subs[ch] = sub.latestWins
+	s.subsMu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.subsMu.Lock()
+			delete(s.subs, ch)
+			s.subsMu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// publish sends a snapshot to every subscriber that has room for it, first
+// dropping the stale snapshot of latest-wins subscribers that have none
+func (s *Scheduler) publish(snap *Snapshot) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for ch, latestWins := range s.subs {
+		select {
+		case ch <- snap:
+			continue
+		default:
+		}
+		if !latestWins {
+			continue
+		}
+		select {
+		case <-ch:
+		default: // received meanwhile
+		}
+		select {
+		case ch <- snap:
+		default:
+		}
+	}
+}
+
+// Run refreshes tracked locations until ctx is done and returns its error.
+// Each feed is refreshed every WithFeedInterval, or its TTL, and feeds
+// registered while running are picked up at the next refresh.
+func (s *Scheduler) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		next := s.dispatch(ctx, &wg)
+		timer.Reset(time.Until(next))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// dispatch starts refreshes of every feed that is due and returns when the
+// next one will be
+func (s *Scheduler) dispatch(ctx context.Context, wg *sync.WaitGroup) time.Time {
+	feeds := s.feeds()
+	now := time.Now()
+	next := now.Add(defaultFeedInterval)
+
+	s.mu.Lock()
+	for key, tl := range s.locations {
+		var due []scheduledFeed
+		for _, f := range feeds {
+			dk := dueKey{key, f.name}
+			at, ok := s.due[dk]
+			if !ok || !at.After(now) {
+				at = now.Add(f.interval)
+				s.due[dk] = at
+				due = append(due, f)
+			}
+			if at.Before(next) {
+				next = at
+			}
+		}
+		if len(due) > 0 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.refresh(ctx, key, tl, due)
+			}()
+		}
+	}
+	s.mu.Unlock()
+	return next
+}
+
+// feeds returns weather plus the registry's feeds with their intervals
+func (s *Scheduler) feeds() []scheduledFeed {
+	feeds := []scheduledFeed{{
+		name:     "weather",
+		timeout:  defaultFeedTimeout,
+		interval: s.interval("weather", DefaultWeatherInterval),
+	}}
+	if s.registry == nil {
+		return feeds
+	}
+	for _, f := range s.registry.Feeds() {
+		timeout := defaultFeedTimeout
+		if t, ok := f.(FeedTimeouter); ok && t.Timeout() > 0 {
+			timeout = t.Timeout()
+		}
+		feeds = append(feeds, scheduledFeed{
+			name:     f.Name(),
+			fn:       f.Fetch,
+			timeout:  timeout,
+			interval: s.interval(f.Name(), f.TTL()),
+		})
+	}
+	return feeds
+}
+
+// interval returns a feed's configured interval, else ttl, else the default
+func (s *Scheduler) interval(name string, ttl time.Duration) time.Duration {
+	s.mu.RLock()
+	d, ok := s.intervals[name]
+	s.mu.RUnlock()
+	if ok {
+		return d
+	}
+	if ttl > 0 {
+		return ttl
+	}
+	return defaultFeedInterval
+}
+
+// recordWeather adds a reading to a location's history, dropping readings
+// too old for any trend, and returns the trends up to it. Called with s.mu held.
+func (s *Scheduler) recordWeather(key string, r WeatherReading) []Trend {
+	history := s.history[key]
+	trends := ComputeTrends(r, history)
+	cutoff := r.At.Add(-weatherHistoryAge)
+	i := 0
+	for i < len(history) && history[i].At.Before(cutoff) {
+		i++
+	}
+	s.history[key] = append(history[i:], r)
+	return trends
+}
+
+// refresh runs due feeds for a location concurrently and stores a new
+// snapshot. A failed feed keeps its previous value and records the error,
+// and a result is dropped if a later refresh of the same feed finished first.
+func (s *Scheduler) refresh(ctx context.Context, key string, tl trackedLocation, due []scheduledFeed) {
+	started := time.Now()
+	type result struct {
+		v   any
+		err error
+	}
+	results := make([]result, len(due))
+	var wg sync.WaitGroup
+	for i, f := range due {
+		fn := f.fn
+		if f.name == "weather" {
+			fn = func(ctx context.Context, loc Location) (any, error) {
+				return s.client.fetchCurrent(ctx, loc.Coordinates, tl.source)
+			}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fctx, cancel := context.WithTimeout(ctx, f.timeout)
+			defer cancel()
+			v, err := s.client.runFeed(fctx, f.name, fn, tl.loc)
+			results[i] = result{v, err}
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	if _, ok := s.locations[key]; !ok {
+		s.mu.Unlock()
+		return // untracked while refreshing
+	}
+	snap := &Snapshot{
+		Country:   tl.loc.Country,
+		Latitude:  tl.loc.Lat,
+		Longitude: tl.loc.Lon,
+		Feeds:     make(map[string]any),
+	}
+	prev := s.snapshots[key]
+	if prev != nil {
+		snap.Weather = prev.Weather
+		snap.Trends = prev.Trends
+		snap.Feeds = maps.Clone(prev.Feeds)
+		snap.Errors = maps.Clone(prev.Errors)
+	}
+	for i, f := range due {
+		dk := dueKey{key, f.name}
+		if s.started[dk].After(started) {
+			continue
+		}
+		s.started[dk] = started
+		r := results[i]
+		if r.err != nil {
+			s.client.log().Warn("feed refresh failed", "feed", f.name, "location", key, "error", r.err)
+			if snap.Errors == nil {
+				snap.Errors = make(map[string]string)
+			}
+			snap.Errors[f.name] = r.err.Error()
+			continue
+		}
+		delete(snap.Errors, f.name)
+		if f.name == "weather" {
+			snap.Weather, _ = r.v.(*WeatherData)
+			snap.Trends = s.recordWeather(key, WeatherReading{At: started, Weather: snap.Weather})
+		} else {
+			snap.Feeds[f.name] = r.v
+		}
+	}
+	if len(snap.Errors) == 0 {
+		snap.Errors = nil
+	}
+	snap.stamp(tl.loc.Coordinates)
+	s.snapshots[key] = snap
+	// Publish under the lock so subscribers see a location's snapshots in order
+	s.publish(snap)
+	if changed := diffSnapshots(prev, snap); len(changed) > 0 {
+		s.publishChange(Change{Location: key, Previous: prev, Current: snap, Feeds: changed})
+	}
+	s.mu.Unlock()
+}
@@ -0,0 +1,96 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSubscribeLatestWins(t *testing.T) {
+	s := NewScheduler(NewClient())
+	latest, stopLatest := s.Subscribe(WithLatestWins())
+	defer stopLatest()
+	queued, stopQueued := s.Subscribe()
+	defer stopQueued()
+
+	var published []*Snapshot
+	for i := range subscriberBuffer + 4 {
+		snap := &Snapshot{Country: "US", Latitude: float64(i)}
+		published = append(published, snap)
+		s.publish(snap)
+	}
+
+	if got := <-latest; got != published[len(published)-1] {
+		t.Errorf("latest-wins subscriber got snapshot %v, want the last, %v", got.Latitude, len(published)-1)
+	}
+	select {
+	case got := <-latest:
+		t.Errorf("latest-wins subscriber got a second snapshot, %v", got.Latitude)
+	default:
+	}
+
+	for i := range subscriberBuffer {
+		if got := <-queued; got != published[i] {
+			t.Fatalf("queued subscriber got snapshot %v, want %d", got.Latitude, i)
+		}
+	}
+	select {
+	case got := <-queued:
+		t.Errorf("queued subscriber got snapshot %v beyond its buffer", got.Latitude)
+	default:
+	}
+}
+
+func TestSchedulerSnapshotTimezone(t *testing.T) {
+	// New York's weather is served; Mexico City's fails
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Query().Get("latitude"), "40.71") {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, currentBody)
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithLogger(slog.New(slog.DiscardHandler)))
+	s := NewScheduler(c, WithSchedulerRegistry(NewRegistry()))
+	snapshots, stop := s.Subscribe()
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	for _, country := range []string{"US", "MX"} {
+		if err := s.TrackCountry(country); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]string{"US": "America/New_York", "MX": "America/Mexico_City"}
+	for len(want) > 0 {
+		select {
+		case snap := <-snapshots:
+			tz, ok := want[snap.Country]
+			if !ok {
+				continue
+			}
+			delete(want, snap.Country)
+			if snap.Timezone != tz || snap.FetchedAt.Location().String() != tz {
+				t.Errorf("%s snapshot in %q, fetched at %v, want %s", snap.Country, snap.Timezone, snap.FetchedAt, tz)
+			}
+		case <-ctx.Done():
+			t.Fatalf("no snapshots for %v", want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package feeds
+
+// MaxWeatherSeverity is the highest rank returned by WeatherSeverity
+const MaxWeatherSeverity = 12
+
+// weatherCodeSeverity ranks WMO weather codes by how disruptive they are:
+//
+//	 0  clear or mainly clear (0, 1)
+//	 1  partly cloudy or overcast (2, 3)
+//	 2  fog (45, 48)
+//	 3  drizzle (51, 53, 55)
+//	 4  slight rain or rain showers (61, 80)
+//	 5  slight snow, snow grains or snow showers (71, 77, 85)
+//	 6  moderate rain or rain showers (63, 81)
+//	 7  moderate snow or heavy snow showers (73, 86)
+//	 8  heavy or violent rain, freezing drizzle or rain (65, 82, 56, 57, 66, 67)
+//	 9  heavy snow (75)
+//	10  thunderstorm (95)
+//	11  thunderstorm with slight hail (96)
+//	12  thunderstorm with heavy hail (99)
+var weatherCodeSeverity = map[int]int{
+	0: 0, 1: 0,
+	2: 1, 3: 1,
+	45: 2, 48: 2,
+	51: 3, 53: 3, 55: 3,
+	61: 4, 80: 4,
+	71: 5, 77: 5, 85: 5,
+	63: 6, 81: 6,
+	73: 7, 86: 7,
+	65: 8, 82: 8, 56: 8, 57: 8, 66: 8, 67: 8,
+	75: 9,
+	95: 10,
+	96: 11,
+	99: 12,
+}
+
+// WeatherSeverity returns the 0–MaxWeatherSeverity rank of a WMO weather code
+// (see weatherCodeSeverity for the scheme). Unknown codes rank 0.
+func WeatherSeverity(code int) int {
+	return weatherCodeSeverity[code]
+}
+
+// Severity returns the severity rank of the reading's weather code
+func (w WeatherData) Severity() int {
+	return WeatherSeverity(w.WeatherCode)
+}
+
+// Condition severities: a coarse classification of a reading for display,
+// such as color-coding, alongside the finer WeatherSeverity rank
+const (
+	ConditionSeverityNone     = "none"
+	ConditionSeverityMinor    = "minor"
+	ConditionSeverityModerate = "moderate"
+	ConditionSeveritySevere   = "severe"
+)
+
+// Precipitation rates, in mm per hour, at which rain counts as moderate and
+// heavy (the American Meteorological Society's thresholds)
+const (
+	moderatePrecipitationMM = 2.5
+	heavyPrecipitationMM    = 7.6
+)
+
+// conditionSeverityRank orders the condition severities
+var conditionSeverityRank = map[string]int{
+	ConditionSeverityNone:     0,
+	ConditionSeverityMinor:    1,
+	ConditionSeverityModerate: 2,
+	ConditionSeveritySevere:   3,
+}
+
+// weatherCodeConditionSeverity classifies WMO weather codes; codes not listed,
+// clear to overcast skies among them, are none
+var weatherCodeConditionSeverity = map[int]string{
+	45: ConditionSeverityMinor, 48: ConditionSeverityMinor, // fog
+	51: ConditionSeverityMinor, 53: ConditionSeverityMinor, // light and moderate drizzle
+	61: ConditionSeverityMinor, 80: ConditionSeverityMinor, // slight rain or showers
+	71: ConditionSeverityMinor, 77: ConditionSeverityMinor, 85: ConditionSeverityMinor, // slight snow, grains or showers
+	55: ConditionSeverityModerate, 56: ConditionSeverityModerate, 57: ConditionSeverityModerate, // dense or freezing drizzle
+	63: ConditionSeverityModerate, 81: ConditionSeverityModerate, // moderate rain or showers
+	66: ConditionSeverityModerate,                            // light freezing rain
+	73: ConditionSeverityModerate,                            // moderate snow
+	65: ConditionSeveritySevere, 82: ConditionSeveritySevere, // heavy rain, violent showers
+	67: ConditionSeveritySevere,                              // heavy freezing rain
+	75: ConditionSeveritySevere, 86: ConditionSeveritySevere, // heavy snow or snow showers
+	95: ConditionSeveritySevere, 96: ConditionSeveritySevere, 99: ConditionSeveritySevere, // thunderstorms
+}
+
+// ClassifyConditions returns the condition severity of a WMO weather code
+// and the precipitation reported with it, taken as an hourly rate: the more
+// severe of the code's class and the rate's (any precipitation is minor,
+// 2.5 mm moderate and 7.6 mm severe)
+func ClassifyConditions(code int, precipitationMM float64) string {
+	severity := weatherCodeConditionSeverity[code]
+	if severity == "" {
+		severity = ConditionSeverityNone
+	}
+	byRate := ConditionSeverityNone
+	switch {
+	case precipitationMM >= heavyPrecipitationMM:
+		byRate = ConditionSeveritySevere
+	case precipitationMM >= moderatePrecipitationMM:
+		byRate = ConditionSeverityModerate
+	case precipitationMM > 0:
+		byRate = ConditionSeverityMinor
+	}
+	if conditionSeverityRank[byRate] > conditionSeverityRank[severity] {
+		return byRate
+	}
+	return severity
+}
+
+// IsPrecipitationCode reports whether a WMO weather code describes falling
+// precipitation: drizzle, rain, snow, showers or thunderstorms
+func IsPrecipitationCode(code int) bool {
+	return code >= 51 && code <= 67 || code >= 71 && code <= 77 || code >= 80 && code <= 86 || code >= 95 && code <= 99
+}
+
+// IsPrecipitation reports whether precipitation is falling or was measured
+func (w WeatherData) IsPrecipitation() bool {
+	return IsPrecipitationCode(w.WeatherCode) || w.PrecipitationMM > 0
+}
+
+// IsSevere reports whether the reading's conditions are severe
+func (w WeatherData) IsSevere() bool {
+	return ClassifyConditions(w.WeatherCode, w.PrecipitationMM) == ConditionSeveritySevere
+}
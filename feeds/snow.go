@@ -0,0 +1,219 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SkiArea is a ski resort whose snow report can be fetched by ID
+type SkiArea struct {
+	ID      string // short slug, e.g. "whistler"
+	Name    string
+	Country string
+	Region  string // state or province code
+	Coordinates
+}
+
+var (
+	skiAreasMu sync.RWMutex
+
+	// skiAreas holds major North American ski areas
+	skiAreas = map[string]SkiArea{
+		"whistler":     {ID: "whistler", Name: "Whistler Blackcomb", Country: "CA", Region: "BC", Coordinates: Coordinates{Lat: 50.1163, Lon: -122.9574}},
+		"revelstoke":   {ID: "revelstoke", Name: "Revelstoke Mountain Resort", Country: "CA", Region: "BC", Coordinates: Coordinates{Lat: 50.9584, Lon: -118.1631}},
+		"sunshine":     {ID: "sunshine", Name: "Banff Sunshine", Country: "CA", Region: "AB", Coordinates: Coordinates{Lat: 51.0785, Lon: -115.7731}},
+		"lake-louise":  {ID: "lake-louise", Name: "Lake Louise", Country: "CA", Region: "AB", Coordinates: Coordinates{Lat: 51.4419, Lon: -116.1622}},
+		"tremblant":    {ID: "tremblant", Name: "Mont Tremblant", Country: "CA", Region: "QC", Coordinates: Coordinates{Lat: 46.2096, Lon: -74.5855}},
+		"vail":         {ID: "vail", Name: "Vail", Country: "US", Region: "CO", Coordinates: Coordinates{Lat: 39.6061, Lon: -106.3550}},
+		"breckenridge": {ID: "breckenridge", Name: "Breckenridge", Country: "US", Region: "CO", Coordinates: Coordinates{Lat: 39.4817, Lon: -106.0384}},
+		"aspen":        {ID: "aspen", Name: "Aspen Snowmass", Country: "US", Region: "CO", Coordinates: Coordinates{Lat: 39.2084, Lon: -106.9490}},
+		"telluride":    {ID: "telluride", Name: "Telluride", Country: "US", Region: "CO", Coordinates: Coordinates{Lat: 37.9375, Lon: -107.8123}},
+		"park-city":    {ID: "park-city", Name: "Park City", Country: "US", Region: "UT", Coordinates: Coordinates{Lat: 40.6514, Lon: -111.5080}},
+		"snowbird":     {ID: "snowbird", Name: "Snowbird", Country: "US", Region: "UT", Coordinates: Coordinates{Lat: 40.5810, Lon: -111.6570}},
+		"alta":         {ID: "alta", Name: "Alta", Country: "US", Region: "UT", Coordinates: Coordinates{Lat: 40.5884, Lon: -111.6386}},
+		"jackson-hole": {ID: "jackson-hole", Name: "Jackson Hole", Country: "US", Region: "WY", Coordinates: Coordinates{Lat: 43.5875, Lon: -110.8279}},
+		"big-sky":      {ID: "big-sky", Name: "Big Sky", Country: "US", Region: "MT", Coordinates: Coordinates{Lat: 45.2858, Lon: -111.4010}},
+		"sun-valley":   {ID: "sun-valley", Name: "Sun Valley", Country: "US", Region: "ID", Coordinates: Coordinates{Lat: 43.6971, Lon: -114.3517}},
+		"taos":         {ID: "taos", Name: "Taos Ski Valley", Country: "US", Region: "NM", Coordinates: Coordinates{Lat: 36.5960, Lon: -105.4545}},
+		"mammoth":      {ID: "mammoth", Name: "Mammoth Mountain", Country: "US", Region: "CA", Coordinates: Coordinates{Lat: 37.6308, Lon: -119.0326}},
+		"palisades":    {ID: "palisades", Name: "Palisades Tahoe", Country: "US", Region: "CA", Coordinates: Coordinates{Lat: 39.1970, Lon: -120.2357}},
+		"heavenly":     {ID: "heavenly", Name: "Heavenly", Country: "US", Region: "CA", Coordinates: Coordinates{Lat: 38.9353, Lon: -119.9400}},
+		"mt-bachelor":  {ID: "mt-bachelor", Name: "Mt. Bachelor", Country: "US", Region: "OR", Coordinates: Coordinates{Lat: 43.9793, Lon: -121.6886}},
+		"crystal":      {ID: "crystal", Name: "Crystal Mountain", Country: "US", Region: "WA", Coordinates: Coordinates{Lat: 46.9282, Lon: -121.5045}},
+		"killington":   {ID: "killington", Name: "Killington", Country: "US", Region: "VT", Coordinates: Coordinates{Lat: 43.6045, Lon: -72.8201}},
+		"stowe":        {ID: "stowe", Name: "Stowe", Country: "US", Region: "VT", Coordinates: Coordinates{Lat: 44.5303, Lon: -72.7814}},
+	}
+)
+
+// RegisterSkiArea adds or replaces a ski area used by FetchSnowReport
+func RegisterSkiArea(area SkiArea) error {
+	if area.ID == "" {
+		return errors.New("ski area ID is empty")
+	}
+	if err := area.Validate(); err != nil {
+		return err
+	}
+	area.ID = strings.ToLower(area.ID)
+	area.Country = strings.ToUpper(area.Country)
+
+	skiAreasMu.Lock()
+	defer skiAreasMu.Unlock()
+	skiAreas[area.ID] = area
+	return nil
+}
+
+// SkiAreas returns the registered ski areas sorted by name
+func SkiAreas() []SkiArea {
+	skiAreasMu.RLock()
+	defer skiAreasMu.RUnlock()
+	areas := make([]SkiArea, 0, len(skiAreas))
+	for _, a := range skiAreas {
+		areas = append(areas, a)
+	}
+	slices.SortFunc(areas, func(a, b SkiArea) int { return strings.Compare(a.Name, b.Name) })
+	return areas
+}
+
+// SnowReport summarizes recent and forecast snow at a location. Snowfall is
+// fresh snow in cm; depth is the settled snowpack.
+type SnowReport struct {
+	Area              string    `json:"area,omitempty"` // ski area name when fetched by ID
+	SnowDepthCm       float64   `json:"snowDepthCm"`
+	FreezingLevelM    float64   `json:"freezingLevelM"`
+	Snowfall24hCm     float64   `json:"snowfall24hCm"`
+	Snowfall7dCm      float64   `json:"snowfall7dCm"`
+	ForecastSnow72hCm float64   `json:"forecastSnow72hCm"`
+	Time              time.Time `json:"time"` // hour the depth and freezing level refer to
+	Latitude          float64   `json:"latitude,omitempty"`
+	Longitude         float64   `json:"longitude,omitempty"`
+	Elevation         float64   `json:"elevation,omitempty"` // metres, of the model grid cell
+	Timezone          string    `json:"timezone,omitempty"`
+}
+
+// OpenMeteoSnowResponse represents an hourly snow response from Open-Meteo
+type OpenMeteoSnowResponse struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	Elevation        float64 `json:"elevation"`
+	Timezone         string  `json:"timezone"`
+	UTCOffsetSeconds int     `json:"utc_offset_seconds"`
+	Hourly           struct {
+		Time          []Timestamp `json:"time"`
+		Snowfall      []*float64  `json:"snowfall"`              // cm
+		SnowDepth     []*float64  `json:"snow_depth"`            // m
+		FreezingLevel []*float64  `json:"freezing_level_height"` // m
+	} `json:"hourly"`
+}
+
+// FetchSnowReport fetches a ski area's snow report using the default Client
+func FetchSnowReport(areaID string) (*SnowReport, error) {
+	return defaultClient.FetchSnowReport(areaID)
+}
+
+// FetchSnowReport fetches the snow report for a registered ski area (see SkiAreas)
+func (c *Client) FetchSnowReport(areaID string) (*SnowReport, error) {
+	skiAreasMu.RLock()
+	area, ok := skiAreas[strings.ToLower(areaID)]
+	skiAreasMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: no ski area %q", ErrUnknownLocation, areaID)
+	}
+
+	report, err := c.fetchSnowReport(context.Background(), area.Coordinates)
+	if err != nil {
+		return nil, err
+	}
+	report.Area = area.Name
+	return report, nil
+}
+
+// FetchSnowReportByCoords fetches a snow report for coordinates using the default Client
+func FetchSnowReportByCoords(lat, lon float64) (*SnowReport, error) {
+	return defaultClient.FetchSnowReportByCoords(lat, lon)
+}
+
+// FetchSnowReportByCoords fetches current snow depth and freezing level,
+// snowfall over the past 24 hours and 7 days, and forecast snowfall for the
+// next 72 hours at the given coordinates
+func (c *Client) FetchSnowReportByCoords(lat, lon float64) (*SnowReport, error) {
+	coords := Coordinates{Lat: lat, Lon: lon}
+	if err := coords.Validate(); err != nil {
+		return nil, err
+	}
+	return c.fetchSnowReport(context.Background(), coords)
+}
+
+// fetchSnowReport requests a week of past and three days of forecast hourly
+// snow data at coords and summarizes it around the current hour
+func (c *Client) fetchSnowReport(ctx context.Context, coords Coordinates) (*SnowReport, error) {
+	q := coords.query()
+	q.Set("hourly", "snowfall,snow_depth,freezing_level_height")
+	q.Set("past_days", "7")
+	q.Set("forecast_days", "3")
+	q.Set("timezone", "auto")
+
+	var apiResp OpenMeteoSnowResponse
+	if err := c.getOpenMeteo(ctx, c.forecastBaseURL+"/v1/forecast", q, &apiResp); err != nil {
+		return nil, err
+	}
+	h := apiResp.Hourly
+	n := len(h.Time)
+	if n == 0 || len(h.Snowfall) != n || len(h.SnowDepth) != n || len(h.FreezingLevel) != n {
+		return nil, withKind(ErrDecode, errors.New("hourly snow response has missing or mismatched arrays"))
+	}
+
+	loc := apiLocation(apiResp.Timezone, apiResp.UTCOffsetSeconds)
+	now := time.Now()
+	report := &SnowReport{
+		Latitude:  apiResp.Latitude,
+		Longitude: apiResp.Longitude,
+		Elevation: apiResp.Elevation,
+		Timezone:  apiResp.Timezone,
+	}
+	current := -1
+	for i := range n {
+		t, err := h.Time[i].In(loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse hourly time: %w", err)
+		}
+		snowfall := valueOrZero(h.Snowfall[i])
+		switch age := now.Sub(t); {
+		case age < 0:
+			if age >= -72*time.Hour {
+				report.ForecastSnow72hCm += snowfall
+			}
+		default:
+			current = i
+			report.Time = t
+			if age < 24*time.Hour {
+				report.Snowfall24hCm += snowfall
+			}
+			if age < 7*24*time.Hour {
+				report.Snowfall7dCm += snowfall
+			}
+		}
+	}
+	if current < 0 {
+		return nil, withKind(ErrDecode, errors.New("hourly snow response has no past hours"))
+	}
+
+	report.SnowDepthCm = roundTo(valueOrZero(h.SnowDepth[current])*100, 1)
+	report.FreezingLevelM = valueOrZero(h.FreezingLevel[current])
+	report.Snowfall24hCm = roundTo(report.Snowfall24hCm, 1)
+	report.Snowfall7dCm = roundTo(report.Snowfall7dCm, 1)
+	report.ForecastSnow72hCm = roundTo(report.ForecastSnow72hCm, 1)
+	return report, nil
+}
+
+// valueOrZero returns *v, or 0 for a missing value
+func valueOrZero(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
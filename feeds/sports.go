@@ -0,0 +1,226 @@
+package feeds
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultESPNBaseURL is the ESPN site API host, whose scoreboards need no key
+const defaultESPNBaseURL = "https://site.api.espn.com"
+
+// WithESPNBaseURL points sports scoreboard requests at another host
+func WithESPNBaseURL(base string) Option {
+	return func(c *Client) {
+		c.espnBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// Leagues
+const (
+	LeagueNFL    = "nfl"
+	LeagueNBA    = "nba"
+	LeagueMLB    = "mlb"
+	LeagueNHL    = "nhl"
+	LeagueMLS    = "mls"
+	LeagueLigaMX = "ligamx"
+)
+
+// espnLeagues maps leagues to ESPN sport/league scoreboard paths
+var espnLeagues = map[string]string{
+	LeagueNFL:    "football/nfl",
+	LeagueNBA:    "basketball/nba",
+	LeagueMLB:    "baseball/mlb",
+	LeagueNHL:    "hockey/nhl",
+	LeagueMLS:    "soccer/usa.1",
+	LeagueLigaMX: "soccer/mex.1",
+}
+
+// Game states
+const (
+	GameScheduled  = "scheduled"
+	GameInProgress = "in_progress"
+	GameFinal      = "final"
+	GamePostponed  = "postponed"
+	GameCanceled   = "canceled"
+)
+
+// Game is a scheduled, live or finished game
+type Game struct {
+	ID     string    `json:"id"`
+	League string    `json:"league"`
+	Name   string    `json:"name"`   // e.g. "Toronto Maple Leafs at Montreal Canadiens"
+	State  string    `json:"state"`  // one of the Game state constants
+	Detail string    `json:"detail"` // e.g. "Final/OT", "3rd - 12:41", "7:00 PM EDT"
+	Start  time.Time `json:"start"`  // in the query's Location
+	Venue  string    `json:"venue,omitempty"`
+	Home   GameTeam  `json:"home"`
+	Away   GameTeam  `json:"away"`
+}
+
+// GameTeam is one side of a game; Score is 0 before it starts
+type GameTeam struct {
+	ID           string `json:"id"`
+	Abbreviation string `json:"abbreviation"` // e.g. "TOR"
+	Name         string `json:"name"`
+	Score        int    `json:"score"`
+	Winner       bool   `json:"winner"`
+}
+
+// GameQuery selects games from a league's scoreboard
+type GameQuery struct {
+	Team     string         // abbreviation or part of the name, case-insensitive; empty for all teams
+	From     time.Time      // first day; zero for today
+	Days     int            // days from From; default 1
+	Location *time.Location // zone for days and start times; nil for time.Local
+}
+
+// ESPNScoreboardResponse represents an ESPN site API scoreboard response
+type ESPNScoreboardResponse struct {
+	Events []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Date   string `json:"date"` // e.g. "2024-10-13T17:00Z"
+		Status struct {
+			Type struct {
+				Name        string `json:"name"`  // e.g. "STATUS_FINAL", "STATUS_POSTPONED"
+				State       string `json:"state"` // "pre", "in" or "post"
+				ShortDetail string `json:"shortDetail"`
+			} `json:"type"`
+		} `json:"status"`
+		Competitions []struct {
+			Venue struct {
+				FullName string `json:"fullName"`
+			} `json:"venue"`
+			Competitors []struct {
+				HomeAway string `json:"homeAway"`
+				Score    string `json:"score"`
+				Winner   bool   `json:"winner"`
+				Team     struct {
+					ID           string `json:"id"`
+					Abbreviation string `json:"abbreviation"`
+					DisplayName  string `json:"displayName"`
+				} `json:"team"`
+			} `json:"competitors"`
+		} `json:"competitions"`
+	} `json:"events"`
+}
+
+// FetchGames fetches a league's games using the default Client
+func FetchGames(league string, query GameQuery) ([]Game, error) {
+	return defaultClient.FetchGames(league, query)
+}
+
+// FetchGames returns a league's games on the queried days in start order,
+// with scores for games in progress or finished. Leagues are the League
+// constants; others return ErrUnknownLeague.
+func (c *Client) FetchGames(league string, query GameQuery) ([]Game, error) {
+	return c.fetchGames(context.Background(), strings.ToLower(league), query)
+}
+
+// fetchGames requests the scoreboard for the query's date range
+func (c *Client) fetchGames(ctx context.Context, league string, query GameQuery) ([]Game, error) {
+	path, ok := espnLeagues[league]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLeague, league)
+	}
+	loc := cmp.Or(query.Location, time.Local)
+	from := query.From
+	if from.IsZero() {
+		from = time.Now()
+	}
+	from = from.In(loc)
+	days := max(query.Days, 1)
+	first := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	last := first.AddDate(0, 0, days-1)
+
+	// ESPN groups games by US Eastern day, so pad the range by a day on each
+	// side and keep the games that start on the caller's days
+	q := url.Values{}
+	q.Set("dates", first.AddDate(0, 0, -1).Format("20060102")+"-"+last.AddDate(0, 0, 1).Format("20060102"))
+	q.Set("limit", "500")
+
+	var apiResp ESPNScoreboardResponse
+	if err := c.getJSON(ctx, c.espnBaseURL+"/apis/site/v2/sports/"+path+"/scoreboard", q, &apiResp); err != nil {
+		return nil, err
+	}
+
+	team := strings.ToLower(strings.TrimSpace(query.Team))
+	end := last.AddDate(0, 0, 1)
+	var games []Game
+	for _, ev := range apiResp.Events {
+		start, err := parseESPNTime(ev.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse game time: %w", err)
+		}
+		start = start.In(loc)
+		if start.Before(first) || !start.Before(end) {
+			continue
+		}
+		g := Game{
+			ID:     ev.ID,
+			League: league,
+			Name:   ev.Name,
+			State:  espnGameState(ev.Status.Type.State, ev.Status.Type.Name),
+			Detail: ev.Status.Type.ShortDetail,
+			Start:  start,
+		}
+		if len(ev.Competitions) > 0 {
+			comp := ev.Competitions[0]
+			g.Venue = comp.Venue.FullName
+			for _, t := range comp.Competitors {
+				side := GameTeam{
+					ID:           t.Team.ID,
+					Abbreviation: t.Team.Abbreviation,
+					Name:         t.Team.DisplayName,
+					Winner:       t.Winner,
+				}
+				side.Score, _ = strconv.Atoi(t.Score)
+				if t.HomeAway == "home" {
+					g.Home = side
+				} else {
+					g.Away = side
+				}
+			}
+		}
+		if team != "" && !g.Home.matches(team) && !g.Away.matches(team) {
+			continue
+		}
+		games = append(games, g)
+	}
+	slices.SortStableFunc(games, func(a, b Game) int { return a.Start.Compare(b.Start) })
+	return games, nil
+}
+
+// matches reports whether the team's abbreviation is team or its name contains it
+func (t GameTeam) matches(team string) bool {
+	return strings.ToLower(t.Abbreviation) == team || strings.Contains(strings.ToLower(t.Name), team)
+}
+
+// espnGameState maps an ESPN status to a Game state constant
+func espnGameState(state, name string) string {
+	switch {
+	case strings.Contains(name, "POSTPONED"):
+		return GamePostponed
+	case strings.Contains(name, "CANCELED"):
+		return GameCanceled
+	case state == "in":
+		return GameInProgress
+	case state == "post":
+		return GameFinal
+	}
+	return GameScheduled
+}
+
+// parseESPNTime parses ESPN event dates, which usually omit seconds
+func parseESPNTime(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02T15:04Z07:00", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,69 @@
+package feeds
+
+import (
+	"maps"
+	"time"
+)
+
+// ClientStatus is a snapshot of a Client's upstream health for operators
+type ClientStatus struct {
+	// Degraded is set when some upstream circuit is not closed, or when a
+	// stale cached response was served after the last upstream success
+	Degraded    bool                    `json:"degraded"`
+	Circuits    map[string]CircuitState `json:"circuits"` // by upstream host
+	LastError   string                  `json:"lastError,omitempty"`
+	LastErrorAt time.Time               `json:"lastErrorAt,omitzero"`
+	LastSuccess time.Time               `json:"lastSuccess,omitzero"`
+	LastStaleAt time.Time               `json:"lastStaleAt,omitzero"` // when a stale cached response was last served
+	CacheSize   int                     `json:"cacheSize"`
+}
+
+// Status returns the default Client's status
+func Status() ClientStatus {
+	return defaultClient.Status()
+}
+
+// Status reports the circuit state of every upstream contacted so far, the
+// most recent upstream error and success, when stale cached data was last
+// served, and the number of cached responses
+func (c *Client) Status() ClientStatus {
+	s := ClientStatus{Circuits: make(map[string]CircuitState), CacheSize: c.cache.Len()}
+
+	c.circuitMu.Lock()
+	breakers := maps.Clone(c.circuits)
+	c.circuitMu.Unlock()
+	for host, b := range breakers {
+		state := b.current()
+		s.Circuits[host] = state
+		s.Degraded = s.Degraded || state != CircuitClosed
+	}
+
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+	if c.lastErr != nil {
+		s.LastError = c.lastErr.Error()
+	}
+	s.LastErrorAt = c.lastErrAt
+	s.LastSuccess = c.lastSuccess
+	s.LastStaleAt = c.lastStaleAt
+	s.Degraded = s.Degraded || c.lastStaleAt.After(c.lastSuccess)
+	return s
+}
+
+// recordOutcome remembers the latest upstream success or error for Status
+func (c *Client) recordOutcome(err error) {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+	if err != nil {
+		c.lastErr, c.lastErrAt = err, time.Now()
+	} else {
+		c.lastSuccess = time.Now()
+	}
+}
+
+// recordStale remembers that a stale cached response was served for Status
+func (c *Client) recordStale() {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+	c.lastStaleAt = time.Now()
+}
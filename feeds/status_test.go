@@ -0,0 +1,52 @@
+package feeds
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStatusDegradedWhileServingStale(t *testing.T) {
+	var failing atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, currentBody)
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithCacheTTL(time.Nanosecond), WithStaleOnError(),
+		WithRetry(RetryPolicy{MaxAttempts: 1}), WithCircuitBreaker(0, 0), WithLogger(slog.New(slog.DiscardHandler)))
+
+	if _, err := c.FetchWeather("US"); err != nil {
+		t.Fatal(err)
+	}
+	if s := c.Status(); s.Degraded || !s.LastStaleAt.IsZero() {
+		t.Errorf("after a success: degraded %v, last stale at %v", s.Degraded, s.LastStaleAt)
+	}
+
+	failing.Store(true)
+	if _, err := c.FetchWeather("US"); err != nil {
+		t.Fatalf("stale response not served: %v", err)
+	}
+	s := c.Status()
+	if !s.Degraded || s.LastStaleAt.IsZero() || s.LastError == "" {
+		t.Errorf("serving stale: degraded %v, last stale at %v, last error %q", s.Degraded, s.LastStaleAt, s.LastError)
+	}
+	if len(s.Circuits) > 0 {
+		t.Errorf("circuits %v with the breaker disabled", s.Circuits)
+	}
+
+	failing.Store(false)
+	if _, err := c.FetchWeather("US"); err != nil {
+		t.Fatal(err)
+	}
+	if s := c.Status(); s.Degraded || s.LastStaleAt.IsZero() {
+		t.Errorf("after recovering: degraded %v, last stale at %v", s.Degraded, s.LastStaleAt)
+	}
+}
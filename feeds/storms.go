@@ -0,0 +1,193 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultNHCBaseURL is the NOAA National Hurricane Center host
+const defaultNHCBaseURL = "https://www.nhc.noaa.gov"
+
+// knotsToKmh converts knots, in which NHC reports wind speeds, to km/h
+const knotsToKmh = 1.852
+
+// WithNHCBaseURL points tropical storm requests at another host
+func WithNHCBaseURL(base string) Option {
+	return func(c *Client) {
+		c.nhcBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// Storm is an active tropical cyclone tracked by the National Hurricane Center
+type Storm struct {
+	ID             string         `json:"id"` // e.g. "al052023"
+	Name           string         `json:"name"`
+	Basin          string         `json:"basin"`          // Atlantic, Eastern Pacific or Central Pacific
+	Classification string         `json:"classification"` // e.g. "Hurricane", "Tropical Storm"
+	Category       int            `json:"category"`       // Saffir-Simpson category; 0 below hurricane strength
+	MaxWindKmh     float64        `json:"maxWindKmh"`
+	PressureMb     float64        `json:"pressureMb,omitempty"`
+	Latitude       float64        `json:"latitude"`
+	Longitude      float64        `json:"longitude"`
+	MovementDeg    float64        `json:"movementDeg"` // direction of travel, degrees clockwise from north
+	MovementKmh    float64        `json:"movementKmh"`
+	UpdatedAt      time.Time      `json:"updatedAt,omitzero"`
+	Advisory       *StormAdvisory `json:"advisory,omitempty"`
+	Cone           *ForecastCone  `json:"cone,omitempty"`
+}
+
+// StormAdvisory is the latest NHC public advisory for a storm
+type StormAdvisory struct {
+	Number string    `json:"number"`
+	Issued time.Time `json:"issued,omitzero"`
+	URL    string    `json:"url"`
+}
+
+// ForecastCone summarizes the NHC cone of uncertainty for a storm's track. The
+// cone geometry itself is published as KMZ and shapefile downloads.
+type ForecastCone struct {
+	Advisory     string `json:"advisory"`
+	KMZURL       string `json:"kmzUrl,omitempty"`
+	ShapefileURL string `json:"shapefileUrl,omitempty"`
+}
+
+// nhcNumber is a number that NHC sends either bare or as a string
+type nhcNumber float64
+
+// UnmarshalJSON accepts 12.5, "12.5" or an empty string
+func (n *nhcNumber) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	v, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return err
+	}
+	*n = nhcNumber(v)
+	return nil
+}
+
+// NHCCurrentStormsResponse represents the NHC CurrentStorms.json document
+type NHCCurrentStormsResponse struct {
+	ActiveStorms []struct {
+		ID               string    `json:"id"`
+		Name             string    `json:"name"`
+		Classification   string    `json:"classification"`
+		Intensity        nhcNumber `json:"intensity"` // maximum sustained wind, knots
+		Pressure         nhcNumber `json:"pressure"`  // minimum central pressure, mb
+		LatitudeNumeric  float64   `json:"latitudeNumeric"`
+		LongitudeNumeric float64   `json:"longitudeNumeric"`
+		MovementDir      nhcNumber `json:"movementDir"`
+		MovementSpeed    nhcNumber `json:"movementSpeed"` // mph
+		LastUpdate       string    `json:"lastUpdate"`
+		PublicAdvisory   *struct {
+			AdvNum   string `json:"advNum"`
+			Issuance string `json:"issuance"`
+			URL      string `json:"url"`
+		} `json:"publicAdvisory"`
+		TrackCone *struct {
+			AdvNum  string `json:"advNum"`
+			KMZFile string `json:"kmzFile"`
+			ZipFile string `json:"zipFile"`
+		} `json:"trackCone"`
+	} `json:"activeStorms"`
+}
+
+// stormClassifications names NHC classification codes
+var stormClassifications = map[string]string{
+	"TD":  "Tropical Depression",
+	"STD": "Subtropical Depression",
+	"TS":  "Tropical Storm",
+	"STS": "Subtropical Storm",
+	"HU":  "Hurricane",
+	"MH":  "Major Hurricane",
+	"PTC": "Potential Tropical Cyclone",
+	"PC":  "Post-Tropical Cyclone",
+}
+
+// stormBasins names the basin prefix of NHC storm IDs
+var stormBasins = map[string]string{
+	"al": "Atlantic",
+	"ep": "Eastern Pacific",
+	"cp": "Central Pacific",
+}
+
+// SaffirSimpsonCategory returns the hurricane category (1–5) for a maximum
+// sustained wind in knots, or 0 below hurricane strength
+func SaffirSimpsonCategory(windKt float64) int {
+	switch {
+	case windKt >= 137:
+		return 5
+	case windKt >= 113:
+		return 4
+	case windKt >= 96:
+		return 3
+	case windKt >= 83:
+		return 2
+	case windKt >= 64:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// FetchStorms fetches active tropical cyclones using the default Client
+func FetchStorms() ([]Storm, error) {
+	return defaultClient.FetchStorms()
+}
+
+// FetchStorms fetches the tropical cyclones the National Hurricane Center is
+// currently tracking in the Atlantic and eastern and central Pacific. Outside
+// hurricane season the list is usually empty.
+func (c *Client) FetchStorms() ([]Storm, error) {
+	return c.fetchStorms(context.Background())
+}
+
+// fetchStorms reads the NHC active storms document
+func (c *Client) fetchStorms(ctx context.Context) ([]Storm, error) {
+	var apiResp NHCCurrentStormsResponse
+	if err := c.getJSON(ctx, c.nhcBaseURL+"/CurrentStorms.json", nil, &apiResp); err != nil {
+		return nil, err
+	}
+
+	storms := make([]Storm, 0, len(apiResp.ActiveStorms))
+	for _, s := range apiResp.ActiveStorms {
+		windKt := float64(s.Intensity)
+		storm := Storm{
+			ID:             s.ID,
+			Name:           s.Name,
+			Classification: stormClassifications[s.Classification],
+			Category:       SaffirSimpsonCategory(windKt),
+			MaxWindKmh:     roundTo(windKt*knotsToKmh, 1),
+			PressureMb:     float64(s.Pressure),
+			Latitude:       s.LatitudeNumeric,
+			Longitude:      s.LongitudeNumeric,
+			MovementDeg:    float64(s.MovementDir),
+			MovementKmh:    roundTo(MphToKmh(float64(s.MovementSpeed)), 1),
+		}
+		if storm.Classification == "" {
+			storm.Classification = s.Classification
+		}
+		if len(s.ID) >= 2 {
+			storm.Basin = stormBasins[strings.ToLower(s.ID[:2])]
+		}
+		if t, err := time.Parse(time.RFC3339, s.LastUpdate); err == nil {
+			storm.UpdatedAt = t
+		}
+		if adv := s.PublicAdvisory; adv != nil {
+			storm.Advisory = &StormAdvisory{Number: adv.AdvNum, URL: adv.URL}
+			if t, err := time.Parse(time.RFC3339, adv.Issuance); err == nil {
+				storm.Advisory.Issued = t
+			}
+		}
+		if cone := s.TrackCone; cone != nil {
+			storm.Cone = &ForecastCone{Advisory: cone.AdvNum, KMZURL: cone.KMZFile, ShapefileURL: cone.ZipFile}
+		}
+		storms = append(storms, storm)
+	}
+	return storms, nil
+}
@@ -0,0 +1,139 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// goldenHourLength approximates how long the light stays golden after sunrise and before sunset
+const goldenHourLength = time.Hour
+
+// TimeWindow represents a span of time
+type TimeWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// SunTimes holds a location's sunrise, sunset and daylight for one day
+type SunTimes struct {
+	Date            time.Time `json:"date"`
+	Sunrise         time.Time `json:"sunrise"`
+	Sunset          time.Time `json:"sunset"`
+	DaylightSeconds float64   `json:"daylightSeconds"`
+	Latitude        float64   `json:"latitude"`
+	Longitude       float64   `json:"longitude"`
+	Timezone        string    `json:"timezone"`
+}
+
+// Daylight returns the daylight duration
+func (s SunTimes) Daylight() time.Duration {
+	return time.Duration(s.DaylightSeconds * float64(time.Second))
+}
+
+// IsDaylight reports whether t falls between sunrise and sunset
+func (s SunTimes) IsDaylight(t time.Time) bool {
+	return !t.Before(s.Sunrise) && t.Before(s.Sunset)
+}
+
+// OpenMeteoSunResponse represents a daily sunrise/sunset response from Open-Meteo
+type OpenMeteoSunResponse struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	Timezone         string  `json:"timezone"`
+	UTCOffsetSeconds int     `json:"utc_offset_seconds"`
+	Daily            struct {
+		Time             []Timestamp `json:"time"`
+		Sunrise          []Timestamp `json:"sunrise"`
+		Sunset           []Timestamp `json:"sunset"`
+		DaylightDuration []float64   `json:"daylight_duration"`
+	} `json:"daily"`
+}
+
+// FetchSunTimes fetches today's sun times using the default Client
+func FetchSunTimes(country string) (*SunTimes, error) {
+	return defaultClient.FetchSunTimes(country)
+}
+
+// FetchSunTimes fetches today's sunrise, sunset and daylight duration at a
+// country's coordinates, in the location's time zone
+func (c *Client) FetchSunTimes(country string) (*SunTimes, error) {
+	coords, _, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchSunTimes(context.Background(), coords)
+}
+
+// FetchSunTimesByCoords fetches today's sun times for coordinates using the default Client
+func FetchSunTimesByCoords(lat, lon float64) (*SunTimes, error) {
+	return defaultClient.FetchSunTimesByCoords(lat, lon)
+}
+
+// FetchSunTimesByCoords fetches today's sunrise, sunset and daylight duration at the given coordinates
+func (c *Client) FetchSunTimesByCoords(lat, lon float64) (*SunTimes, error) {
+	coords := Coordinates{Lat: lat, Lon: lon}
+	if err := coords.Validate(); err != nil {
+		return nil, err
+	}
+	return c.fetchSunTimes(context.Background(), coords)
+}
+
+// fetchSunTimes requests today's sun times at coords
+func (c *Client) fetchSunTimes(ctx context.Context, coords Coordinates) (*SunTimes, error) {
+	q := coords.query()
+	q.Set("daily", "sunrise,sunset,daylight_duration")
+	q.Set("forecast_days", "1")
+	q.Set("timezone", "auto")
+
+	var apiResp OpenMeteoSunResponse
+	if err := c.getOpenMeteo(ctx, c.forecastBaseURL+"/v1/forecast", q, &apiResp); err != nil {
+		return nil, err
+	}
+
+	d := apiResp.Daily
+	if len(d.Time) == 0 || len(d.Sunrise) == 0 || len(d.Sunset) == 0 || len(d.DaylightDuration) == 0 {
+		return nil, withKind(ErrDecode, errors.New("sun times response has no daily values"))
+	}
+
+	loc := apiLocation(apiResp.Timezone, apiResp.UTCOffsetSeconds)
+	date, err := d.Time[0].In(loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse date: %w", err)
+	}
+	sunrise, err := d.Sunrise[0].In(loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sunrise: %w", err)
+	}
+	sunset, err := d.Sunset[0].In(loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sunset: %w", err)
+	}
+
+	return &SunTimes{
+		Date:            truncateDay(date),
+		Sunrise:         sunrise,
+		Sunset:          sunset,
+		DaylightSeconds: d.DaylightDuration[0],
+		Latitude:        apiResp.Latitude,
+		Longitude:       apiResp.Longitude,
+		Timezone:        apiResp.Timezone,
+	}, nil
+}
+
+// GoldenHours derives the morning (just after sunrise) and evening (just before
+// sunset) golden-hour windows from a day's sun times. On days shorter than two
+// hours each window is shortened to half the daylight; if sunset is not after
+// sunrise (polar night or bad input) both windows are zero.
+func GoldenHours(sunrise, sunset time.Time) (morning, evening TimeWindow) {
+	daylight := sunset.Sub(sunrise)
+	if daylight <= 0 {
+		return TimeWindow{}, TimeWindow{}
+	}
+	length := min(goldenHourLength, daylight/2)
+
+	morning = TimeWindow{Start: sunrise, End: sunrise.Add(length)}
+	evening = TimeWindow{Start: sunset.Add(-length), End: sunset}
+	return morning, evening
+}
@@ -0,0 +1,97 @@
+package feeds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// placeZone is the time zone of a built-in country city or region capital
+type placeZone struct {
+	coords Coordinates
+	loc    *time.Location
+}
+
+// placeZones lists the zones of the built-in places, loaded from
+// data/countries.json and data/regions.json
+var placeZones = mustLoadPlaceZones()
+
+// loadPlaceZones reads the "tz" of every country and region entry,
+// rejecting names missing from the zone database
+func loadPlaceZones(countries, regions []byte) ([]placeZone, error) {
+	var entries []placeEntry
+	if err := json.Unmarshal(countries, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse country registry: %w", err)
+	}
+	var byCountry map[string][]placeEntry
+	if err := json.Unmarshal(regions, &byCountry); err != nil {
+		return nil, fmt.Errorf("failed to parse region registry: %w", err)
+	}
+	for _, e := range byCountry {
+		entries = append(entries, e...)
+	}
+
+	zones := make([]placeZone, 0, len(entries))
+	cache := make(map[string]*time.Location)
+	for _, e := range entries {
+		loc, ok := cache[e.TZ]
+		if !ok {
+			var err error
+			if e.TZ == "" {
+				err = errors.New("no time zone")
+			} else if loc, err = time.LoadLocation(e.TZ); err != nil {
+				err = fmt.Errorf("unknown time zone %q", e.TZ)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("place registry: %s (%s): %w", e.Code, e.City, err)
+			}
+			cache[e.TZ] = loc
+		}
+		zones = append(zones, placeZone{coords: Coordinates{Lat: e.Lat, Lon: e.Lon}, loc: loc})
+	}
+	return zones, nil
+}
+
+// mustLoadPlaceZones loads the embedded zones; like mustLoadCountries, a bad
+// edit to the data files fails at startup
+func mustLoadPlaceZones() []placeZone {
+	zones, err := loadPlaceZones(countriesJSON, regionsJSON)
+	if err != nil {
+		panic(err)
+	}
+	return zones
+}
+
+// TimezoneAt returns the IANA time zone at coords, taken from the nearest
+// built-in country city or region capital. It needs no network request but
+// is approximate near zone boundaries, such as in states split between two
+// zones; prefer the zone a provider reports, as in WeatherData.Timezone.
+func TimezoneAt(coords Coordinates) *time.Location {
+	best, bestKm := time.UTC, -1.0
+	for _, z := range placeZones {
+		if d := distanceKm(coords, z.coords); bestKm < 0 || d < bestKm {
+			best, bestKm = z.loc, d
+		}
+	}
+	return best
+}
+
+// zoneFor returns the IANA zone called name, or the zone at coords if name
+// is empty or unknown
+func zoneFor(name string, coords Coordinates) *time.Location {
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return TimezoneAt(coords)
+}
+
+// localTime returns t in loc, leaving the zero time as it is
+func localTime(t time.Time, loc *time.Location) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.In(loc)
+}
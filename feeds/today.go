@@ -0,0 +1,84 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TodayWeather represents current conditions plus today's outlook
+type TodayWeather struct {
+	WeatherData
+	HighC   float64   `json:"highC"`
+	LowC    float64   `json:"lowC"`
+	Sunrise time.Time `json:"sunrise"`
+	Sunset  time.Time `json:"sunset"`
+}
+
+// OpenMeteoTodayResponse represents a combined current and one-day daily response
+type OpenMeteoTodayResponse struct {
+	OpenMeteoResponse
+	Daily struct {
+		TemperatureMax []float64   `json:"temperature_2m_max"`
+		TemperatureMin []float64   `json:"temperature_2m_min"`
+		Sunrise        []Timestamp `json:"sunrise"`
+		Sunset         []Timestamp `json:"sunset"`
+	} `json:"daily"`
+}
+
+// FetchToday fetches today's weather using the default Client
+func FetchToday(country string) (*TodayWeather, error) {
+	return defaultClient.FetchToday(country)
+}
+
+// FetchToday fetches current conditions together with today's high, low,
+// sunrise and sunset in a single Open-Meteo request
+func (c *Client) FetchToday(country string) (*TodayWeather, error) {
+	ctx := context.Background()
+	coords, source, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+
+	q := coords.query()
+	q.Set("current", c.currentVariables())
+	q.Set("daily", "temperature_2m_max,temperature_2m_min,sunrise,sunset")
+	q.Set("forecast_days", "1")
+	q.Set("timezone", "auto")
+
+	var apiResp OpenMeteoTodayResponse
+	if err := c.getOpenMeteo(ctx, c.forecastBaseURL+"/v1/forecast", q, &apiResp); err != nil {
+		return nil, err
+	}
+	if err := apiResp.validate(); err != nil {
+		return nil, err
+	}
+
+	d := apiResp.Daily
+	if len(d.TemperatureMax) == 0 || len(d.TemperatureMin) == 0 || len(d.Sunrise) == 0 || len(d.Sunset) == 0 {
+		return nil, withKind(ErrDecode, errors.New("today's weather response has no daily values"))
+	}
+
+	loc := apiLocation(apiResp.Timezone, apiResp.UTCOffsetSeconds)
+	sunrise, err := d.Sunrise[0].In(loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sunrise: %w", err)
+	}
+	sunset, err := d.Sunset[0].In(loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sunset: %w", err)
+	}
+
+	w := apiResp.toWeatherData()
+	w.Source, w.Provider = source, ProviderOpenMeteo
+	c.finishWeather(w)
+
+	return &TodayWeather{
+		WeatherData: *w,
+		HighC:       c.roundTemperature(d.TemperatureMax[0]),
+		LowC:        c.roundTemperature(d.TemperatureMin[0]),
+		Sunrise:     sunrise,
+		Sunset:      sunset,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Tracer starts spans around a Client's work, for timing feed refreshes
+// and upstream requests. It is a hook for the application's own tracing;
+// the module does not depend on OpenTelemetry, so spans are not exported to
+// OpenTelemetry collectors and their names and attributes are the module's
+// own. Spans are started from the caller's context, so they nest under the
+// caller's own span when its tracer puts one there.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...SpanAttribute) (context.Context, Span)
+}
+
+// Span is an operation started by a Tracer
+type Span interface {
+	SetAttributes(attrs ...SpanAttribute)
+	// RecordError marks the span failed with err
+	RecordError(err error)
+	End()
+}
+
+// SpanAttribute is a span attribute; Value is a string, int, int64,
+// float64 or bool
+type SpanAttribute struct {
+	Key   string
+	Value any
+}
+
+// Span names and attribute keys
+const (
+	spanFeed    = "reef.feed"    // one feed of a Scheduler or Aggregator refresh
+	spanWeather = "reef.weather" // a current-conditions fetch through a WeatherProvider
+	spanFetch   = "reef.fetch"   // an upstream request, retries included
+
+	attrFeed       = "reef.feed"
+	attrLocation   = "reef.location"
+	attrProvider   = "reef.provider"
+	attrErrorType  = "reef.error_kind" // see ErrorKind
+	attrHTTPMethod = "reef.http.method"
+	attrHTTPStatus = "reef.http.status"
+	attrURL        = "reef.url" // without the query
+	attrServer     = "reef.host"
+)
+
+// WithTracer sets the Tracer the Client starts spans with: one per feed
+// refreshed by a Scheduler or Aggregator, per current-conditions fetch and
+// per upstream request. No spans are created by default.
+func WithTracer(t Tracer) Option {
+	return func(c *Client) {
+		c.tracer = t
+	}
+}
+
+// noopSpan is the Span of a Client without a Tracer
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...SpanAttribute) {}
+func (noopSpan) RecordError(error)              {}
+func (noopSpan) End()                           {}
+
+// spanLocationKey carries the location being fetched to nested spans
+type spanLocationKey struct{}
+
+// startSpan starts a span with the Client's Tracer, adding the location of
+// an enclosing span, and returns a no-op span without one
+func (c *Client) startSpan(ctx context.Context, name string, attrs ...SpanAttribute) (context.Context, Span) {
+	if c.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	if loc, ok := ctx.Value(spanLocationKey{}).(string); ok && !hasAttribute(attrs, attrLocation) {
+		attrs = append(attrs, SpanAttribute{attrLocation, loc})
+	}
+	return c.tracer.Start(ctx, name, attrs...)
+}
+
+// withSpanLocation records the location nested spans are for
+func withSpanLocation(ctx context.Context, loc string) context.Context {
+	return context.WithValue(ctx, spanLocationKey{}, loc)
+}
+
+// endSpan records err, if any, and ends span
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.SetAttributes(SpanAttribute{attrErrorType, ErrorKind(err)})
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// hasAttribute reports whether attrs sets key
+func hasAttribute(attrs []SpanAttribute, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL drops the query, which may carry API keys, from a URL for spans
+func redactURL(target string) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	u.RawQuery, u.User = "", nil
+	return u.String()
+}
+
+// providerName names a WeatherProvider for spans and WeatherData.Provider
+func providerName(p WeatherProvider) string {
+	switch p.(type) {
+	case *OpenMeteoProvider:
+		return ProviderOpenMeteo
+	case *NWSProvider:
+		return ProviderNWS
+	case *ECCCProvider:
+		return ProviderECCC
+	case *FallbackProvider:
+		return "fallback"
+	default:
+		return strings.TrimPrefix(fmt.Sprintf("%T", p), "*")
+	}
+}
@@ -0,0 +1,327 @@
+package feeds
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Traffic feed formats
+const (
+	// TrafficFormat511 is the event API (api/v2/get/event) shared by many
+	// state and provincial 511 systems, e.g. 511on.ca, 511ny.org, 511ga.org
+	TrafficFormat511 = "511"
+	// TrafficFormatOpen511 is the Open511 standard, e.g. DriveBC
+	TrafficFormatOpen511 = "open511"
+)
+
+// Traffic incident severities
+const (
+	TrafficSeverityUnknown  = "unknown"
+	TrafficSeverityMinor    = "minor"
+	TrafficSeverityModerate = "moderate"
+	TrafficSeverityMajor    = "major"
+)
+
+// Traffic incident types
+const (
+	TrafficTypeIncident     = "incident"
+	TrafficTypeConstruction = "construction"
+	TrafficTypeClosure      = "closure"
+	TrafficTypeEvent        = "event"
+	TrafficTypeConditions   = "conditions" // weather and road conditions
+	TrafficTypeOther        = "other"
+)
+
+// TrafficIncident is a road incident, closure or advisory from a 511 feed
+type TrafficIncident struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`     // one of the TrafficType constants
+	Severity    string    `json:"severity"` // one of the TrafficSeverity constants
+	Road        string    `json:"road,omitempty"`
+	Direction   string    `json:"direction,omitempty"`
+	Description string    `json:"description"`
+	Closed      bool      `json:"closed"` // the road is fully closed
+	Latitude    float64   `json:"latitude"`
+	Longitude   float64   `json:"longitude"`
+	DistanceKm  float64   `json:"distanceKm"` // from the area's center
+	Start       time.Time `json:"start,omitzero"`
+	Updated     time.Time `json:"updated,omitzero"`
+}
+
+// TrafficArea is a metro area or corridor whose incidents are read from a
+// 511 feed and kept within RadiusKm of its center
+type TrafficArea struct {
+	ID       string
+	Name     string
+	Format   string // TrafficFormat511 or TrafficFormatOpen511
+	URL      string // full event endpoint URL
+	APIKey   string // sent as the key parameter, for feeds that require one
+	RadiusKm float64
+	Coordinates
+}
+
+var (
+	trafficAreasMu sync.RWMutex
+
+	// trafficAreas holds areas whose feeds need no API key
+	trafficAreas = map[string]TrafficArea{
+		"toronto": {
+			ID: "toronto", Name: "Greater Toronto Area", Format: TrafficFormat511,
+			URL: "https://511on.ca/api/v2/get/event", RadiusKm: 60,
+			Coordinates: Coordinates{Lat: 43.6532, Lon: -79.3832},
+		},
+		"ottawa": {
+			ID: "ottawa", Name: "Ottawa", Format: TrafficFormat511,
+			URL: "https://511on.ca/api/v2/get/event", RadiusKm: 40,
+			Coordinates: Coordinates{Lat: 45.4215, Lon: -75.6972},
+		},
+		"vancouver": {
+			ID: "vancouver", Name: "Metro Vancouver", Format: TrafficFormatOpen511,
+			URL: "https://api.open511.gov.bc.ca/events", RadiusKm: 50,
+			Coordinates: Coordinates{Lat: 49.2827, Lon: -123.1207},
+		},
+		"sea-to-sky": {
+			ID: "sea-to-sky", Name: "Sea-to-Sky Highway (BC-99)", Format: TrafficFormatOpen511,
+			URL: "https://api.open511.gov.bc.ca/events", RadiusKm: 60,
+			Coordinates: Coordinates{Lat: 49.7016, Lon: -123.1558},
+		},
+	}
+)
+
+// RegisterTrafficArea adds or replaces an area used by FetchTraffic, e.g. a
+// state 511 feed with its API key
+func RegisterTrafficArea(area TrafficArea) error {
+	if area.ID == "" || area.URL == "" || (area.Format != TrafficFormat511 && area.Format != TrafficFormatOpen511) {
+		return fmt.Errorf("invalid traffic area %q (format %q)", area.ID, area.Format)
+	}
+	if area.RadiusKm <= 0 {
+		return fmt.Errorf("traffic area %q needs a positive radius", area.ID)
+	}
+	if err := area.Validate(); err != nil {
+		return err
+	}
+	area.ID = strings.ToLower(area.ID)
+
+	trafficAreasMu.Lock()
+	defer trafficAreasMu.Unlock()
+	trafficAreas[area.ID] = area
+	return nil
+}
+
+// TrafficEvent511 represents an event from a 511 api/v2/get/event response
+type TrafficEvent511 struct {
+	ID                string  `json:"ID"`
+	RoadwayName       string  `json:"RoadwayName"`
+	DirectionOfTravel string  `json:"DirectionOfTravel"`
+	Description       string  `json:"Description"`
+	EventType         string  `json:"EventType"`
+	Severity          string  `json:"Severity"`
+	IsFullClosure     bool    `json:"IsFullClosure"`
+	Latitude          float64 `json:"Latitude"`
+	Longitude         float64 `json:"Longitude"`
+	StartDate         int64   `json:"StartDate"`   // Unix seconds
+	LastUpdated       int64   `json:"LastUpdated"` // Unix seconds
+}
+
+// Open511EventsResponse represents an Open511 /events response
+type Open511EventsResponse struct {
+	Events []struct {
+		ID          string `json:"id"`
+		Headline    string `json:"headline"`
+		Description string `json:"description"`
+		EventType   string `json:"event_type"`
+		Severity    string `json:"severity"`
+		Status      string `json:"status"`
+		Created     string `json:"created"`
+		Updated     string `json:"updated"`
+		Roads       []struct {
+			Name      string `json:"name"`
+			Direction string `json:"direction"`
+			State     string `json:"state"`
+		} `json:"roads"`
+		Geography struct {
+			Type        string `json:"type"`
+			Coordinates any    `json:"coordinates"` // a point, or nested arrays for lines
+		} `json:"geography"`
+	} `json:"events"`
+}
+
+// trafficTypes maps source event types to TrafficType constants
+var trafficTypes = map[string]string{
+	"accidentsandincidents": TrafficTypeIncident,
+	"incident":              TrafficTypeIncident,
+	"roadwork":              TrafficTypeConstruction,
+	"construction":          TrafficTypeConstruction,
+	"closures":              TrafficTypeClosure,
+	"specialevents":         TrafficTypeEvent,
+	"special_event":         TrafficTypeEvent,
+	"weather_condition":     TrafficTypeConditions,
+	"road_condition":        TrafficTypeConditions,
+}
+
+// trafficSeverityRank orders severities for sorting, most severe first
+var trafficSeverityRank = map[string]int{
+	TrafficSeverityMajor:    0,
+	TrafficSeverityModerate: 1,
+	TrafficSeverityMinor:    2,
+	TrafficSeverityUnknown:  3,
+}
+
+// normalizeTrafficSeverity maps a source severity to a TrafficSeverity constant
+func normalizeTrafficSeverity(s string) string {
+	switch s = strings.ToLower(s); s {
+	case TrafficSeverityMinor, TrafficSeverityModerate, TrafficSeverityMajor:
+		return s
+	default:
+		return TrafficSeverityUnknown
+	}
+}
+
+// FetchTraffic fetches an area's traffic incidents using the default Client
+func FetchTraffic(areaID string) ([]TrafficIncident, error) {
+	return defaultClient.FetchTraffic(areaID)
+}
+
+// FetchTraffic fetches the incidents, closures and advisories within a
+// registered traffic area, most severe first and then most recently updated
+func (c *Client) FetchTraffic(areaID string) ([]TrafficIncident, error) {
+	trafficAreasMu.RLock()
+	area, ok := trafficAreas[strings.ToLower(areaID)]
+	trafficAreasMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: no traffic area %q", ErrUnknownLocation, areaID)
+	}
+	return c.fetchTraffic(context.Background(), area)
+}
+
+// fetchTraffic reads an area's feed and keeps incidents within its radius
+func (c *Client) fetchTraffic(ctx context.Context, area TrafficArea) ([]TrafficIncident, error) {
+	q := url.Values{}
+	if area.APIKey != "" {
+		q.Set("key", area.APIKey)
+	}
+
+	var all []TrafficIncident
+	var err error
+	switch area.Format {
+	case TrafficFormat511:
+		q.Set("format", "json")
+		all, err = c.fetch511Events(ctx, area.URL, q)
+	case TrafficFormatOpen511:
+		q.Set("status", "ACTIVE")
+		q.Set("format", "json")
+		all, err = c.fetchOpen511Events(ctx, area.URL, q)
+	default:
+		err = fmt.Errorf("unsupported traffic format %q", area.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", area.Name, err)
+	}
+
+	var incidents []TrafficIncident
+	for _, inc := range all {
+		d := distanceKm(area.Coordinates, Coordinates{Lat: inc.Latitude, Lon: inc.Longitude})
+		if d <= area.RadiusKm {
+			inc.DistanceKm = roundTo(d, 1)
+			incidents = append(incidents, inc)
+		}
+	}
+	slices.SortStableFunc(incidents, func(a, b TrafficIncident) int {
+		return cmp.Or(
+			cmp.Compare(trafficSeverityRank[a.Severity], trafficSeverityRank[b.Severity]),
+			b.Updated.Compare(a.Updated),
+		)
+	})
+	return incidents, nil
+}
+
+// fetch511Events reads a 511 event endpoint
+func (c *Client) fetch511Events(ctx context.Context, endpoint string, q url.Values) ([]TrafficIncident, error) {
+	var events []TrafficEvent511
+	if err := c.getJSON(ctx, endpoint, q, &events); err != nil {
+		return nil, err
+	}
+
+	incidents := make([]TrafficIncident, 0, len(events))
+	for _, e := range events {
+		inc := TrafficIncident{
+			ID:          e.ID,
+			Type:        cmp.Or(trafficTypes[strings.ToLower(e.EventType)], TrafficTypeOther),
+			Severity:    normalizeTrafficSeverity(e.Severity),
+			Road:        e.RoadwayName,
+			Direction:   e.DirectionOfTravel,
+			Description: strings.TrimSpace(e.Description),
+			Closed:      e.IsFullClosure,
+			Latitude:    e.Latitude,
+			Longitude:   e.Longitude,
+		}
+		if e.StartDate > 0 {
+			inc.Start = time.Unix(e.StartDate, 0).UTC()
+		}
+		if e.LastUpdated > 0 {
+			inc.Updated = time.Unix(e.LastUpdated, 0).UTC()
+		}
+		incidents = append(incidents, inc)
+	}
+	return incidents, nil
+}
+
+// fetchOpen511Events reads an Open511 events endpoint
+func (c *Client) fetchOpen511Events(ctx context.Context, endpoint string, q url.Values) ([]TrafficIncident, error) {
+	var apiResp Open511EventsResponse
+	if err := c.getJSON(ctx, endpoint, q, &apiResp); err != nil {
+		return nil, err
+	}
+
+	incidents := make([]TrafficIncident, 0, len(apiResp.Events))
+	for _, e := range apiResp.Events {
+		pos, ok := firstPosition(e.Geography.Coordinates)
+		if !ok {
+			continue
+		}
+		inc := TrafficIncident{
+			ID:          e.ID,
+			Type:        cmp.Or(trafficTypes[strings.ToLower(e.EventType)], TrafficTypeOther),
+			Severity:    normalizeTrafficSeverity(e.Severity),
+			Description: strings.TrimSpace(cmp.Or(e.Description, e.Headline)),
+			Latitude:    pos.Lat,
+			Longitude:   pos.Lon,
+		}
+		if len(e.Roads) > 0 {
+			inc.Road, inc.Direction = e.Roads[0].Name, e.Roads[0].Direction
+			inc.Closed = strings.EqualFold(e.Roads[0].State, "CLOSED")
+		}
+		if t, err := time.Parse(time.RFC3339, e.Created); err == nil {
+			inc.Start = t
+		}
+		if t, err := time.Parse(time.RFC3339, e.Updated); err == nil {
+			inc.Updated = t
+		}
+		incidents = append(incidents, inc)
+	}
+	return incidents, nil
+}
+
+// firstPosition returns the first [lon, lat] position of a GeoJSON coordinates
+// value, descending into nested arrays for lines and polygons
+func firstPosition(coords any) (Coordinates, bool) {
+	arr, ok := coords.([]any)
+	if !ok || len(arr) == 0 {
+		return Coordinates{}, false
+	}
+	if _, nested := arr[0].([]any); nested {
+		return firstPosition(arr[0])
+	}
+	if len(arr) < 2 {
+		return Coordinates{}, false
+	}
+	lon, lonOK := arr[0].(float64)
+	lat, latOK := arr[1].(float64)
+	return Coordinates{Lat: lat, Lon: lon}, lonOK && latOK
+}
@@ -0,0 +1,357 @@
+package feeds
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// transitMaxAge is how old a cached GTFS-Realtime feed may be; agencies
+// publish new snapshots every 15–30 seconds
+const transitMaxAge = 30 * time.Second
+
+// TransitAgency is a transit operator publishing GTFS-Realtime feeds
+type TransitAgency struct {
+	ID             string
+	Name           string
+	TripUpdatesURL string // GTFS-Realtime TripUpdates feed
+	AlertsURL      string // GTFS-Realtime service alerts feed, optional
+	APIKeyParam    string // query parameter carrying APIKey, e.g. "api_key"
+	APIKey         string
+}
+
+var (
+	transitAgenciesMu sync.RWMutex
+
+	// transitAgencies holds agencies known to publish GTFS-Realtime; those with
+	// an APIKeyParam need their key set with RegisterTransitAgency
+	transitAgencies = map[string]TransitAgency{
+		"mbta": {
+			ID: "mbta", Name: "MBTA (Boston)",
+			TripUpdatesURL: "https://cdn.mbta.com/realtime/TripUpdates.pb",
+			AlertsURL:      "https://cdn.mbta.com/realtime/Alerts.pb",
+		},
+		"trimet": {
+			ID: "trimet", Name: "TriMet (Portland)",
+			TripUpdatesURL: "https://developer.trimet.org/ws/V1/TripUpdate",
+			AlertsURL:      "https://developer.trimet.org/ws/V1/FeedSpecAlerts",
+			APIKeyParam:    "appID",
+		},
+	}
+)
+
+// RegisterTransitAgency adds or replaces an agency used by FetchDepartures
+// and FetchTransitAlerts, e.g. one whose feeds need an API key
+func RegisterTransitAgency(agency TransitAgency) error {
+	if agency.ID == "" || agency.TripUpdatesURL == "" {
+		return fmt.Errorf("invalid transit agency %q: ID and TripUpdatesURL are required", agency.ID)
+	}
+	if agency.APIKey != "" && agency.APIKeyParam == "" {
+		return fmt.Errorf("transit agency %q has an API key but no APIKeyParam", agency.ID)
+	}
+	agency.ID = strings.ToLower(agency.ID)
+
+	transitAgenciesMu.Lock()
+	defer transitAgenciesMu.Unlock()
+	transitAgencies[agency.ID] = agency
+	return nil
+}
+
+// TransitAgencies returns the registered transit agencies sorted by ID
+func TransitAgencies() []TransitAgency {
+	transitAgenciesMu.RLock()
+	defer transitAgenciesMu.RUnlock()
+	agencies := make([]TransitAgency, 0, len(transitAgencies))
+	for _, a := range transitAgencies {
+		agencies = append(agencies, a)
+	}
+	slices.SortFunc(agencies, func(a, b TransitAgency) int { return strings.Compare(a.ID, b.ID) })
+	return agencies
+}
+
+// transitAgency looks up a registered agency
+func transitAgency(id string) (TransitAgency, error) {
+	transitAgenciesMu.RLock()
+	agency, ok := transitAgencies[strings.ToLower(id)]
+	transitAgenciesMu.RUnlock()
+	if !ok {
+		return TransitAgency{}, fmt.Errorf("%w: %q", ErrUnknownAgency, id)
+	}
+	if agency.APIKeyParam != "" && agency.APIKey == "" {
+		return TransitAgency{}, fmt.Errorf("transit agency %q needs an API key; set it with RegisterTransitAgency", agency.ID)
+	}
+	return agency, nil
+}
+
+// Departure is a predicted departure from a stop
+type Departure struct {
+	AgencyID     string    `json:"agencyId"`
+	RouteID      string    `json:"routeId"`
+	TripID       string    `json:"tripId"`
+	StopID       string    `json:"stopId"`
+	DirectionID  int       `json:"directionId"`
+	Time         time.Time `json:"time"`
+	DelaySeconds int       `json:"delaySeconds"` // positive when running late
+}
+
+// TransitAlert is a service alert affecting an agency's routes or stops
+type TransitAlert struct {
+	ID          string    `json:"id"`
+	Header      string    `json:"header"`
+	Description string    `json:"description,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	Cause       string    `json:"cause"`    // e.g. "maintenance", "weather"
+	Effect      string    `json:"effect"`   // e.g. "detour", "significant_delays"
+	Severity    string    `json:"severity"` // "unknown", "info", "warning" or "severe"
+	RouteIDs    []string  `json:"routeIds,omitempty"`
+	StopIDs     []string  `json:"stopIds,omitempty"`
+	Start       time.Time `json:"start,omitzero"`
+	End         time.Time `json:"end,omitzero"`
+}
+
+// GTFS-Realtime Alert enum names, indexed by value
+var (
+	gtfsCauses = []string{
+		"", "unknown", "other", "technical_problem", "strike", "demonstration", "accident",
+		"holiday", "weather", "maintenance", "construction", "police_activity", "medical_emergency",
+	}
+	gtfsEffects = []string{
+		"", "no_service", "reduced_service", "significant_delays", "detour", "additional_service",
+		"modified_service", "other", "unknown", "stop_moved", "no_effect", "accessibility_issue",
+	}
+	gtfsSeverities = []string{"", "unknown", "info", "warning", "severe"}
+)
+
+// gtfsEnumName returns names[v], or "unknown" for values not in the table
+func gtfsEnumName(names []string, v uint64) string {
+	if v == 0 || v >= uint64(len(names)) {
+		return "unknown"
+	}
+	return names[v]
+}
+
+// FetchDepartures fetches upcoming departures from a stop using the default Client
+func FetchDepartures(agencyID, stopID string, limit int) ([]Departure, error) {
+	return defaultClient.FetchDepartures(agencyID, stopID, limit)
+}
+
+// FetchDepartures returns up to limit predicted departures from stopID,
+// soonest first, from a registered agency's TripUpdates feed. A limit of 0
+// returns all of them.
+func (c *Client) FetchDepartures(agencyID, stopID string, limit int) ([]Departure, error) {
+	agency, err := transitAgency(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchDepartures(context.Background(), agency, stopID, limit)
+}
+
+// fetchDepartures collects the stop's departures that are not yet more than
+// a minute in the past; skipped stops and updates without an absolute time
+// are left out
+func (c *Client) fetchDepartures(ctx context.Context, agency TransitAgency, stopID string, limit int) ([]Departure, error) {
+	feed, err := c.getGTFSFeed(ctx, agency, agency.TripUpdatesURL)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-time.Minute)
+	var departures []Departure
+	for _, e := range feed.entities {
+		tu := e.tripUpdate
+		if e.deleted || tu == nil {
+			continue
+		}
+		for _, st := range tu.stopTimes {
+			if st.stopID != stopID || st.skipped {
+				continue
+			}
+			ev := cmp.Or(st.departure, st.arrival)
+			if ev == nil || ev.time == 0 {
+				continue
+			}
+			t := time.Unix(ev.time, 0)
+			if t.Before(cutoff) {
+				continue
+			}
+			departures = append(departures, Departure{
+				AgencyID:     agency.ID,
+				RouteID:      tu.trip.routeID,
+				TripID:       tu.trip.tripID,
+				StopID:       st.stopID,
+				DirectionID:  int(tu.trip.directionID),
+				Time:         t,
+				DelaySeconds: int(ev.delay),
+			})
+		}
+	}
+	slices.SortFunc(departures, func(a, b Departure) int { return a.Time.Compare(b.Time) })
+	if limit > 0 && len(departures) > limit {
+		departures = departures[:limit]
+	}
+	return departures, nil
+}
+
+// FetchTransitAlerts fetches an agency's service alerts using the default Client
+func FetchTransitAlerts(agencyID, stopID string) ([]TransitAlert, error) {
+	return defaultClient.FetchTransitAlerts(agencyID, stopID)
+}
+
+// FetchTransitAlerts returns a registered agency's currently active service
+// alerts, limited to those naming stopID unless it is empty
+func (c *Client) FetchTransitAlerts(agencyID, stopID string) ([]TransitAlert, error) {
+	agency, err := transitAgency(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	if agency.AlertsURL == "" {
+		return nil, fmt.Errorf("transit agency %q publishes no alerts feed", agency.ID)
+	}
+	return c.fetchTransitAlerts(context.Background(), agency, stopID)
+}
+
+// fetchTransitAlerts converts the alerts in agency's feed that are active now
+func (c *Client) fetchTransitAlerts(ctx context.Context, agency TransitAgency, stopID string) ([]TransitAlert, error) {
+	feed, err := c.getGTFSFeed(ctx, agency, agency.AlertsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uint64(time.Now().Unix())
+	var alerts []TransitAlert
+	for _, e := range feed.entities {
+		a := e.alert
+		if e.deleted || a == nil {
+			continue
+		}
+		period, active := activePeriod(a.activePeriods, now)
+		if !active {
+			continue
+		}
+		alert := TransitAlert{
+			ID:          e.id,
+			Header:      a.header,
+			Description: a.description,
+			URL:         a.url,
+			Cause:       gtfsEnumName(gtfsCauses, a.cause),
+			Effect:      gtfsEnumName(gtfsEffects, a.effect),
+			Severity:    gtfsEnumName(gtfsSeverities, a.severity),
+		}
+		if period.start > 0 {
+			alert.Start = time.Unix(int64(period.start), 0)
+		}
+		if period.end > 0 {
+			alert.End = time.Unix(int64(period.end), 0)
+		}
+		for _, sel := range a.informed {
+			if sel.routeID != "" && !slices.Contains(alert.RouteIDs, sel.routeID) {
+				alert.RouteIDs = append(alert.RouteIDs, sel.routeID)
+			}
+			if sel.stopID != "" && !slices.Contains(alert.StopIDs, sel.stopID) {
+				alert.StopIDs = append(alert.StopIDs, sel.stopID)
+			}
+		}
+		if stopID != "" && !slices.Contains(alert.StopIDs, stopID) {
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+// activePeriod returns the period containing now; an alert without periods
+// is always active
+func activePeriod(periods []gtfsTimeRange, now uint64) (gtfsTimeRange, bool) {
+	if len(periods) == 0 {
+		return gtfsTimeRange{}, true
+	}
+	for _, p := range periods {
+		if (p.start == 0 || p.start <= now) && (p.end == 0 || now < p.end) {
+			return p, true
+		}
+	}
+	return gtfsTimeRange{}, false
+}
+
+// getGTFSFeed fetches and decodes a GTFS-Realtime feed, accepting a cached
+// copy only within transitMaxAge
+func (c *Client) getGTFSFeed(ctx context.Context, agency TransitAgency, endpoint string) (*gtfsFeed, error) {
+	target := transitURL(agency, endpoint)
+	body, err := c.cachedGet(withMaxAge(ctx, transitMaxAge), target)
+	if err != nil {
+		return nil, err
+	}
+	feed, err := decodeGTFSFeed(body)
+	if err != nil {
+		c.evict(target)
+		return nil, fmt.Errorf("failed to parse GTFS-Realtime feed: %w", withKind(ErrDecode, err))
+	}
+	return feed, nil
+}
+
+// transitURL adds the agency's API key, if any, to a feed URL
+func transitURL(agency TransitAgency, endpoint string) string {
+	if agency.APIKey == "" {
+		return endpoint
+	}
+	q := url.Values{}
+	q.Set(agency.APIKeyParam, agency.APIKey)
+	if strings.Contains(endpoint, "?") {
+		return endpoint + "&" + q.Encode()
+	}
+	return requestURL(endpoint, q)
+}
+
+// PollTransit keeps the registered agencies' feeds warm using the default Client
+func PollTransit(ctx context.Context, interval time.Duration) error {
+	return defaultClient.PollTransit(ctx, interval)
+}
+
+// PollTransit refetches every registered agency's feeds each interval until
+// ctx is done, so FetchDepartures and FetchTransitAlerts answer from the
+// cache. Agencies still missing an API key are skipped; fetch errors are
+// ignored and retried on the next tick. It returns ctx's error.
+func (c *Client) PollTransit(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("transit poll interval must be positive")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.refreshTransit(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshTransit fetches every registered agency's feeds concurrently,
+// bypassing the cache
+func (c *Client) refreshTransit(ctx context.Context) {
+	ctx = BypassCache(ctx)
+	var wg sync.WaitGroup
+	for _, agency := range TransitAgencies() {
+		if agency.APIKeyParam != "" && agency.APIKey == "" {
+			continue
+		}
+		for _, endpoint := range []string{agency.TripUpdatesURL, agency.AlertsURL} {
+			if endpoint == "" {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.getGTFSFeed(ctx, agency, endpoint)
+			}()
+		}
+	}
+	wg.Wait()
+}
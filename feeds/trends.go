@@ -0,0 +1,186 @@
+package feeds
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Trend metrics
+const (
+	TrendTemperature = "temperature" // °C, compared with about a day earlier
+	TrendPressure    = "pressure"    // hPa, compared with about three hours earlier
+	TrendWind        = "wind"        // km/h, compared with about three hours earlier
+)
+
+// Trend directions
+const (
+	TrendRising  = "rising"
+	TrendFalling = "falling"
+	TrendSteady  = "steady"
+)
+
+// Windows trends are measured over, and how far the earlier reading may be
+// from the start of the window
+const (
+	dayTrendWindow   = 24 * time.Hour
+	shortTrendWindow = 3 * time.Hour
+	trendTolerance   = time.Hour
+
+	// weatherHistoryAge is how long the Scheduler keeps weather readings
+	weatherHistoryAge = dayTrendWindow + trendTolerance
+)
+
+// WeatherReading is a WeatherData and when it was fetched
+type WeatherReading struct {
+	At      time.Time    `json:"at"`
+	Weather *WeatherData `json:"weather"`
+}
+
+// Trend is how a weather reading changed over a recent window, such as
+// "5°C warmer than yesterday" or "pressure falling rapidly"
+type Trend struct {
+	Metric      string    `json:"metric"` // one of the Trend metric constants
+	Current     float64   `json:"current"`
+	Previous    float64   `json:"previous"`
+	Change      float64   `json:"change"` // Current minus Previous, in the metric's unit
+	Since       time.Time `json:"since"`  // when Previous was fetched
+	Direction   string    `json:"direction"`
+	Rapid       bool      `json:"rapid,omitempty"`
+	Description string    `json:"description"`
+}
+
+// trendRule says when a change counts as steady or rapid
+type trendRule struct {
+	metric string
+	window time.Duration
+	value  func(*WeatherData) (float64, bool)
+	steady float64 // changes smaller than this are steady
+	rapid  float64 // changes at least this large are rapid, 0 for never
+}
+
+var trendRules = []trendRule{
+	{
+		metric: TrendTemperature,
+		window: dayTrendWindow,
+		value:  func(w *WeatherData) (float64, bool) { return w.TemperatureC, true },
+		steady: 1,
+	},
+	{
+		// Pressure tendency over three hours, as in surface observations
+		metric: TrendPressure,
+		window: shortTrendWindow,
+		value:  func(w *WeatherData) (float64, bool) { return w.PressureHPa, w.PressureHPa > 0 },
+		steady: 1,
+		rapid:  3,
+	},
+	{
+		metric: TrendWind,
+		window: shortTrendWindow,
+		value:  func(w *WeatherData) (float64, bool) { return w.WindSpeedKmh, true },
+		steady: 5,
+		rapid:  20,
+	},
+}
+
+// ComputeTrends compares current with the readings in history, oldest first,
+// and returns a Trend for each metric with a reading from the start of its
+// window, give or take an hour. Descriptions use °F and mph when current
+// carries imperial values.
+func ComputeTrends(current WeatherReading, history []WeatherReading) []Trend {
+	if current.Weather == nil {
+		return nil
+	}
+	var trends []Trend
+	for _, rule := range trendRules {
+		cur, ok := rule.value(current.Weather)
+		if !ok {
+			continue
+		}
+		prev, ok := readingNear(history, current.At.Add(-rule.window), rule)
+		if !ok {
+			continue
+		}
+		was, _ := rule.value(prev.Weather)
+		t := Trend{
+			Metric:    rule.metric,
+			Current:   cur,
+			Previous:  was,
+			Change:    roundTo(cur-was, 1),
+			Since:     prev.At,
+			Direction: TrendSteady,
+		}
+		switch {
+		case math.Abs(t.Change) < rule.steady:
+		case t.Change > 0:
+			t.Direction = TrendRising
+		default:
+			t.Direction = TrendFalling
+		}
+		t.Rapid = t.Direction != TrendSteady && rule.rapid > 0 && math.Abs(t.Change) >= rule.rapid
+		t.Description = describeTrend(t, current.Weather.Imperial != nil)
+		trends = append(trends, t)
+	}
+	return trends
+}
+
+// readingNear returns the reading closest to at that has a value for rule,
+// if one is within trendTolerance
+func readingNear(history []WeatherReading, at time.Time, rule trendRule) (WeatherReading, bool) {
+	var best WeatherReading
+	found := false
+	for _, r := range history {
+		if r.Weather == nil {
+			continue
+		}
+		if _, ok := rule.value(r.Weather); !ok {
+			continue
+		}
+		if d := absDuration(r.At.Sub(at)); d <= trendTolerance && (!found || d < absDuration(best.At.Sub(at))) {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
+
+// describeTrend returns a short English description of t
+func describeTrend(t Trend, imperial bool) string {
+	change := math.Abs(t.Change)
+	switch t.Metric {
+	case TrendTemperature:
+		unit := "°C"
+		if imperial {
+			change, unit = change*9/5, "°F"
+		}
+		switch t.Direction {
+		case TrendRising:
+			return fmt.Sprintf("%.0f%s warmer than yesterday", change, unit)
+		case TrendFalling:
+			return fmt.Sprintf("%.0f%s colder than yesterday", change, unit)
+		}
+		return "about the same temperature as yesterday"
+	case TrendPressure:
+		if t.Direction == TrendSteady {
+			return "pressure steady"
+		}
+		if t.Rapid {
+			return "pressure " + t.Direction + " rapidly"
+		}
+		return "pressure " + t.Direction
+	case TrendWind:
+		unit := "km/h"
+		if imperial {
+			change, unit = KmhToMph(change), "mph"
+		}
+		switch {
+		case t.Direction == TrendRising && t.Rapid:
+			return fmt.Sprintf("wind strengthening quickly, up %.0f %s", change, unit)
+		case t.Direction == TrendRising:
+			return fmt.Sprintf("wind picking up, up %.0f %s", change, unit)
+		case t.Direction == TrendFalling:
+			return fmt.Sprintf("wind easing, down %.0f %s", change, unit)
+		}
+		return "wind steady"
+	}
+	return ""
+}
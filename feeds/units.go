@@ -0,0 +1,186 @@
+package feeds
+
+// UnitSystem selects the units results are reported in
+type UnitSystem string
+
+// Supported unit systems
+const (
+	UnitsMetric   UnitSystem = "metric"   // °C, mm, cm (the default)
+	UnitsImperial UnitSystem = "imperial" // °F, inches and mph, reported alongside the metric values
+)
+
+// ImperialWeather holds the imperial equivalents of a reading's metric values
+type ImperialWeather struct {
+	TemperatureF    float64 `json:"temperatureF"`
+	FeelsLikeF      float64 `json:"feelsLikeF"`
+	RainIn          float64 `json:"rainIn"`
+	SnowfallIn      float64 `json:"snowfallIn"`
+	PrecipitationIn float64 `json:"precipitationIn"`
+	WindSpeedMph    float64 `json:"windSpeedMph"`
+	WindGustsMph    float64 `json:"windGustsMph"`
+	PressureInHg    float64 `json:"pressureInHg,omitempty"`
+}
+
+// WithUnits selects the unit system for current-conditions results. With
+// UnitsImperial every WeatherData also carries an Imperial block converted
+// from the metric fields, which are always present. Temperatures in it follow
+// WithTemperaturePrecision; result transforms run after the conversion.
+func WithUnits(units UnitSystem) Option {
+	return func(c *Client) {
+		c.units = units
+	}
+}
+
+// PrecipitationUnit is a unit for rain and precipitation amounts, named as
+// in Open-Meteo's precipitation_unit parameter
+type PrecipitationUnit string
+
+// Supported precipitation units
+const (
+	PrecipitationMillimeters PrecipitationUnit = "mm"   // snowfall in cm
+	PrecipitationInches      PrecipitationUnit = "inch" // snowfall in inches too
+)
+
+// WindUnit is a unit for wind speeds, named as in Open-Meteo's
+// wind_speed_unit parameter
+type WindUnit string
+
+// Supported wind speed units
+const (
+	WindKmh   WindUnit = "kmh"
+	WindMs    WindUnit = "ms"
+	WindMph   WindUnit = "mph"
+	WindKnots WindUnit = "kn"
+)
+
+// PrecipitationAmounts holds a reading's precipitation in the unit chosen
+// WithPrecipitationUnit
+type PrecipitationAmounts struct {
+	Unit          PrecipitationUnit `json:"unit"`
+	Rain          float64           `json:"rain"`
+	Precipitation float64           `json:"precipitation"`
+	Snowfall      float64           `json:"snowfall"`
+	SnowfallUnit  string            `json:"snowfallUnit"` // "cm" with millimetres, "inch" with inches
+}
+
+// WindSpeeds holds a reading's wind speeds in the unit chosen WithWindUnit
+type WindSpeeds struct {
+	Unit  WindUnit `json:"unit"`
+	Speed float64  `json:"speed"`
+	Gusts float64  `json:"gusts"`
+}
+
+// WithPrecipitationUnit reports rain, precipitation and snowfall in unit as
+// well, in WeatherData.Precipitation, whatever the unit system. The metric
+// fields are unchanged. Units other than the PrecipitationUnit constants are
+// ignored.
+func WithPrecipitationUnit(unit PrecipitationUnit) Option {
+	return func(c *Client) {
+		switch unit {
+		case PrecipitationMillimeters, PrecipitationInches:
+			c.precipitationUnit = unit
+		}
+	}
+}
+
+// WithWindUnit reports wind speed and gusts in unit as well, in
+// WeatherData.Wind, whatever the unit system. The km/h fields are unchanged.
+// Units other than the WindUnit constants are ignored.
+func WithWindUnit(unit WindUnit) Option {
+	return func(c *Client) {
+		switch unit {
+		case WindKmh, WindMs, WindMph, WindKnots:
+			c.windUnit = unit
+		}
+	}
+}
+
+// PrecipitationIn returns the reading's precipitation amounts in unit, which
+// must be one of the PrecipitationUnit constants
+func (w WeatherData) PrecipitationIn(unit PrecipitationUnit) PrecipitationAmounts {
+	if unit == PrecipitationInches {
+		return PrecipitationAmounts{
+			Unit:          unit,
+			Rain:          MillimetersToInches(w.RainMM),
+			Precipitation: MillimetersToInches(w.PrecipitationMM),
+			Snowfall:      MillimetersToInches(w.SnowfallCM * 10),
+			SnowfallUnit:  "inch",
+		}
+	}
+	return PrecipitationAmounts{
+		Unit:          unit,
+		Rain:          w.RainMM,
+		Precipitation: w.PrecipitationMM,
+		Snowfall:      w.SnowfallCM,
+		SnowfallUnit:  "cm",
+	}
+}
+
+// WindIn returns the reading's wind speeds in unit, which must be one of the
+// WindUnit constants
+func (w WeatherData) WindIn(unit WindUnit) WindSpeeds {
+	convert := func(kmh float64) float64 { return kmh }
+	switch unit {
+	case WindMs:
+		convert = func(kmh float64) float64 { return kmh / 3.6 }
+	case WindMph:
+		convert = KmhToMph
+	case WindKnots:
+		convert = func(kmh float64) float64 { return kmh / 1.852 }
+	}
+	return WindSpeeds{Unit: unit, Speed: convert(w.WindSpeedKmh), Gusts: convert(w.WindGustsKmh)}
+}
+
+// ToImperial returns the imperial equivalents of the reading's metric values
+func (w WeatherData) ToImperial() ImperialWeather {
+	return ImperialWeather{
+		TemperatureF:    CelsiusToFahrenheit(w.TemperatureC),
+		FeelsLikeF:      CelsiusToFahrenheit(w.FeelsLikeC),
+		RainIn:          MillimetersToInches(w.RainMM),
+		SnowfallIn:      MillimetersToInches(w.SnowfallCM * 10),
+		PrecipitationIn: MillimetersToInches(w.PrecipitationMM),
+		WindSpeedMph:    KmhToMph(w.WindSpeedKmh),
+		WindGustsMph:    KmhToMph(w.WindGustsKmh),
+		PressureInHg:    HectopascalsToInchesOfMercury(w.PressureHPa),
+	}
+}
+
+// CelsiusToFahrenheit converts a temperature from °C to °F
+func CelsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+// FahrenheitToCelsius converts a temperature from °F to °C
+func FahrenheitToCelsius(f float64) float64 {
+	return (f - 32) * 5 / 9
+}
+
+// MillimetersToInches converts a length from mm to inches
+func MillimetersToInches(mm float64) float64 {
+	return mm / 25.4
+}
+
+// KmhToMph converts a speed from km/h to mph
+func KmhToMph(kmh float64) float64 {
+	return kmh / 1.609344
+}
+
+// MphToKmh converts a speed from mph to km/h
+func MphToKmh(mph float64) float64 {
+	return mph * 1.609344
+}
+
+// HectopascalsToInchesOfMercury converts a pressure from hPa to inHg
+func HectopascalsToInchesOfMercury(hpa float64) float64 {
+	return hpa / 33.8639
+}
+
+// FeetToMeters converts a length from feet to metres
+func FeetToMeters(ft float64) float64 {
+	return ft * 0.3048
+}
+
+// CubicFeetToCubicMeters converts a flow from ft³/s to m³/s
+func CubicFeetToCubicMeters(cfs float64) float64 {
+	return cfs * 0.028316846592
+}
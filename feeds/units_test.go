@@ -0,0 +1,66 @@
+package feeds
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWithPrecipitationAndWindUnits(t *testing.T) {
+	srv := currentServer(t)
+	c := NewClient(WithBaseURL(srv.URL), WithPrecipitationUnit("inch"), WithWindUnit("mph"))
+	w, err := c.FetchWeather("US")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Precipitation == nil || w.Precipitation.Unit != PrecipitationInches || w.Precipitation.SnowfallUnit != "inch" {
+		t.Errorf("Precipitation = %+v, want amounts in inches", w.Precipitation)
+	}
+	if w.Wind == nil || w.Wind.Unit != WindMph || math.Abs(w.Wind.Speed-9.196) > 0.001 || math.Abs(w.Wind.Gusts-19.449) > 0.001 {
+		t.Errorf("Wind = %+v, want 9.196 mph gusting 19.449 mph", w.Wind)
+	}
+	if w.WindSpeedKmh != 14.8 {
+		t.Errorf("WindSpeedKmh = %v, want it unchanged at 14.8", w.WindSpeedKmh)
+	}
+
+	plain, err := NewClient(WithBaseURL(srv.URL), WithWindUnit("furlongs")).FetchWeather("US")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Precipitation != nil || plain.Wind != nil {
+		t.Errorf("got Precipitation %+v, Wind %+v without valid units, want neither", plain.Precipitation, plain.Wind)
+	}
+}
+
+func TestUnitConversions(t *testing.T) {
+	w := WeatherData{RainMM: 25.4, PrecipitationMM: 50.8, SnowfallCM: 2.54, WindSpeedKmh: 36, WindGustsKmh: 72}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+	for _, tt := range []struct {
+		unit                          PrecipitationUnit
+		rain, precipitation, snowfall float64
+		snowfallUnit                  string
+	}{
+		{PrecipitationMillimeters, 25.4, 50.8, 2.54, "cm"},
+		{PrecipitationInches, 1, 2, 1, "inch"},
+	} {
+		p := w.PrecipitationIn(tt.unit)
+		if !near(p.Rain, tt.rain) || !near(p.Precipitation, tt.precipitation) || !near(p.Snowfall, tt.snowfall) || p.SnowfallUnit != tt.snowfallUnit {
+			t.Errorf("PrecipitationIn(%s) = %+v", tt.unit, p)
+		}
+	}
+
+	for _, tt := range []struct {
+		unit         WindUnit
+		speed, gusts float64
+	}{
+		{WindKmh, 36, 72},
+		{WindMs, 10, 20},
+		{WindMph, 22.369362920544, 44.738725841088},
+		{WindKnots, 19.438444924406, 38.876889848812},
+	} {
+		got := w.WindIn(tt.unit)
+		if math.Abs(got.Speed-tt.speed) > 1e-6 || math.Abs(got.Gusts-tt.gusts) > 1e-6 {
+			t.Errorf("WindIn(%s) = %+v, want %v gusting %v", tt.unit, got, tt.speed, tt.gusts)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package feeds
+
+import "math"
+
+// uvBands are the WHO UV index bands below Extreme and the index each
+// ends before
+var uvBands = []struct {
+	below float64
+	name  string
+}{
+	{3, "Low"},
+	{6, "Moderate"},
+	{8, "High"},
+	{11, "Very High"},
+}
+
+// WithUVIndex additionally requests the UV index with current conditions,
+// filling WeatherData.UVIndex and UVBand
+func WithUVIndex() Option {
+	return func(c *Client) {
+		c.uvIndex = true
+	}
+}
+
+// UVBand returns the WHO exposure band for a UV index: Low (below 3),
+// Moderate (below 6), High (below 8), Very High (below 11) or Extreme. It
+// returns "" for negative and NaN indexes, which no band covers.
+func UVBand(index float64) string {
+	if index < 0 || math.IsNaN(index) {
+		return ""
+	}
+	for _, b := range uvBands {
+		if index < b.below {
+			return b.name
+		}
+	}
+	return "Extreme"
+}
+
+// currentVariables returns the current-conditions variables to request
+func (c *Client) currentVariables() string {
+	if c.uvIndex {
+		return currentVariables + ",uv_index"
+	}
+	return currentVariables
+}
@@ -0,0 +1,69 @@
+package feeds
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestUVBand(t *testing.T) {
+	tests := []struct {
+		index float64
+		want  string
+	}{
+		{0, "Low"},
+		{2.9, "Low"},
+		{3, "Moderate"},
+		{5.9, "Moderate"},
+		{6, "High"},
+		{7.9, "High"},
+		{8, "Very High"},
+		{10.9, "Very High"},
+		{11, "Extreme"},
+		{16.4, "Extreme"},
+		{-0.1, ""},
+		{-3, ""},
+		{math.NaN(), ""},
+	}
+	for _, tt := range tests {
+		if got := UVBand(tt.index); got != tt.want {
+			t.Errorf("UVBand(%v) = %q, want %q", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestWeatherDataUVIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		index   *float64
+		band    string
+	}{
+		{"missing", `{"time":"2026-06-21T12:00"}`, nil, ""},
+		{"null", `{"time":"2026-06-21T12:00","uv_index":null}`, nil, ""},
+		{"zero", `{"time":"2026-06-21T12:00","uv_index":0}`, ptr(0.0), "Low"},
+		{"high", `{"time":"2026-06-21T12:00","uv_index":7.95}`, ptr(7.95), "High"},
+		{"negative", `{"time":"2026-06-21T12:00","uv_index":-1}`, ptr(-1.0), ""},
+	}
+	for _, tt := range tests {
+		var r OpenMeteoResponse
+		if err := json.Unmarshal([]byte(`{"current":`+tt.current+`}`), &r); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		w := r.toWeatherData()
+		switch {
+		case (w.UVIndex == nil) != (tt.index == nil):
+			t.Errorf("%s: UVIndex = %v, want %v", tt.name, w.UVIndex, tt.index)
+		case w.UVIndex != nil && *w.UVIndex != *tt.index:
+			t.Errorf("%s: UVIndex = %v, want %v", tt.name, *w.UVIndex, *tt.index)
+		}
+		if w.UVBand != tt.band {
+			t.Errorf("%s: UVBand = %q, want %q", tt.name, w.UVBand, tt.band)
+		}
+	}
+}
+
+// ptr returns a pointer to v
+func ptr[T any](v T) *T {
+	return &v
+}
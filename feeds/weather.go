@@ -0,0 +1,357 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// currentVariables are the current-conditions variables requested from Open-Meteo
+const currentVariables = "temperature_2m,apparent_temperature,weather_code,rain,snowfall,precipitation," +
+	"relative_humidity_2m,cloud_cover,wind_speed_10m,wind_direction_10m,wind_gusts_10m,pressure_msl"
+
+// Default Open-Meteo API hosts
+const (
+	defaultForecastBaseURL  = "https://api.open-meteo.com"
+	defaultArchiveBaseURL   = "https://archive-api.open-meteo.com"
+	defaultGeocodingBaseURL = "https://geocoding-api.open-meteo.com"
+)
+
+// WeatherData represents weather information
+type WeatherData struct {
+	Summary          string  `json:"summary"`
+	WeatherCode      int     `json:"weatherCode"`
+	TemperatureC     float64 `json:"temperatureC"`
+	FeelsLikeC       float64 `json:"feelsLikeC"`
+	RainMM           float64 `json:"rainMM"`
+	SnowfallCM       float64 `json:"snowfallCM"`
+	PrecipitationMM  float64 `json:"precipitationMM"` // rain, showers and snowfall water equivalent
+	HumidityPct      float64 `json:"humidityPct"`
+	CloudCoverPct    float64 `json:"cloudCoverPct"`
+	WindSpeedKmh     float64 `json:"windSpeedKmh"`
+	WindDirectionDeg float64 `json:"windDirectionDeg"` // direction the wind blows from, 0 = north
+	WindGustsKmh     float64 `json:"windGustsKmh"`
+	PressureHPa      float64 `json:"pressureHPa,omitempty"` // mean sea-level pressure; 0 if not reported
+	Latitude         float64 `json:"latitude,omitempty"`
+	Longitude        float64 `json:"longitude,omitempty"`
+	Timezone         string  `json:"timezone,omitempty"` // IANA zone of the location
+	Source           string  `json:"source,omitempty"`
+	Provider         string  `json:"provider,omitempty"` // the WeatherProvider that served the data
+
+	// ObservedAt is when the conditions were observed or forecast for, in
+	// the location's time zone; zero if the provider does not say
+	ObservedAt time.Time `json:"observedAt,omitzero"`
+
+	// ConditionSeverity classifies the conditions as one of the
+	// ConditionSeverity constants; see ClassifyConditions
+	ConditionSeverity string `json:"severity"`
+
+	// Icon names an icon for the conditions, one of the Icon constants, with
+	// day or night variants chosen by the sun at ObservedAt; see WeatherIcon
+	Icon string `json:"icon"`
+
+	// UVIndex and UVBand are set when the Client uses WithUVIndex
+	UVIndex *float64 `json:"uvIndex,omitempty"`
+	UVBand  string   `json:"uvBand,omitempty"`
+
+	// Imperial is set when the Client uses UnitsImperial
+	Imperial *ImperialWeather `json:"imperial,omitempty"`
+
+	// Precipitation and Wind are set when the Client uses
+	// WithPrecipitationUnit or WithWindUnit, and carry the unit they are in
+	Precipitation *PrecipitationAmounts `json:"precipitation,omitempty"`
+	Wind          *WindSpeeds           `json:"wind,omitempty"`
+}
+
+// Coordinate sources reported in WeatherData.Source
+const (
+	SourceCountryMap = "country-map"  // country code found in the coordinate map
+	SourceFallback   = "fallback"     // unknown country, default location used
+	SourceCityMap    = "city-map"     // city found in the city registry
+	SourceRegionMap  = "region-map"   // state or province found in the region registry
+	SourceExplicit   = "explicit"     // coordinates supplied by the caller
+	SourceGeocode    = "geocode-city" // place name resolved by the geocoder
+	SourceZIP        = "zip"          // ZIP or postal code resolved by the geocoder
+)
+
+// Coordinates represents latitude and longitude
+type Coordinates struct {
+	Lat float64
+	Lon float64
+}
+
+// Validate checks that the coordinates are within valid latitude/longitude ranges
+func (c Coordinates) Validate() error {
+	if math.IsNaN(c.Lat) || c.Lat < -90 || c.Lat > 90 || math.IsNaN(c.Lon) || c.Lon < -180 || c.Lon > 180 {
+		return fmt.Errorf("%w: %.4f,%.4f", ErrInvalidCoordinates, c.Lat, c.Lon)
+	}
+	return nil
+}
+
+// query returns the latitude/longitude query parameters for the coordinates
+func (c Coordinates) query() url.Values {
+	q := url.Values{}
+	q.Set("latitude", fmt.Sprintf("%.4f", c.Lat))
+	q.Set("longitude", fmt.Sprintf("%.4f", c.Lon))
+	return q
+}
+
+// OpenMeteoResponse represents the API response from Open-Meteo
+type OpenMeteoResponse struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	Timezone         string  `json:"timezone"`
+	UTCOffsetSeconds int     `json:"utc_offset_seconds"`
+	Current          struct {
+		Time                Timestamp `json:"time"`
+		Temperature         float64   `json:"temperature_2m"`
+		ApparentTemperature float64   `json:"apparent_temperature"`
+		WeatherCode         int       `json:"weather_code"`
+		Rain                float64   `json:"rain"`
+		Snowfall            float64   `json:"snowfall"`
+		Precipitation       float64   `json:"precipitation"`
+		RelativeHumidity    float64   `json:"relative_humidity_2m"`
+		CloudCover          float64   `json:"cloud_cover"`
+		WindSpeed           float64   `json:"wind_speed_10m"`
+		WindDirection       float64   `json:"wind_direction_10m"`
+		WindGusts           float64   `json:"wind_gusts_10m"`
+		PressureMSL         float64   `json:"pressure_msl"`
+		UVIndex             *float64  `json:"uv_index"` // only when requested
+	} `json:"current"`
+}
+
+// Guards naCountryCoordinates and weatherCodeDescriptions; always access the
+// maps through the accessor functions below
+var (
+	coordinatesMu  sync.RWMutex
+	descriptionsMu sync.RWMutex
+)
+
+// North America country coordinates (a major city in each), loaded from data/countries.json
+var naCountryCoordinates = mustLoadCountries()
+
+// Weather code to description mapping (WMO Weather interpretation codes)
+var weatherCodeDescriptions = map[int]string{
+	0:  "Clear sky",
+	1:  "Mainly clear",
+	2:  "Partly cloudy",
+	3:  "Overcast",
+	45: "Foggy",
+	48: "Depositing rime fog",
+	51: "Light drizzle",
+	53: "Moderate drizzle",
+	55: "Dense drizzle",
+	61: "Slight rain",
+	63: "Moderate rain",
+	65: "Heavy rain",
+	71: "Slight snow",
+	73: "Moderate snow",
+	75: "Heavy snow",
+	77: "Snow grains",
+	80: "Slight rain showers",
+	81: "Moderate rain showers",
+	82: "Violent rain showers",
+	85: "Slight snow showers",
+	86: "Heavy snow showers",
+	95: "Thunderstorm",
+	96: "Thunderstorm with slight hail",
+	99: "Thunderstorm with heavy hail",
+}
+
+// FetchWeather fetches weather data for a given country using the default Client
+func FetchWeather(country string) (*WeatherData, error) {
+	return defaultClient.FetchWeather(country)
+}
+
+// FetchWeatherContext is FetchWeather with a caller-supplied context
+func FetchWeatherContext(ctx context.Context, country string) (*WeatherData, error) {
+	return defaultClient.FetchWeatherContext(ctx, country)
+}
+
+// FetchWeather fetches weather data for a given country using Open-Meteo API
+func (c *Client) FetchWeather(country string) (*WeatherData, error) {
+	return c.FetchWeatherContext(context.Background(), country)
+}
+
+// FetchWeatherContext fetches weather data for a given country using Open-Meteo API.
+// The request is cancelled when ctx is done; the error then wraps ctx.Err().
+func (c *Client) FetchWeatherContext(ctx context.Context, country string) (*WeatherData, error) {
+	coords, source, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchCurrent(ctx, coords, source)
+}
+
+// FetchWeatherByCoords fetches weather for explicit coordinates using the default Client
+func FetchWeatherByCoords(lat, lon float64) (*WeatherData, error) {
+	return defaultClient.FetchWeatherByCoords(lat, lon)
+}
+
+// FetchWeatherByCoords fetches weather for explicit coordinates, bypassing the
+// country lookup. It returns ErrInvalidCoordinates for out-of-range values.
+func (c *Client) FetchWeatherByCoords(lat, lon float64) (*WeatherData, error) {
+	coords := Coordinates{Lat: lat, Lon: lon}
+	if err := coords.Validate(); err != nil {
+		return nil, err
+	}
+	return c.fetchCurrent(context.Background(), coords, SourceExplicit)
+}
+
+// fetchCurrent fetches current conditions at coords from the Client's provider,
+// recording source on the result
+func (c *Client) fetchCurrent(ctx context.Context, coords Coordinates, source string) (w *WeatherData, err error) {
+	if source == SourceFallback {
+		c.log().WarnContext(ctx, "unknown country, using fallback location", "lat", coords.Lat, "lon", coords.Lon)
+	}
+	if _, ok := ctx.Value(spanLocationKey{}).(string); !ok {
+		ctx = withSpanLocation(ctx, locationKey(Location{Coordinates: coords}))
+	}
+	ctx, span := c.startSpan(ctx, spanWeather, SpanAttribute{attrProvider, providerName(c.provider)})
+	defer func() { endSpan(span, err) }()
+
+	w, err = c.provider.Fetch(ctx, coords)
+	if err != nil {
+		return nil, err
+	}
+	w.Source = source
+	c.finishWeather(w)
+	return w, nil
+}
+
+// openMeteoCurrent fetches current conditions at coords from Open-Meteo
+func (c *Client) openMeteoCurrent(ctx context.Context, coords Coordinates) (*WeatherData, error) {
+	// Build Open-Meteo API query
+	q := coords.query()
+	q.Set("current", c.currentVariables())
+	q.Set("timezone", "auto")
+
+	var apiResp OpenMeteoResponse
+	if err := c.getOpenMeteo(ctx, c.forecastBaseURL+"/v1/forecast", q, &apiResp); err != nil {
+		return nil, err
+	}
+	if err := apiResp.validate(); err != nil {
+		return nil, err
+	}
+
+	return apiResp.toWeatherData(), nil
+}
+
+// validate rejects responses that decoded cleanly but are not usable, such as
+// an error page or proxy response that happens to be valid JSON
+func (r *OpenMeteoResponse) validate() error {
+	if r.Current.Time.IsZero() {
+		return withKind(ErrDecode, errors.New("weather response has no current conditions"))
+	}
+	if err := (Coordinates{Lat: r.Latitude, Lon: r.Longitude}).Validate(); err != nil {
+		return withKind(ErrDecode, fmt.Errorf("weather response: %w", err))
+	}
+	return nil
+}
+
+// toWeatherData converts the current block of a response into WeatherData
+func (r *OpenMeteoResponse) toWeatherData() *WeatherData {
+	w := &WeatherData{
+		Summary:          describeWeatherCode(r.Current.WeatherCode),
+		WeatherCode:      r.Current.WeatherCode,
+		TemperatureC:     r.Current.Temperature,
+		FeelsLikeC:       r.Current.ApparentTemperature,
+		RainMM:           r.Current.Rain,
+		SnowfallCM:       r.Current.Snowfall,
+		PrecipitationMM:  r.Current.Precipitation,
+		HumidityPct:      r.Current.RelativeHumidity,
+		CloudCoverPct:    r.Current.CloudCover,
+		WindSpeedKmh:     r.Current.WindSpeed,
+		WindDirectionDeg: r.Current.WindDirection,
+		WindGustsKmh:     r.Current.WindGusts,
+		PressureHPa:      r.Current.PressureMSL,
+		Latitude:         r.Latitude,
+		Longitude:        r.Longitude,
+		Timezone:         r.Timezone,
+	}
+	if t, err := r.Current.Time.In(apiLocation(r.Timezone, r.UTCOffsetSeconds)); err == nil {
+		w.ObservedAt = t
+	}
+	if uv := r.Current.UVIndex; uv != nil {
+		w.UVIndex = uv
+		w.UVBand = UVBand(*uv)
+	}
+	return w
+}
+
+// lookupCountry returns the registered coordinates for a country, ignoring case
+func lookupCountry(country string) (Coordinates, bool) {
+	coordinatesMu.RLock()
+	defer coordinatesMu.RUnlock()
+
+	coords, ok := naCountryCoordinates[strings.ToUpper(country)]
+	return coords, ok
+}
+
+// SupportedCountries returns the registered country codes in sorted order.
+// Callers can validate input against it before fetching, since unknown
+// countries otherwise fall back to New York (see WithNoFallback).
+func SupportedCountries() []string {
+	coordinatesMu.RLock()
+	defer coordinatesMu.RUnlock()
+	return slices.Sorted(maps.Keys(naCountryCoordinates))
+}
+
+// resolveCountry returns the coordinates for a country and how they were
+// found. Unknown countries default to New York unless the Client was created
+// WithNoFallback.
+func (c *Client) resolveCountry(country string) (Coordinates, string, error) {
+	if coords, ok := lookupCountry(country); ok {
+		return coords, SourceCountryMap, nil
+	}
+	if c.noFallback {
+		return Coordinates{}, "", fmt.Errorf("%w: %q", ErrUnknownCountry, country)
+	}
+	// Default to New York if country not found
+	coords, _ := lookupCountry("US")
+	return coords, SourceFallback, nil
+}
+
+// RegisterCountry adds or replaces the coordinates used for a country code.
+// Codes are case-insensitive and stored upper-case.
+func RegisterCountry(country string, coords Coordinates) {
+	coordinatesMu.Lock()
+	defer coordinatesMu.Unlock()
+	naCountryCoordinates[strings.ToUpper(country)] = coords
+}
+
+// CountryLocation returns the Location of a registered country, without
+// falling back to New York
+func CountryLocation(country string) (Location, error) {
+	coords, ok := lookupCountry(country)
+	if !ok {
+		return Location{}, fmt.Errorf("%w: %q", ErrUnknownCountry, country)
+	}
+	return Location{Country: strings.ToUpper(country), Coordinates: coords}, nil
+}
+
+// describeWeatherCode converts a WMO weather code to a description
+func describeWeatherCode(code int) string {
+	descriptionsMu.RLock()
+	defer descriptionsMu.RUnlock()
+
+	description, ok := weatherCodeDescriptions[code]
+	if !ok {
+		return "Unknown"
+	}
+	return description
+}
+
+// SetWeatherCodeDescription overrides the description for a WMO weather code
+func SetWeatherCodeDescription(code int, description string) {
+	descriptionsMu.Lock()
+	defer descriptionsMu.Unlock()
+	weatherCodeDescriptions[code] = description
+}
@@ -0,0 +1,127 @@
+package feeds
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// currentBody is an Open-Meteo current-conditions response for New York
+const currentBody = `{"latitude":40.71,"longitude":-74.01,"timezone":"America/New_York","utc_offset_seconds":-14400,
+"current":{"time":"2026-06-21T12:00","temperature_2m":21.5,"apparent_temperature":22.1,"weather_code":3,
+"rain":0,"snowfall":0,"precipitation":0,"relative_humidity_2m":64,"cloud_cover":90,"wind_speed_10m":14.8,
+"wind_direction_10m":225,"wind_gusts_10m":31.3,"pressure_msl":1012.4}}`
+
+// currentServer serves currentBody for every /v1/forecast request
+func currentServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/forecast" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, currentBody)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestRegisterWhileFetching changes the country and weather code registries
+// while weather is fetched; run it with -race
+func TestRegisterWhileFetching(t *testing.T) {
+	srv := currentServer(t)
+	c := NewClient(WithBaseURL(srv.URL), WithNoFallback())
+	RegisterCountry("zz", Coordinates{Lat: 40.71, Lon: -74.01})
+	overcast := describeWeatherCode(3)
+	t.Cleanup(func() {
+		coordinatesMu.Lock()
+		delete(naCountryCoordinates, "ZZ")
+		coordinatesMu.Unlock()
+		SetWeatherCodeDescription(3, overcast)
+	})
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				RegisterCountry("zz", Coordinates{Lat: 40 + float64(i)/10, Lon: -74 + float64(j)/100})
+				SetWeatherCodeDescription(3, overcast)
+				_ = SupportedCountries()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				w, err := c.FetchWeather("ZZ")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if w.Summary != overcast {
+					t.Errorf("Summary = %q, want %q", w.Summary, overcast)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func FuzzDecodeOpenMeteoResponse(f *testing.F) {
+	for _, seed := range []string{
+		``,
+		`{}`,
+		`null`,
+		`[]`,
+		`{"latitude":40.7,"longitude":-74,"timezone":"America/New_York","utc_offset_seconds":-14400,` +
+			`"current":{"time":"2026-06-21T12:00","temperature_2m":21.5,"weather_code":3,"uv_index":6.2}}`,
+		`{"current":{"time":1782057600,"temperature_2m":"hot"}}`,
+		`{"latitude":400,"longitude":-74,"current":{"time":"2026-06-21T12:00"}}`,
+		`[{"latitude":40.7,"longitude":-74,"current":{"time":"2026-06-21T12:00"}},{"latitude":45.4}]`,
+		`{"hourly":{"time":null,"temperature_2m":null,"apparent_temperature":null,"precipitation":null,"weather_code":null}}`,
+		`{"hourly":{"time":["2026-06-21T00:00","2026-06-21T01:00"],"temperature_2m":[20],"weather_code":[0,1,2]}}`,
+		`{"daily":{"time":null,"temperature_2m_max":null,"temperature_2m_min":null}}`,
+		`{"daily":{"time":["2026-06-21"],"temperature_2m_max":[25,26],"temperature_2m_min":[]}}`,
+		`{"current":{"time":"not a time"},"hourly":{"time":["2026-13-45T99:00"],"temperature_2m":[1],` +
+			`"apparent_temperature":[1],"precipitation":[0],"weather_code":[0]}}`,
+		`[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var current OpenMeteoResponse
+		if json.Unmarshal(data, &current) == nil && current.validate() == nil {
+			if w := current.toWeatherData(); w == nil {
+				t.Fatal("toWeatherData returned nil for a valid response")
+			}
+		}
+
+		var multi MultiLocationResponse
+		if json.Unmarshal(data, &multi) == nil && multi.Validate() == nil {
+			if got := multi.ToWeatherData(); len(got) != len(multi) {
+				t.Fatalf("ToWeatherData returned %d locations for %d", len(got), len(multi))
+			}
+		}
+
+		var hourly OpenMeteoHourlyResponse
+		if json.Unmarshal(data, &hourly) == nil {
+			if hours, err := hourly.toHourlyForecasts(); err == nil && len(hours) != len(hourly.Hourly.Time) {
+				t.Fatalf("toHourlyForecasts returned %d hours for %d times", len(hours), len(hourly.Hourly.Time))
+			}
+		}
+
+		var daily OpenMeteoDailyResponse
+		if json.Unmarshal(data, &daily) == nil {
+			if days, err := daily.toDailyForecasts(); err == nil && len(days) != len(daily.Daily.Time) {
+				t.Fatalf("toDailyForecasts returned %d days for %d dates", len(days), len(daily.Daily.Time))
+			}
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package feeds
+
+import (
+	"fmt"
+	"strings"
+)
+
+// widgetInnerWidth is the number of terminal columns between the widget borders
+const widgetInnerWidth = 30
+
+// Emoji returns an emoji representing the weather code
+func (w WeatherData) Emoji() string {
+	switch c := w.WeatherCode; {
+	case c == 0:
+		return "☀️"
+	case c == 1:
+		return "🌤️"
+	case c == 2:
+		return "⛅"
+	case c == 3:
+		return "☁️"
+	case c == 45 || c == 48:
+		return "🌫️"
+	case c >= 51 && c <= 55:
+		return "🌦️"
+	case (c >= 61 && c <= 65) || (c >= 80 && c <= 82):
+		return "🌧️"
+	case (c >= 71 && c <= 77) || c == 85 || c == 86:
+		return "🌨️"
+	case c >= 95 && c <= 99:
+		return "⛈️"
+	default:
+		return "🌡️"
+	}
+}
+
+// RenderWidget renders the reading as a fixed-width box for terminal output:
+//
+//	┌──────────────────────────────┐
+//	│ ⛅  Partly cloudy            │
+//	│ 21.4°C   feels like 20.9°C   │
+//	└──────────────────────────────┘
+func (w WeatherData) RenderWidget() string {
+	border := strings.Repeat("─", widgetInnerWidth)
+	lines := []string{
+		"┌" + border + "┐",
+		widgetLine(w.Emoji() + "  " + w.Summary),
+		widgetLine(fmt.Sprintf("%.1f°C   feels like %.1f°C", w.TemperatureC, w.FeelsLikeC)),
+		"└" + border + "┘",
+	}
+	return strings.Join(lines, "\n")
+}
+
+// widgetLine pads or truncates text to fit between the widget borders
+func widgetLine(text string) string {
+	avail := widgetInnerWidth - 2 // one space of padding each side
+	if textWidth(text) > avail {
+		var b strings.Builder
+		width := 0
+		for _, r := range text {
+			if width+runeWidth(r) > avail-1 { // leave room for the ellipsis
+				break
+			}
+			b.WriteRune(r)
+			width += runeWidth(r)
+		}
+		text = b.String() + "…"
+	}
+	return "│ " + text + strings.Repeat(" ", avail-textWidth(text)) + " │"
+}
+
+// textWidth approximates how many terminal columns a string occupies
+func textWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// runeWidth approximates how many terminal columns a rune occupies
+func runeWidth(r rune) int {
+	switch {
+	case r == 0xFE0F || r == 0x200D: // variation selector, zero-width joiner
+		return 0
+	case r >= 0x1F000, r >= 0x2600 && r <= 0x27BF:
+		return 2
+	default:
+		return 1
+	}
+}
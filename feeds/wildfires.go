@@ -0,0 +1,288 @@
+package feeds
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Wildfire source hosts
+const (
+	// defaultNIFCBaseURL hosts the US National Interagency Fire Center's
+	// current incident layer (WFIGS)
+	defaultNIFCBaseURL = "https://services3.arcgis.com/T4QMspbfLg3qTGWY/arcgis"
+	// defaultCWFISBaseURL is the Canadian Wildland Fire Information System host
+	defaultCWFISBaseURL = "https://cwfis.cfs.nrcan.gc.ca"
+)
+
+// acresToHectares converts the acreage NIFC reports to hectares
+const acresToHectares = 0.40468564224
+
+// smokeAQIThreshold is the US AQI above which nearby fires are flagged as a
+// likely smoke source ("Unhealthy for Sensitive Groups" and worse)
+const smokeAQIThreshold = 100
+
+// WithNIFCBaseURL points US wildfire requests at another ArcGIS host
+func WithNIFCBaseURL(base string) Option {
+	return func(c *Client) {
+		c.nifcBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// WithCWFISBaseURL points Canadian wildfire requests at another host
+func WithCWFISBaseURL(base string) Option {
+	return func(c *Client) {
+		c.cwfisBaseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// Wildfire is an active wildfire incident
+type Wildfire struct {
+	Name           string    `json:"name"`
+	Source         string    `json:"source"` // "NIFC" or "CWFIS"
+	Latitude       float64   `json:"latitude"`
+	Longitude      float64   `json:"longitude"`
+	AreaHa         float64   `json:"areaHa"`
+	ContainmentPct *float64  `json:"containmentPct,omitempty"` // nil when the source does not report it
+	Status         string    `json:"status,omitempty"`         // Canadian stage of control, e.g. "Out of control"
+	Discovered     time.Time `json:"discovered,omitzero"`
+	DistanceKm     float64   `json:"distanceKm"`
+}
+
+// WildfireSmoke combines nearby fires with current air quality so smoke can
+// be cross-referenced with advisories
+type WildfireSmoke struct {
+	AirQuality *AirQualityData `json:"airQuality,omitempty"`
+	Fires      []Wildfire      `json:"fires"`
+	// SmokeLikely is set when fires are burning nearby and the AQI is above 100
+	SmokeLikely bool `json:"smokeLikely"`
+}
+
+// NIFCIncidentsResponse represents a GeoJSON query response from the WFIGS current incidents layer
+type NIFCIncidentsResponse struct {
+	Features []struct {
+		Properties struct {
+			IncidentName          string   `json:"IncidentName"`
+			IncidentSize          *float64 `json:"IncidentSize"` // acres
+			PercentContained      *float64 `json:"PercentContained"`
+			FireDiscoveryDateTime int64    `json:"FireDiscoveryDateTime"` // Unix milliseconds
+		} `json:"properties"`
+		Geometry struct {
+			Coordinates []float64 `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// CWFISActiveFiresResponse represents the CWFIS active fires WFS layer as GeoJSON
+type CWFISActiveFiresResponse struct {
+	Features []struct {
+		Properties struct {
+			FireName       string  `json:"firename"`
+			Agency         string  `json:"agency"`
+			Lat            float64 `json:"lat"`
+			Lon            float64 `json:"lon"`
+			Hectares       float64 `json:"hectares"`
+			StageOfControl string  `json:"stage_of_control"`
+			StartDate      string  `json:"startdate"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// cwfisStages names Canadian stage-of-control codes
+var cwfisStages = map[string]string{
+	"OC":  "Out of control",
+	"BH":  "Being held",
+	"UC":  "Under control",
+	"OUT": "Out",
+}
+
+// FetchWildfires fetches wildfires near a country using the default Client
+func FetchWildfires(country string, radiusKm float64) ([]Wildfire, error) {
+	return defaultClient.FetchWildfires(country, radiusKm)
+}
+
+// FetchWildfires fetches active wildfires within radiusKm of a country's coordinates
+func (c *Client) FetchWildfires(country string, radiusKm float64) ([]Wildfire, error) {
+	coords, _, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchWildfires(context.Background(), coords, radiusKm)
+}
+
+// FetchWildfiresByCoords fetches wildfires near coordinates using the default Client
+func FetchWildfiresByCoords(lat, lon, radiusKm float64) ([]Wildfire, error) {
+	return defaultClient.FetchWildfiresByCoords(lat, lon, radiusKm)
+}
+
+// FetchWildfiresByCoords fetches active wildfires within radiusKm of the given
+// coordinates from the US (NIFC) and Canadian (CWFIS) incident feeds, nearest
+// first. A failing feed is skipped as long as the other succeeds. Mexican
+// fires are not covered.
+func (c *Client) FetchWildfiresByCoords(lat, lon, radiusKm float64) ([]Wildfire, error) {
+	coords := Coordinates{Lat: lat, Lon: lon}
+	if err := coords.Validate(); err != nil {
+		return nil, err
+	}
+	return c.fetchWildfires(context.Background(), coords, radiusKm)
+}
+
+// FetchWildfireSmoke fetches nearby fires and air quality using the default Client
+func FetchWildfireSmoke(country string, radiusKm float64) (*WildfireSmoke, error) {
+	return defaultClient.FetchWildfireSmoke(country, radiusKm)
+}
+
+// FetchWildfireSmoke fetches active wildfires within radiusKm of a country's
+// coordinates together with current air quality there. Either half may be
+// missing if its upstream fails; an error is returned only if both do.
+func (c *Client) FetchWildfireSmoke(country string, radiusKm float64) (*WildfireSmoke, error) {
+	coords, _, err := c.resolveCountry(country)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchWildfireSmoke(context.Background(), coords, radiusKm)
+}
+
+// fetchWildfireSmoke fetches fires and air quality at coords concurrently
+func (c *Client) fetchWildfireSmoke(ctx context.Context, coords Coordinates, radiusKm float64) (*WildfireSmoke, error) {
+	var (
+		smoke          WildfireSmoke
+		fireErr, aqErr error
+		wg             sync.WaitGroup
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		smoke.Fires, fireErr = c.fetchWildfires(ctx, coords, radiusKm)
+	}()
+	go func() {
+		defer wg.Done()
+		smoke.AirQuality, aqErr = c.fetchAirQuality(ctx, coords)
+	}()
+	wg.Wait()
+
+	if fireErr != nil && aqErr != nil {
+		return nil, errors.Join(fireErr, aqErr)
+	}
+	smoke.SmokeLikely = len(smoke.Fires) > 0 && smoke.AirQuality != nil && smoke.AirQuality.AQI > smokeAQIThreshold
+	return &smoke, nil
+}
+
+// fetchWildfires merges the US and Canadian incident feeds around coords
+func (c *Client) fetchWildfires(ctx context.Context, coords Coordinates, radiusKm float64) ([]Wildfire, error) {
+	var (
+		us, ca       []Wildfire
+		usErr, caErr error
+		wg           sync.WaitGroup
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		us, usErr = c.fetchNIFCFires(ctx, coords, radiusKm)
+	}()
+	go func() {
+		defer wg.Done()
+		ca, caErr = c.fetchCWFISFires(ctx, coords, radiusKm)
+	}()
+	wg.Wait()
+
+	if usErr != nil && caErr != nil {
+		return nil, errors.Join(usErr, caErr)
+	}
+	fires := append(us, ca...)
+	slices.SortFunc(fires, func(a, b Wildfire) int { return cmp.Compare(a.DistanceKm, b.DistanceKm) })
+	return fires, nil
+}
+
+// fetchNIFCFires queries current US wildfire incidents within radiusKm of coords
+func (c *Client) fetchNIFCFires(ctx context.Context, coords Coordinates, radiusKm float64) ([]Wildfire, error) {
+	q := url.Values{}
+	q.Set("where", "IncidentTypeCategory='WF'")
+	q.Set("outFields", "IncidentName,IncidentSize,PercentContained,FireDiscoveryDateTime")
+	q.Set("geometry", fmt.Sprintf("%.4f,%.4f", coords.Lon, coords.Lat))
+	q.Set("geometryType", "esriGeometryPoint")
+	q.Set("inSR", "4326")
+	q.Set("outSR", "4326")
+	q.Set("spatialRel", "esriSpatialRelIntersects")
+	q.Set("distance", strconv.FormatFloat(radiusKm, 'f', -1, 64))
+	q.Set("units", "esriSRUnit_Kilometer")
+	q.Set("f", "geojson")
+
+	var apiResp NIFCIncidentsResponse
+	endpoint := c.nifcBaseURL + "/rest/services/WFIGS_Incident_Locations_Current/FeatureServer/0/query"
+	if err := c.getJSON(ctx, endpoint, q, &apiResp); err != nil {
+		return nil, fmt.Errorf("NIFC: %w", err)
+	}
+
+	fires := make([]Wildfire, 0, len(apiResp.Features))
+	for _, f := range apiResp.Features {
+		if len(f.Geometry.Coordinates) < 2 {
+			continue
+		}
+		p := f.Properties
+		fire := Wildfire{
+			Name:           p.IncidentName,
+			Source:         "NIFC",
+			Latitude:       f.Geometry.Coordinates[1],
+			Longitude:      f.Geometry.Coordinates[0],
+			ContainmentPct: p.PercentContained,
+		}
+		if p.IncidentSize != nil {
+			fire.AreaHa = roundTo(*p.IncidentSize*acresToHectares, 1)
+		}
+		if p.FireDiscoveryDateTime > 0 {
+			fire.Discovered = time.UnixMilli(p.FireDiscoveryDateTime).UTC()
+		}
+		fire.DistanceKm = roundTo(distanceKm(coords, Coordinates{Lat: fire.Latitude, Lon: fire.Longitude}), 1)
+		fires = append(fires, fire)
+	}
+	return fires, nil
+}
+
+// fetchCWFISFires reads the Canadian active fires layer and keeps those within
+// radiusKm of coords. The layer is fetched whole so every location shares one
+// cached response.
+func (c *Client) fetchCWFISFires(ctx context.Context, coords Coordinates, radiusKm float64) ([]Wildfire, error) {
+	q := url.Values{}
+	q.Set("service", "WFS")
+	q.Set("version", "2.0.0")
+	q.Set("request", "GetFeature")
+	q.Set("typeNames", "public:activefires_current")
+	q.Set("outputFormat", "application/json")
+
+	var apiResp CWFISActiveFiresResponse
+	if err := c.getJSON(ctx, c.cwfisBaseURL+"/geoserver/public/ows", q, &apiResp); err != nil {
+		return nil, fmt.Errorf("CWFIS: %w", err)
+	}
+
+	var fires []Wildfire
+	for _, f := range apiResp.Features {
+		p := f.Properties
+		pos := Coordinates{Lat: p.Lat, Lon: p.Lon}
+		d := distanceKm(coords, pos)
+		if d > radiusKm {
+			continue
+		}
+		fire := Wildfire{
+			Name:       cmp.Or(p.FireName, p.Agency),
+			Source:     "CWFIS",
+			Latitude:   p.Lat,
+			Longitude:  p.Lon,
+			AreaHa:     p.Hectares,
+			Status:     cmp.Or(cwfisStages[strings.ToUpper(p.StageOfControl)], p.StageOfControl),
+			DistanceKm: roundTo(d, 1),
+		}
+		if t, err := time.Parse(time.DateTime, p.StartDate); err == nil {
+			fire.Discovered = t
+		}
+		fires = append(fires, fire)
+	}
+	return fires, nil
+}
@@ -0,0 +1,332 @@
+// Package health probes upstream providers in the background and reports
+// whether each is healthy, degraded or down
+package health
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"reef-na/feeds"
+)
+
+// Status is the health of a provider, or of all of them
+type Status string
+
+// Statuses, from best to worst
+const (
+	Unknown  Status = "unknown"  // not probed yet
+	Healthy  Status = "healthy"  // the last probe succeeded
+	Degraded Status = "degraded" // recent probes failed, fewer than the down threshold in a row
+	Down     Status = "down"     // the down threshold of probes failed in a row
+)
+
+// Defaults of a Checker
+const (
+	defaultInterval  = time.Minute
+	defaultTimeout   = 10 * time.Second
+	defaultDownAfter = 3
+)
+
+// Probe checks one provider
+type Probe interface {
+	// Name identifies the provider in reports
+	Name() string
+	// Check returns an error if the provider cannot serve requests. The
+	// context carries the Checker's timeout.
+	Check(ctx context.Context) error
+}
+
+// funcProbe adapts a function to Probe
+type funcProbe struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Name returns the probe's name
+func (p funcProbe) Name() string { return p.name }
+
+// Check calls the probe's function
+func (p funcProbe) Check(ctx context.Context) error { return p.fn(ctx) }
+
+// NewProbe returns a Probe that calls fn
+func NewProbe(name string, fn func(ctx context.Context) error) Probe {
+	return funcProbe{name: name, fn: fn}
+}
+
+// WeatherProbe probes c's current-conditions provider by fetching the
+// weather of a country, bypassing the response cache
+func WeatherProbe(c *feeds.Client, country string) Probe {
+	return NewProbe("weather", func(ctx context.Context) error {
+		_, err := c.FetchWeatherContext(feeds.BypassCache(ctx), country)
+		return err
+	})
+}
+
+// FeedProbe probes a feed's upstream by fetching it for loc, bypassing the
+// response cache. The probe has the feed's name.
+func FeedProbe(f feeds.Feed, loc feeds.Location) Probe {
+	return NewProbe(f.Name(), func(ctx context.Context) error {
+		_, err := f.Fetch(feeds.BypassCache(ctx), loc)
+		return err
+	})
+}
+
+// ProviderStatus is the health of one provider
+type ProviderStatus struct {
+	Name                string        `json:"name"`
+	Status              Status        `json:"status"`
+	LastSuccess         time.Time     `json:"lastSuccess,omitzero"`
+	LastFailure         time.Time     `json:"lastFailure,omitzero"`
+	LastError           string        `json:"lastError,omitempty"`
+	ConsecutiveFailures int           `json:"consecutiveFailures"`
+	Latency             time.Duration `json:"-"` // of the last probe, written as latencyMs
+	CheckedAt           time.Time     `json:"checkedAt,omitzero"`
+}
+
+// MarshalJSON writes Latency in milliseconds
+func (p ProviderStatus) MarshalJSON() ([]byte, error) {
+	type plain ProviderStatus
+	return json.Marshal(struct {
+		plain
+		Latency int64 `json:"latencyMs"`
+	}{plain(p), p.Latency.Milliseconds()})
+}
+
+// Report is the health of every provider of a Checker
+type Report struct {
+	// Status is Healthy when every probed provider is, Down when every one
+	// is, Degraded otherwise and Unknown before the first probe
+	Status    Status           `json:"status"`
+	Providers []ProviderStatus `json:"providers"`
+}
+
+// Option configures a Checker
+type Option func(*Checker)
+
+// WithInterval sets how often providers are probed; the default is 1m
+func WithInterval(d time.Duration) Option {
+	return func(c *Checker) {
+		if d > 0 {
+			c.interval = d
+		}
+	}
+}
+
+// WithTimeout bounds each probe; the default is 10s
+func WithTimeout(d time.Duration) Option {
+	return func(c *Checker) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// WithDownAfter sets how many probes of a provider must fail in a row
+// before it is down rather than degraded; the default is 3
+func WithDownAfter(n int) Option {
+	return func(c *Checker) {
+		c.downAfter = max(n, 1)
+	}
+}
+
+// WithProbes adds probes to the Checker
+func WithProbes(probes ...Probe) Option {
+	return func(c *Checker) {
+		c.probes = append(c.probes, probes...)
+	}
+}
+
+// WithOnChange sets a function called, from the probing goroutine, when a
+// provider's status changes
+func WithOnChange(fn func(ProviderStatus)) Option {
+	return func(c *Checker) {
+		c.onChange = fn
+	}
+}
+
+// Checker probes providers periodically and keeps their latest status. It
+// is safe for concurrent use.
+type Checker struct {
+	interval  time.Duration
+	timeout   time.Duration
+	downAfter int
+	onChange  func(ProviderStatus)
+
+	mu     sync.RWMutex
+	probes []Probe
+	status map[string]*ProviderStatus
+}
+
+// NewChecker creates a Checker. Probes run once Run is started.
+func NewChecker(options ...Option) *Checker {
+	c := &Checker{
+		interval:  defaultInterval,
+		timeout:   defaultTimeout,
+		downAfter: defaultDownAfter,
+		status:    make(map[string]*ProviderStatus),
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	for _, p := range c.probes {
+		c.status[p.Name()] = &ProviderStatus{Name: p.Name(), Status: Unknown}
+	}
+	return c
+}
+
+// Add adds a probe, replacing any with the same name. It is first run at
+// Run's next round.
+func (c *Checker) Add(p Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.probes = slices.DeleteFunc(c.probes, func(q Probe) bool { return q.Name() == p.Name() })
+	c.probes = append(c.probes, p)
+	c.status[p.Name()] = &ProviderStatus{Name: p.Name(), Status: Unknown}
+}
+
+// Remove stops probing the named provider and drops it from reports
+func (c *Checker) Remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.probes = slices.DeleteFunc(c.probes, func(p Probe) bool { return p.Name() == name })
+	delete(c.status, name)
+}
+
+// Run probes every provider right away and then at each interval until ctx
+// is done, and returns ctx's error
+func (c *Checker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.CheckNow(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckNow probes every provider concurrently and returns the resulting report
+func (c *Checker) CheckNow(ctx context.Context) Report {
+	c.mu.RLock()
+	probes := slices.Clone(c.probes)
+	c.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, p := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.check(ctx, p)
+		}()
+	}
+	wg.Wait()
+	return c.Report()
+}
+
+// check runs one probe and records its result
+func (c *Checker) check(ctx context.Context, p Probe) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	start := time.Now()
+	err := runProbe(ctx, p)
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+		return // shutting down, not the provider's fault
+	}
+	now := time.Now()
+
+	c.mu.Lock()
+	s, ok := c.status[p.Name()]
+	if !ok {
+		c.mu.Unlock()
+		return // replaced or removed while probing
+	}
+	previous := s.Status
+	s.CheckedAt, s.Latency = now, now.Sub(start)
+	if err == nil {
+		s.LastSuccess, s.ConsecutiveFailures, s.Status = now, 0, Healthy
+	} else {
+		s.LastFailure, s.LastError = now, err.Error()
+		s.ConsecutiveFailures++
+		s.Status = Degraded
+		if s.ConsecutiveFailures >= c.downAfter {
+			s.Status = Down
+		}
+	}
+	changed := *s
+	c.mu.Unlock()
+
+	if changed.Status != previous && c.onChange != nil {
+		c.onChange(changed)
+	}
+}
+
+// runProbe calls p, turning a panic into an error
+func runProbe(ctx context.Context, p Probe) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("probe %s panicked: %v", p.Name(), r)
+		}
+	}()
+	return p.Check(ctx)
+}
+
+// Provider returns the status of one provider
+func (c *Checker) Provider(name string) (ProviderStatus, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.status[name]
+	if !ok {
+		return ProviderStatus{}, false
+	}
+	return *s, true
+}
+
+// Report returns the latest status of every provider, sorted by name
+func (c *Checker) Report() Report {
+	c.mu.RLock()
+	providers := make([]ProviderStatus, 0, len(c.status))
+	for _, s := range c.status {
+		providers = append(providers, *s)
+	}
+	c.mu.RUnlock()
+	slices.SortFunc(providers, func(a, b ProviderStatus) int { return cmp.Compare(a.Name, b.Name) })
+
+	counts := make(map[Status]int)
+	for _, p := range providers {
+		counts[p.Status]++
+	}
+	probed := len(providers) - counts[Unknown]
+	r := Report{Status: Degraded, Providers: providers}
+	switch {
+	case probed == 0:
+		r.Status = Unknown
+	case counts[Healthy] == probed:
+		r.Status = Healthy
+	case counts[Down] == probed:
+		r.Status = Down
+	}
+	return r
+}
+
+// ServeHTTP serves the report as JSON, with status 503 when every provider
+// is down or none has been probed yet
+func (c *Checker) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	r := c.Report()
+	code := http.StatusOK
+	if r.Status == Down || r.Status == Unknown {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(r)
+}
@@ -0,0 +1,164 @@
+package grpcapi
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"reef-na/feeds"
+	pw "reef-na/internal/protowire"
+)
+
+// Field numbers are those of proto/reef/v1/reef.proto
+
+// locationRequest is reef.v1.LocationRequest
+type locationRequest struct {
+	country string
+}
+
+func decodeLocationRequest(b []byte) (locationRequest, error) {
+	var req locationRequest
+	err := pw.Fields(b, func(r *pw.Reader, num, wire int) (bool, error) {
+		if num != 1 || wire != pw.Bytes {
+			return false, nil
+		}
+		v, err := r.Bytes()
+		req.country = string(v)
+		return true, err
+	})
+	return req, err
+}
+
+// feedRequest is reef.v1.FeedRequest
+type feedRequest struct {
+	feed, country string
+}
+
+func decodeFeedRequest(b []byte) (feedRequest, error) {
+	var req feedRequest
+	err := pw.Fields(b, func(r *pw.Reader, num, wire int) (bool, error) {
+		if wire != pw.Bytes || (num != 1 && num != 2) {
+			return false, nil
+		}
+		v, err := r.Bytes()
+		if num == 1 {
+			req.feed = string(v)
+		} else {
+			req.country = string(v)
+		}
+		return true, err
+	})
+	return req, err
+}
+
+// watchFeedsRequest is reef.v1.WatchFeedsRequest
+type watchFeedsRequest struct {
+	feeds, countries []string
+}
+
+func decodeWatchFeedsRequest(b []byte) (watchFeedsRequest, error) {
+	var req watchFeedsRequest
+	err := pw.Fields(b, func(r *pw.Reader, num, wire int) (bool, error) {
+		if wire != pw.Bytes || (num != 1 && num != 2) {
+			return false, nil
+		}
+		v, err := r.Bytes()
+		if num == 1 {
+			req.feeds = append(req.feeds, string(v))
+		} else {
+			req.countries = append(req.countries, string(v))
+		}
+		return true, err
+	})
+	return req, err
+}
+
+// encodeWeatherData encodes reef.v1.WeatherData
+func encodeWeatherData(w *feeds.WeatherData, fetchedAt time.Time) []byte {
+	var b []byte
+	b = pw.AppendString(b, 1, w.Summary)
+	b = pw.AppendVarint(b, 2, uint64(int64(w.WeatherCode)))
+	b = pw.AppendDouble(b, 3, w.TemperatureC)
+	b = pw.AppendDouble(b, 4, w.FeelsLikeC)
+	b = pw.AppendDouble(b, 5, w.RainMM)
+	b = pw.AppendDouble(b, 6, w.SnowfallCM)
+	b = pw.AppendDouble(b, 7, w.PrecipitationMM)
+	b = pw.AppendDouble(b, 8, w.HumidityPct)
+	b = pw.AppendDouble(b, 9, w.CloudCoverPct)
+	b = pw.AppendDouble(b, 10, w.WindSpeedKmh)
+	b = pw.AppendDouble(b, 11, w.WindDirectionDeg)
+	b = pw.AppendDouble(b, 12, w.WindGustsKmh)
+	b = pw.AppendDouble(b, 13, w.Latitude)
+	b = pw.AppendDouble(b, 14, w.Longitude)
+	b = pw.AppendString(b, 15, w.Timezone)
+	b = pw.AppendString(b, 16, w.Source)
+	if w.UVIndex != nil {
+		// optional fields are sent even when zero
+		b = pw.AppendTag(b, 17, pw.Fixed64)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(*w.UVIndex))
+	}
+	b = pw.AppendString(b, 18, w.UVBand)
+	b = pw.AppendMessage(b, 19, encodeTimestamp(fetchedAt))
+	b = pw.AppendString(b, 20, w.Provider)
+	if !w.ObservedAt.IsZero() {
+		b = pw.AppendMessage(b, 21, encodeTimestamp(w.ObservedAt))
+	}
+	b = pw.AppendDouble(b, 22, w.PressureHPa)
+	b = pw.AppendString(b, 23, w.ConditionSeverity)
+	b = pw.AppendString(b, 24, w.Icon)
+	return b
+}
+
+// encodeForecastData encodes reef.v1.ForecastData
+func encodeForecastData(f *feeds.ForecastData, fetchedAt time.Time) []byte {
+	var b []byte
+	b = pw.AppendDouble(b, 1, f.Latitude)
+	b = pw.AppendDouble(b, 2, f.Longitude)
+	b = pw.AppendString(b, 3, f.Timezone)
+	for _, d := range f.Days {
+		b = pw.AppendMessage(b, 4, encodeDailyForecast(d))
+	}
+	b = pw.AppendMessage(b, 5, encodeTimestamp(fetchedAt))
+	return b
+}
+
+// encodeDailyForecast encodes reef.v1.DailyForecast
+func encodeDailyForecast(d feeds.DailyForecast) []byte {
+	var b []byte
+	b = pw.AppendMessage(b, 1, encodeTimestamp(d.Date))
+	b = pw.AppendString(b, 2, d.Summary)
+	b = pw.AppendVarint(b, 3, uint64(int64(d.WeatherCode)))
+	b = pw.AppendDouble(b, 4, d.HighC)
+	b = pw.AppendDouble(b, 5, d.LowC)
+	if d.PrecipitationProbability != nil {
+		b = pw.AppendTag(b, 6, pw.Varint)
+		b = binary.AppendUvarint(b, uint64(int64(*d.PrecipitationProbability)))
+	}
+	return b
+}
+
+// feedData is reef.v1.FeedData
+type feedData struct {
+	location, feed string
+	json           []byte
+	err            string
+	fetchedAt      time.Time
+}
+
+func (d feedData) encode() []byte {
+	var b []byte
+	b = pw.AppendString(b, 1, d.location)
+	b = pw.AppendString(b, 2, d.feed)
+	b = pw.AppendBytes(b, 3, d.json)
+	b = pw.AppendString(b, 4, d.err)
+	b = pw.AppendMessage(b, 5, encodeTimestamp(d.fetchedAt))
+	return b
+}
+
+// encodeTimestamp encodes google.protobuf.Timestamp
+func encodeTimestamp(t time.Time) []byte {
+	var b []byte
+	b = pw.AppendVarint(b, 1, uint64(t.Unix()))
+	b = pw.AppendVarint(b, 2, uint64(t.Nanosecond()))
+	return b
+}
@@ -0,0 +1,247 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"reef-na/feeds"
+)
+
+// Server serves the WeatherService and FeedService gRPC services from the
+// latest snapshots of a Scheduler
+type Server struct {
+	sched   *feeds.Scheduler
+	unary   map[string]unaryHandler
+	streams map[string]streamHandler
+
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates a Server reading from sched. Countries must be tracked by
+// sched to be served.
+func New(sched *feeds.Scheduler) *Server {
+	s := &Server{sched: sched, closing: make(chan struct{})}
+	s.unary = map[string]unaryHandler{
+		"/reef.v1.WeatherService/GetCurrentWeather": s.getCurrentWeather,
+		"/reef.v1.WeatherService/GetForecast":       s.getForecast,
+		"/reef.v1.FeedService/GetFeed":              s.getFeed,
+	}
+	s.streams = map[string]streamHandler{
+		"/reef.v1.FeedService/WatchFeeds": s.watchFeeds,
+	}
+	return s
+}
+
+// Close ends open WatchFeeds streams, which http.Server's Shutdown would
+// otherwise wait for; register it with RegisterOnShutdown
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closing) })
+}
+
+func (s *Server) getCurrentWeather(_ context.Context, b []byte) ([]byte, error) {
+	req, err := decodeLocationRequest(b)
+	if err != nil {
+		return nil, statusf(InvalidArgument, "invalid request: %v", err)
+	}
+	snap, err := s.snapshot(req.country)
+	if err != nil {
+		return nil, err
+	}
+	if snap.Weather == nil {
+		return nil, feedMissing(snap, "weather")
+	}
+	return encodeWeatherData(snap.Weather, snap.FetchedAt), nil
+}
+
+func (s *Server) getForecast(_ context.Context, b []byte) ([]byte, error) {
+	req, err := decodeLocationRequest(b)
+	if err != nil {
+		return nil, statusf(InvalidArgument, "invalid request: %v", err)
+	}
+	snap, err := s.snapshot(req.country)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := snap.Feeds["forecast"]
+	if !ok {
+		return nil, feedMissing(snap, "forecast")
+	}
+	forecast, ok := v.(*feeds.ForecastData)
+	if !ok {
+		return nil, statusf(Internal, "forecast feed returned %T", v)
+	}
+	return encodeForecastData(forecast, snap.FetchedAt), nil
+}
+
+func (s *Server) getFeed(_ context.Context, b []byte) ([]byte, error) {
+	req, err := decodeFeedRequest(b)
+	if err != nil {
+		return nil, statusf(InvalidArgument, "invalid request: %v", err)
+	}
+	feed := strings.ToLower(req.feed)
+	if s.sched.Interval(feed) == 0 {
+		return nil, statusf(NotFound, "unknown feed %q", req.feed)
+	}
+	snap, err := s.snapshot(req.country)
+	if err != nil {
+		return nil, err
+	}
+	d, ok := newFeedData(strings.ToUpper(req.country), snap, feed)
+	if !ok || d.err != "" {
+		return nil, feedMissing(snap, feed)
+	}
+	return d.encode(), nil
+}
+
+// watchFeeds sends the selected feeds of every selected location, then each
+// changed feed until the client or server goes away
+func (s *Server) watchFeeds(ctx context.Context, b []byte, send func([]byte) error) error {
+	req, err := decodeWatchFeedsRequest(b)
+	if err != nil {
+		return statusf(InvalidArgument, "invalid request: %v", err)
+	}
+	names := normalize(req.feeds, strings.ToLower)
+	countries := normalize(req.countries, strings.ToUpper)
+	for _, name := range names {
+		if s.sched.Interval(name) == 0 {
+			return statusf(NotFound, "unknown feed %q", name)
+		}
+	}
+	for _, c := range countries {
+		if !s.sched.Tracked(feeds.Location{Country: c}) {
+			return statusf(NotFound, "country %q is not served", c)
+		}
+	}
+
+	var filters []feeds.ChangeFilter
+	if len(names) > 0 {
+		filters = append(filters, feeds.OnFeedChange(names...))
+	}
+	changes, stop := s.sched.Watch(filters...)
+	defer stop()
+
+	wanted := func(country string) bool {
+		return len(countries) == 0 || slices.Contains(countries, strings.ToUpper(country))
+	}
+	sendFeeds := func(loc string, snap *feeds.Snapshot, feedNames []string) error {
+		for _, name := range feedNames {
+			if len(names) > 0 && !slices.Contains(names, name) {
+				continue
+			}
+			if d, ok := newFeedData(loc, snap, name); ok {
+				if err := send(d.encode()); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	snaps := s.sched.Snapshots()
+	for _, key := range slices.Sorted(maps.Keys(snaps)) {
+		snap := snaps[key]
+		if !wanted(snap.Country) {
+			continue
+		}
+		if err := sendFeeds(key, snap, snapshotFeeds(snap)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.closing:
+			return statusf(Unavailable, "server shutting down")
+		case c := <-changes:
+			if !wanted(c.Current.Country) {
+				continue
+			}
+			if err := sendFeeds(c.Location, c.Current, c.Feeds); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// snapshot returns a country's latest snapshot, or the status for a country
+// that is not tracked or not fetched yet
+func (s *Server) snapshot(country string) (*feeds.Snapshot, error) {
+	loc := feeds.Location{Country: country}
+	if snap, ok := s.sched.Get(loc); ok {
+		return snap, nil
+	}
+	if country == "" {
+		return nil, statusf(InvalidArgument, "country is required")
+	}
+	if !s.sched.Tracked(loc) {
+		return nil, statusf(NotFound, "country %q is not served", country)
+	}
+	return nil, statusf(Unavailable, "feeds for %q are not ready yet", country)
+}
+
+// feedMissing returns the status for a feed without a value in a snapshot
+func feedMissing(snap *feeds.Snapshot, feed string) error {
+	if msg, failed := snap.Errors[feed]; failed {
+		return statusf(Unavailable, "%s feed failed: %s", feed, msg)
+	}
+	return statusf(NotFound, "no %s feed for %s", feed, snap.Country)
+}
+
+// newFeedData returns a feed's value or error from a snapshot, or false if
+// it has neither
+func newFeedData(loc string, snap *feeds.Snapshot, feed string) (feedData, bool) {
+	d := feedData{location: loc, feed: feed, fetchedAt: snap.FetchedAt}
+	var v any
+	if feed == "weather" {
+		if snap.Weather != nil {
+			v = snap.Weather
+		}
+	} else {
+		v = snap.Feeds[feed]
+	}
+	if v == nil {
+		msg, failed := snap.Errors[feed]
+		d.err = msg
+		return d, failed
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		d.err = "encoding feed: " + err.Error()
+		return d, true
+	}
+	d.json = b
+	return d, true
+}
+
+// snapshotFeeds returns the sorted names of the feeds a snapshot has a value
+// or error for
+func snapshotFeeds(snap *feeds.Snapshot) []string {
+	names := slices.Collect(maps.Keys(snap.Feeds))
+	if snap.Weather != nil {
+		names = append(names, "weather")
+	}
+	for name := range snap.Errors {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// normalize trims, drops blanks and case-folds a list of names or codes
+func normalize(vals []string, fold func(string) string) []string {
+	var out []string
+	for _, v := range vals {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, fold(v))
+		}
+	}
+	return out
+}
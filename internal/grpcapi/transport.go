@@ -0,0 +1,204 @@
+// Package grpcapi serves reefd's gRPC API, defined in
+// proto/reef/v1/reef.proto, from Scheduler snapshots. It implements the
+// gRPC wire protocol on net/http's HTTP/2 support and encodes messages with
+// protowire, since the module does not depend on grpc-go.
+package grpcapi
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"reef-na/internal/logger"
+)
+
+// maxRequestSize limits request messages, matching grpc-go's default
+const maxRequestSize = 4 << 20
+
+// Code is a gRPC status code
+type Code int
+
+// gRPC status codes used by the API
+const (
+	OK                Code = 0
+	Canceled          Code = 1
+	Unknown           Code = 2
+	InvalidArgument   Code = 3
+	DeadlineExceeded  Code = 4
+	NotFound          Code = 5
+	ResourceExhausted Code = 8
+	Unimplemented     Code = 12
+	Internal          Code = 13
+	Unavailable       Code = 14
+)
+
+// Status is an error carrying a gRPC status code
+type Status struct {
+	Code    Code
+	Message string
+}
+
+func (s *Status) Error() string {
+	return fmt.Sprintf("grpc status %d: %s", s.Code, s.Message)
+}
+
+// statusf creates a Status error
+func statusf(code Code, format string, args ...any) error {
+	return &Status{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// unaryHandler handles a call with one request and one response message
+type unaryHandler func(ctx context.Context, req []byte) ([]byte, error)
+
+// streamHandler handles a call with one request and a stream of responses
+type streamHandler func(ctx context.Context, req []byte, send func([]byte) error) error
+
+// ServeHTTP serves a gRPC call. Calls must arrive over HTTP/2, which for
+// plaintext means the http.Server needs unencrypted HTTP/2 enabled.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor != 2 || r.Method != http.MethodPost {
+		http.Error(w, "gRPC calls must be HTTP/2 POST requests", http.StatusBadRequest)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	start := time.Now()
+	w.Header().Set("Content-Type", "application/grpc")
+	w.WriteHeader(http.StatusOK)
+
+	ctx := r.Context()
+	if timeout, ok := parseTimeout(r.Header.Get("Grpc-Timeout")); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := s.call(ctx, w, r)
+	st := toStatus(ctx, err)
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(int(st.Code)))
+	if st.Message != "" {
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", encodeGRPCMessage(st.Message))
+	}
+	logger.Infof("grpc %s code=%d dur=%s", r.URL.Path, st.Code, time.Since(start))
+}
+
+// call reads the request message and runs the method's handler
+func (s *Server) call(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	unary, isUnary := s.unary[r.URL.Path]
+	stream, isStream := s.streams[r.URL.Path]
+	if !isUnary && !isStream {
+		return statusf(Unimplemented, "unknown method %s", r.URL.Path)
+	}
+	req, err := readMessage(r.Body)
+	if err != nil {
+		return err
+	}
+
+	rc := http.NewResponseController(w)
+	send := func(msg []byte) error {
+		if err := writeMessage(w, msg); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+	if isStream {
+		return stream(ctx, req, send)
+	}
+	resp, err := unary(ctx, req)
+	if err != nil {
+		return err
+	}
+	return send(resp)
+}
+
+// readMessage reads one length-prefixed message
+func readMessage(body io.Reader) ([]byte, error) {
+	var hdr [5]byte
+	if _, err := io.ReadFull(body, hdr[:]); err != nil {
+		return nil, statusf(InvalidArgument, "reading request: %v", err)
+	}
+	if hdr[0] != 0 {
+		return nil, statusf(Unimplemented, "compressed messages are not supported")
+	}
+	n := binary.BigEndian.Uint32(hdr[1:])
+	if n > maxRequestSize {
+		return nil, statusf(ResourceExhausted, "request of %d bytes exceeds %d", n, maxRequestSize)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(body, msg); err != nil {
+		return nil, statusf(InvalidArgument, "reading request: %v", err)
+	}
+	return msg, nil
+}
+
+// writeMessage writes one uncompressed length-prefixed message
+func writeMessage(w io.Writer, msg []byte) error {
+	buf := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(msg)))
+	_, err := w.Write(append(buf, msg...))
+	return err
+}
+
+// toStatus maps a handler error to the status sent to the client
+func toStatus(ctx context.Context, err error) *Status {
+	var st *Status
+	switch {
+	case err == nil:
+		return &Status{Code: OK}
+	case errors.As(err, &st):
+		return st
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return &Status{Code: DeadlineExceeded, Message: err.Error()}
+	case ctx.Err() != nil:
+		return &Status{Code: Canceled, Message: err.Error()}
+	default:
+		return &Status{Code: Unknown, Message: err.Error()}
+	}
+}
+
+// parseTimeout parses a grpc-timeout header such as "200m" or "5S"
+func parseTimeout(s string) (time.Duration, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(s[:len(s)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	units := map[byte]time.Duration{
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+		'm': time.Millisecond,
+		'u': time.Microsecond,
+		'n': time.Nanosecond,
+	}
+	unit, ok := units[s[len(s)-1]]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// encodeGRPCMessage percent-encodes a status message as the protocol
+// requires for bytes outside printable ASCII
+func encodeGRPCMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
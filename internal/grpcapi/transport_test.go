@@ -0,0 +1,234 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"reef-na/feeds"
+	pw "reef-na/internal/protowire"
+)
+
+// currentBody is an Open-Meteo current-conditions response for New York
+const currentBody = `{"latitude":40.71,"longitude":-74.01,"timezone":"America/New_York",
+"current":{"time":"2026-06-21T12:00","temperature_2m":21.5,"apparent_temperature":22.1,"weather_code":3,
+"relative_humidity_2m":64,"cloud_cover":90,"wind_speed_10m":14.8,"wind_direction_10m":225,"wind_gusts_10m":31.3}}`
+
+// newTestServer serves the gRPC API over unencrypted HTTP/2 from a Scheduler
+// that has fetched the weather for the US, and returns the server's URL and
+// an HTTP/2-only client for it
+func newTestServer(t *testing.T) (string, *http.Client) {
+	t.Helper()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, currentBody)
+	}))
+	t.Cleanup(upstream.Close)
+	c := feeds.NewClient(feeds.WithBaseURL(upstream.URL), feeds.WithLogger(slog.New(slog.DiscardHandler)))
+	sched := feeds.NewScheduler(c, feeds.WithSchedulerRegistry(feeds.NewRegistry()))
+	snapshots, stop := sched.Subscribe()
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sched.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	if err := sched.TrackCountry("US"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-snapshots:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no snapshot for US")
+	}
+
+	srv := httptest.NewUnstartedServer(New(sched))
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	transport := &http.Transport{Protocols: new(http.Protocols)}
+	transport.Protocols.SetUnencryptedHTTP2(true)
+	t.Cleanup(transport.CloseIdleConnections)
+	return srv.URL, &http.Client{Transport: transport}
+}
+
+// frame prefixes a message with the gRPC length-prefixed message header
+func frame(compressed byte, msg []byte) []byte {
+	b := []byte{compressed, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(b[1:], uint32(len(msg)))
+	return append(b, msg...)
+}
+
+// invoke calls a method with a raw request body and returns the response
+// messages and the trailers
+func invoke(t *testing.T, client *http.Client, url, method string, body []byte) ([][]byte, http.Header) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url+method, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.ProtoMajor != 2 || resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/grpc" {
+		t.Fatalf("%s: %s %s, content type %q", method, resp.Proto, resp.Status, resp.Header.Get("Content-Type"))
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var msgs [][]byte
+	for len(data) > 0 {
+		if len(data) < 5 || data[0] != 0 {
+			t.Fatalf("%s: invalid message header in % x", method, data)
+		}
+		n := binary.BigEndian.Uint32(data[1:5])
+		if uint32(len(data)-5) < n {
+			t.Fatalf("%s: message of %d bytes truncated to %d", method, n, len(data)-5)
+		}
+		msgs = append(msgs, data[5:5+n])
+		data = data[5+n:]
+	}
+	return msgs, resp.Trailer
+}
+
+func TestUnaryFraming(t *testing.T) {
+	url, client := newTestServer(t)
+	req := pw.AppendString(nil, 1, "us")
+	msgs, trailer := invoke(t, client, url, "/reef.v1.WeatherService/GetCurrentWeather", frame(0, req))
+	if got := trailer.Get("Grpc-Status"); got != "0" {
+		t.Fatalf("grpc-status %q (%s), want 0", got, trailer.Get("Grpc-Message"))
+	}
+	if len(msgs) != 1 {
+		t.Fatalf("got %d response messages, want 1", len(msgs))
+	}
+
+	// summary "Overcast", weather_code 3 and temperature_c 21.5, as protoc
+	// encodes them
+	prefix, _ := hex.DecodeString("0a084f76657263617374" + "1003" + "190000000000803540")
+	if !bytes.HasPrefix(msgs[0], prefix) {
+		t.Errorf("response starts % x, want % x", msgs[0][:min(len(msgs[0]), len(prefix))], prefix)
+	}
+	var tz string
+	var fetchedAt bool
+	err := pw.Fields(msgs[0], func(r *pw.Reader, num, wire int) (bool, error) {
+		if wire != pw.Bytes || (num != 15 && num != 19) {
+			return false, nil
+		}
+		v, err := r.Bytes()
+		if num == 15 {
+			tz = string(v)
+		} else {
+			fetchedAt = len(v) > 0
+		}
+		return true, err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tz != "America/New_York" || !fetchedAt {
+		t.Errorf("timezone %q, fetched_at set %v", tz, fetchedAt)
+	}
+}
+
+func TestStatusTrailers(t *testing.T) {
+	url, client := newTestServer(t)
+	oversized := []byte{0, 0, 0x40, 0, 1} // 4 MiB + 1
+	tests := []struct {
+		name, method string
+		body         []byte
+		code         Code
+	}{
+		{"unknown method", "/reef.v1.WeatherService/Nope", frame(0, nil), Unimplemented},
+		{"country not served", "/reef.v1.WeatherService/GetCurrentWeather", frame(0, pw.AppendString(nil, 1, "CA")), NotFound},
+		{"no country", "/reef.v1.WeatherService/GetCurrentWeather", frame(0, nil), InvalidArgument},
+		{"compressed", "/reef.v1.WeatherService/GetCurrentWeather", frame(1, pw.AppendString(nil, 1, "US")), Unimplemented},
+		{"short header", "/reef.v1.WeatherService/GetCurrentWeather", []byte{0, 0, 0}, InvalidArgument},
+		{"truncated message", "/reef.v1.WeatherService/GetCurrentWeather", frame(0, pw.AppendString(nil, 1, "US"))[:7], InvalidArgument},
+		{"oversized message", "/reef.v1.WeatherService/GetCurrentWeather", oversized, ResourceExhausted},
+		{"invalid message", "/reef.v1.WeatherService/GetCurrentWeather", frame(0, []byte{0x0a, 0x05, 'U'}), InvalidArgument},
+		{"unknown feed", "/reef.v1.FeedService/GetFeed", frame(0, pw.AppendString(nil, 1, "nope")), NotFound},
+	}
+	for _, tt := range tests {
+		msgs, trailer := invoke(t, client, url, tt.method, tt.body)
+		if got := trailer.Get("Grpc-Status"); got != strconv.Itoa(int(tt.code)) {
+			t.Errorf("%s: grpc-status %q (%s), want %d", tt.name, got, trailer.Get("Grpc-Message"), tt.code)
+		}
+		if len(msgs) != 0 {
+			t.Errorf("%s: got %d response messages with an error status", tt.name, len(msgs))
+		}
+	}
+}
+
+func TestRejectsHTTP1(t *testing.T) {
+	srv := httptest.NewServer(New(feeds.NewScheduler(feeds.NewClient())))
+	defer srv.Close()
+	resp, err := http.Post(srv.URL+"/reef.v1.WeatherService/GetCurrentWeather", "application/grpc", bytes.NewReader(frame(0, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("HTTP/1.1 call got %s, want 400", resp.Status)
+	}
+}
+
+func TestParseTimeout(t *testing.T) {
+	tests := map[string]time.Duration{
+		"1H":    time.Hour,
+		"2M":    2 * time.Minute,
+		"5S":    5 * time.Second,
+		"200m":  200 * time.Millisecond,
+		"7u":    7 * time.Microsecond,
+		"99n":   99,
+		"0S":    0,
+		"":      -1,
+		"S":     -1,
+		"5s":    -1,
+		"-1S":   -1,
+		"1.5S":  -1,
+		"99999": -1,
+	}
+	for in, want := range tests {
+		got, ok := parseTimeout(in)
+		if want < 0 && ok || want >= 0 && (!ok || got != want) {
+			t.Errorf("parseTimeout(%q) = %v, %v", in, got, ok)
+		}
+	}
+}
+
+func TestEncodeGRPCMessage(t *testing.T) {
+	tests := map[string]string{
+		"plain text":     "plain text",
+		"100% done":      "100%25 done",
+		"line\nbreak":    "line%0Abreak",
+		"café":           "caf%C3%A9",
+		"tab\tand ~ end": "tab%09and ~ end",
+	}
+	for in, want := range tests {
+		if got := encodeGRPCMessage(in); got != want {
+			t.Errorf("encodeGRPCMessage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Package protowire reads and writes the protobuf wire format without
+// generated code, for the few protobuf feeds the service consumes and the
+// messages of its gRPC API
+package protowire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Wire types
+const (
+	Varint  = 0
+	Fixed64 = 1
+	Bytes   = 2
+	Fixed32 = 5
+)
+
+// ErrTruncated is returned for a message that ends mid-field
+var ErrTruncated = errors.New("truncated protobuf message")
+
+// Reader reads wire-format fields from a buffer
+type Reader struct {
+	b []byte
+}
+
+// NewReader creates a Reader over a message
+func NewReader(b []byte) *Reader {
+	return &Reader{b: b}
+}
+
+// More reports whether fields remain
+func (r *Reader) More() bool {
+	return len(r.b) > 0
+}
+
+// Field reads the next field tag
+func (r *Reader) Field() (num int, wire int, err error) {
+	tag, err := r.Varint()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(tag >> 3), int(tag & 7), nil
+}
+
+// Varint reads a base-128 varint
+func (r *Reader) Varint() (uint64, error) {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		return 0, ErrTruncated
+	}
+	r.b = r.b[n:]
+	return v, nil
+}
+
+// Bytes reads a length-delimited field
+func (r *Reader) Bytes() ([]byte, error) {
+	n, err := r.Varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.b)) {
+		return nil, ErrTruncated
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b, nil
+}
+
+// Skip discards a field of the given wire type
+func (r *Reader) Skip(wire int) error {
+	var n int
+	switch wire {
+	case Varint:
+		_, err := r.Varint()
+		return err
+	case Bytes:
+		_, err := r.Bytes()
+		return err
+	case Fixed64:
+		n = 8
+	case Fixed32:
+		n = 4
+	default:
+		return fmt.Errorf("unsupported protobuf wire type %d", wire)
+	}
+	if len(r.b) < n {
+		return ErrTruncated
+	}
+	r.b = r.b[n:]
+	return nil
+}
+
+// Fields calls fn for each field of a message. fn reads the field's value
+// and reports whether it did; unhandled fields are skipped.
+func Fields(b []byte, fn func(r *Reader, num, wire int) (bool, error)) error {
+	r := NewReader(b)
+	for r.More() {
+		num, wire, err := r.Field()
+		if err != nil {
+			return err
+		}
+		handled, err := fn(r, num, wire)
+		if err != nil {
+			return err
+		}
+		if !handled {
+			if err := r.Skip(wire); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// AppendTag appends a field tag
+func AppendTag(b []byte, num, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(num)<<3|uint64(wire))
+}
+
+// AppendVarint appends a varint field, omitting zero as proto3 does
+func AppendVarint(b []byte, num int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	return binary.AppendUvarint(AppendTag(b, num, Varint), v)
+}
+
+// AppendSint appends a sint32 or sint64 field, zigzag-encoded, omitting zero
+func AppendSint(b []byte, num int, v int64) []byte {
+	return AppendVarint(b, num, EncodeZigZag(v))
+}
+
+// EncodeZigZag maps a signed integer to the varint of a sint32 or sint64
+// field, so that small negative numbers stay short: 0, -1, 1, -2 become 0, 1,
+// 2, 3
+func EncodeZigZag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// DecodeZigZag reverses EncodeZigZag
+func DecodeZigZag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// AppendBytes appends a length-delimited field, omitting empty values
+func AppendBytes(b []byte, num int, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = binary.AppendUvarint(AppendTag(b, num, Bytes), uint64(len(v)))
+	return append(b, v...)
+}
+
+// AppendString appends a string field, omitting empty strings
+func AppendString(b []byte, num int, v string) []byte {
+	return AppendBytes(b, num, []byte(v))
+}
+
+// AppendDouble appends a double field, omitting zero
+func AppendDouble(b []byte, num int, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	return binary.LittleEndian.AppendUint64(AppendTag(b, num, Fixed64), math.Float64bits(v))
+}
+
+// AppendMessage appends an embedded message field. Unlike other values an
+// empty message is kept, since its presence is meaningful.
+func AppendMessage(b []byte, num int, msg []byte) []byte {
+	b = binary.AppendUvarint(AppendTag(b, num, Bytes), uint64(len(msg)))
+	return append(b, msg...)
+}
@@ -0,0 +1,185 @@
+package protowire
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+)
+
+// Golden encodings are those of the protobuf encoding guide and of protoc's
+// output for equivalent messages
+func TestAppendGolden(t *testing.T) {
+	minusTwo := int64(-2)
+	tests := []struct {
+		name string
+		got  []byte
+		want string
+	}{
+		{"varint 150", AppendVarint(nil, 1, 150), "089601"},
+		{"zero varint omitted", AppendVarint(nil, 1, 0), ""},
+		{"negative int32", AppendVarint(nil, 1, uint64(minusTwo)), "08feffffffffffffffff01"},
+		{"sint32 -2", AppendSint(nil, 1, -2), "0803"},
+		{"sint64 min", AppendSint(nil, 1, math.MinInt64), "08ffffffffffffffffff01"},
+		{"string", AppendString(nil, 2, "testing"), "120774657374696e67"},
+		{"empty string omitted", AppendString(nil, 2, ""), ""},
+		{"bytes", AppendBytes(nil, 4, []byte{0, 0xff}), "220200ff"},
+		{"embedded message", AppendMessage(nil, 3, AppendVarint(nil, 1, 150)), "1a03089601"},
+		{"empty message kept", AppendMessage(nil, 3, nil), "1a00"},
+		{"double", AppendDouble(nil, 1, 1), "09000000000000f03f"},
+		{"negative double", AppendDouble(nil, 12, -2.5), "6100000000000004c0"},
+		{"zero double omitted", AppendDouble(nil, 1, 0), ""},
+		{"two-byte tag", AppendVarint(nil, 16, 1), "800101"},
+		{"largest field number", AppendTag(nil, 1<<29-1, Fixed32), "fdffffff0f"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(tt.got); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestVarintRoundTrip(t *testing.T) {
+	for _, v := range []uint64{1, 127, 128, 150, 300, 16383, 16384, 1<<32 - 1, 1 << 32, 1<<63 - 1, math.MaxUint64} {
+		b := AppendVarint(nil, 5, v)
+		r := NewReader(b)
+		num, wire, err := r.Field()
+		if err != nil || num != 5 || wire != Varint {
+			t.Errorf("%d: field %d wire %d, %v", v, num, wire, err)
+			continue
+		}
+		got, err := r.Varint()
+		if err != nil || got != v || r.More() {
+			t.Errorf("%d: read back %d, %v (%d bytes left)", v, got, err, len(r.b))
+		}
+	}
+}
+
+func TestZigZag(t *testing.T) {
+	tests := []struct {
+		v    int64
+		want uint64
+	}{
+		{0, 0},
+		{-1, 1},
+		{1, 2},
+		{-2, 3},
+		{2, 4},
+		{math.MaxInt32, math.MaxUint32 - 1},
+		{math.MinInt32, math.MaxUint32},
+		{math.MaxInt64, math.MaxUint64 - 1},
+		{math.MinInt64, math.MaxUint64},
+	}
+	for _, tt := range tests {
+		if got := EncodeZigZag(tt.v); got != tt.want {
+			t.Errorf("EncodeZigZag(%d) = %d, want %d", tt.v, got, tt.want)
+		}
+		if got := DecodeZigZag(tt.want); got != tt.v {
+			t.Errorf("DecodeZigZag(%d) = %d, want %d", tt.want, got, tt.v)
+		}
+
+		var got int64
+		err := Fields(AppendSint(nil, 7, tt.v), func(r *Reader, num, wire int) (bool, error) {
+			if num != 7 || wire != Varint {
+				t.Errorf("%d: field %d wire %d", tt.v, num, wire)
+				return false, nil
+			}
+			v, err := r.Varint()
+			got = DecodeZigZag(v)
+			return true, err
+		})
+		if err != nil || got != tt.v {
+			t.Errorf("sint %d read back as %d, %v", tt.v, got, err)
+		}
+	}
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	long := bytes.Repeat([]byte("x"), 300) // length needs a two-byte varint
+	values := [][]byte{[]byte("a"), []byte("héllo"), {0, 1, 2}, long}
+	var b []byte
+	for i, v := range values {
+		b = AppendBytes(b, i+1, v)
+	}
+	b = AppendMessage(b, 9, nil)
+
+	var got [][]byte
+	err := Fields(b, func(r *Reader, num, wire int) (bool, error) {
+		if wire != Bytes {
+			t.Errorf("field %d has wire type %d", num, wire)
+			return false, nil
+		}
+		v, err := r.Bytes()
+		got = append(got, v)
+		return true, err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(values)+1 {
+		t.Fatalf("read %d fields, want %d", len(got), len(values)+1)
+	}
+	for i, v := range values {
+		if !bytes.Equal(got[i], v) {
+			t.Errorf("field %d = %q, want %q", i+1, got[i], v)
+		}
+	}
+	if len(got[len(values)]) != 0 {
+		t.Errorf("empty message read back as %q", got[len(values)])
+	}
+}
+
+func TestFieldsSkipsUnhandled(t *testing.T) {
+	var b []byte
+	b = AppendVarint(b, 1, 1<<40)
+	b = AppendDouble(b, 2, 3.5)
+	b = AppendString(b, 3, "skipped")
+	b = AppendTag(b, 4, Fixed32)
+	b = append(b, 1, 2, 3, 4)
+	b = AppendString(b, 5, "wanted")
+
+	var got string
+	err := Fields(b, func(r *Reader, num, wire int) (bool, error) {
+		if num != 5 {
+			return false, nil
+		}
+		v, err := r.Bytes()
+		got = string(v)
+		return true, err
+	})
+	if err != nil || got != "wanted" {
+		t.Errorf("got %q, %v, want \"wanted\"", got, err)
+	}
+}
+
+func TestFieldsTruncated(t *testing.T) {
+	fields := [][]byte{
+		AppendVarint(nil, 1, 300),
+		AppendDouble(nil, 2, 1),
+		AppendString(nil, 3, "abc"),
+		AppendTag(nil, 4, Fixed32),
+	}
+	fields[3] = append(fields[3], 1, 2, 3, 4)
+	skip := func(*Reader, int, int) (bool, error) { return false, nil }
+	for _, f := range fields {
+		if err := Fields(f, skip); err != nil {
+			t.Errorf("%x: %v", f, err)
+		}
+		for n := 1; n < len(f); n++ {
+			if err := Fields(f[:n], skip); !errors.Is(err, ErrTruncated) {
+				t.Errorf("%x: error = %v, want ErrTruncated", f[:n], err)
+			}
+		}
+	}
+
+	r := NewReader([]byte{0x0a, 0x05, 'a'})
+	r.Field()
+	if _, err := r.Bytes(); !errors.Is(err, ErrTruncated) {
+		t.Errorf("Bytes past the end: error = %v, want ErrTruncated", err)
+	}
+	// group wire types are not supported
+	if err := Fields(AppendTag(nil, 1, 3), skip); err == nil {
+		t.Error("start-group field was skipped")
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"reef-na/digest"
+	"reef-na/feeds"
+	"reef-na/internal/logger"
+)
+
+// WithDigests serves g's digests of served countries at /v1/digest/{country},
+// as JSON or, with ?format=text or ?format=html, rendered. The latest
+// scheduled digest is served if it is from today, otherwise one is generated.
+func WithDigests(g *digest.Generator) Option {
+	return func(s *Server) {
+		s.v1.HandleFunc("/digest/{country}", s.handleDigest(g)).Methods(http.MethodGet)
+	}
+}
+
+// handleDigest serves a country's digest
+func (s *Server) handleDigest(g *digest.Generator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		country := mux.Vars(r)["country"]
+		loc := feeds.Location{Country: country}
+		if !s.sched.Tracked(loc) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("country %q is not served", country))
+			return
+		}
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = digest.FormatJSON
+		}
+		if format != digest.FormatJSON && format != digest.FormatText && format != digest.FormatHTML {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown format %q, want json, text or html", format))
+			return
+		}
+
+		d, ok := g.Latest(loc)
+		if !ok || !sameDay(d.Date, time.Now()) {
+			if snap, ok := s.sched.Get(loc); ok {
+				loc.Coordinates = feeds.Coordinates{Lat: snap.Latitude, Lon: snap.Longitude}
+			}
+			var err error
+			if d, err = g.Generate(r.Context(), loc); err != nil {
+				writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to generate digest: %v", err))
+				return
+			}
+		}
+
+		var buf bytes.Buffer
+		var err error
+		switch format {
+		case digest.FormatJSON:
+			writeJSON(w, http.StatusOK, d)
+			return
+		case digest.FormatText:
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			err = d.WriteText(&buf)
+		case digest.FormatHTML:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			err = d.WriteHTML(&buf)
+		}
+		if err != nil {
+			logger.Errorf("failed to render digest: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to render digest")
+			return
+		}
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+// sameDay reports whether t is on the same date as now in t's location
+func sameDay(t, now time.Time) bool {
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := now.In(t.Location()).Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
@@ -0,0 +1,204 @@
+// Package server exposes the Scheduler's feed snapshots over HTTP
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"reef-na/feeds"
+	mw "reef-na/internal/http/middleware"
+	"reef-na/internal/logger"
+)
+
+// Server serves JSON from the latest snapshots of a Scheduler
+type Server struct {
+	sched  *feeds.Scheduler
+	router *mux.Router
+	v1     *mux.Router
+
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithMetrics serves h, such as a *feeds.Metrics, at /metrics
+func WithMetrics(h http.Handler) Option {
+	return func(s *Server) {
+		s.router.Handle("/metrics", h).Methods(http.MethodGet)
+	}
+}
+
+// WithHealth serves h, such as a *health.Checker, at /healthz
+func WithHealth(h http.Handler) Option {
+	return func(s *Server) {
+		s.router.Handle("/healthz", h).Methods(http.MethodGet)
+	}
+}
+
+// New creates a Server reading from sched. Countries must be tracked by
+// sched to be served.
+func New(sched *feeds.Scheduler, options ...Option) *Server {
+	s := &Server{sched: sched, router: mux.NewRouter(), closing: make(chan struct{})}
+	r := s.router
+	r.Use(mw.LogRequests(mw.WithSkips("/health", "/healthz", "/ready", "/metrics")))
+
+	r.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}).Methods(http.MethodGet)
+
+	v1 := r.PathPrefix("/v1").Subrouter()
+	s.v1 = v1
+	v1.HandleFunc("/weather/{country}", s.handleFeed("weather")).Methods(http.MethodGet)
+	v1.HandleFunc("/forecast/{country}", s.handleFeed("forecast")).Methods(http.MethodGet)
+	v1.HandleFunc("/alerts/{country}", s.handleFeed("alerts")).Methods(http.MethodGet)
+	v1.HandleFunc("/feeds/{feed}/{country}", s.handleFeed("")).Methods(http.MethodGet)
+	v1.HandleFunc("/aggregate/{country}", s.handleAggregate).Methods(http.MethodGet)
+	v1.HandleFunc("/stream", s.handleStream).Methods(http.MethodGet)
+	v1.HandleFunc("/ws", s.handleWebSocket).Methods(http.MethodGet)
+
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+// ServeHTTP routes a request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+// Close ends open streams and WebSocket connections, which http.Server's
+// Shutdown would otherwise wait for; register it with RegisterOnShutdown
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closing) })
+}
+
+// handleFeed serves one feed of a country's snapshot; an empty name takes
+// the feed from the path
+func (s *Server) handleFeed(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		feed := name
+		if feed == "" {
+			feed = vars["feed"]
+		}
+		snap, ok := s.snapshot(w, vars["country"])
+		if !ok {
+			return
+		}
+
+		var v any
+		if feed == "weather" {
+			if snap.Weather != nil {
+				v = snap.Weather
+			}
+		} else {
+			v = snap.Feeds[feed]
+		}
+		if v == nil {
+			if msg, failed := snap.Errors[feed]; failed {
+				writeError(w, http.StatusBadGateway, fmt.Sprintf("%s feed failed: %s", feed, msg))
+			} else {
+				writeError(w, http.StatusNotFound, fmt.Sprintf("no %s feed for %s", feed, snap.Country))
+			}
+			return
+		}
+		s.writeSnapshotJSON(w, r, snap, s.sched.Interval(feed), v)
+	}
+}
+
+// handleAggregate serves a country's whole snapshot
+func (s *Server) handleAggregate(w http.ResponseWriter, r *http.Request) {
+	snap, ok := s.snapshot(w, mux.Vars(r)["country"])
+	if !ok {
+		return
+	}
+	s.writeSnapshotJSON(w, r, snap, s.sched.Interval("weather"), snap)
+}
+
+// snapshot looks up a country's latest snapshot, writing an error response
+// if it is not tracked or not fetched yet
+func (s *Server) snapshot(w http.ResponseWriter, country string) (*feeds.Snapshot, bool) {
+	loc := feeds.Location{Country: country}
+	snap, ok := s.sched.Get(loc)
+	if ok {
+		return snap, true
+	}
+	if !s.sched.Tracked(loc) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("country %q is not served", country))
+		return nil, false
+	}
+	w.Header().Set("Retry-After", "5")
+	writeError(w, http.StatusServiceUnavailable, fmt.Sprintf("feeds for %q are not ready yet", country))
+	return nil, false
+}
+
+// writeSnapshotJSON writes v, localized to the request's language, with
+// caching headers derived from the snapshot: Last-Modified and an ETag from
+// its fetch time, and a max-age of what is left of the refresh interval.
+// Conditional requests for an unchanged snapshot get 304 Not Modified.
+func (s *Server) writeSnapshotJSON(w http.ResponseWriter, r *http.Request, snap *feeds.Snapshot, interval time.Duration, v any) {
+	lang, err := requestLanguage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tag := strconv.FormatInt(snap.FetchedAt.UnixNano(), 36)
+	if lang != feeds.LanguageEnglish {
+		tag += "-" + string(lang)
+	}
+	etag := `"` + tag + `"`
+	maxAge := max(interval-time.Since(snap.FetchedAt), 0)
+
+	h := w.Header()
+	h.Set("ETag", etag)
+	h.Set("Content-Language", string(lang))
+	h.Add("Vary", "Accept-Language")
+	h.Set("Last-Modified", snap.FetchedAt.UTC().Format(http.TimeFormat))
+	h.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		if match == etag || match == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	} else if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !snap.FetchedAt.Truncate(time.Second).After(since) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, feeds.Localize(v, lang))
+}
+
+// requestLanguage returns the language a request asks for: the lang query
+// parameter, such as ?lang=fr, or else the Accept-Language header
+func requestLanguage(r *http.Request) (feeds.Language, error) {
+	if tag := r.URL.Query().Get("lang"); tag != "" {
+		return feeds.ParseLanguage(tag)
+	}
+	return feeds.MatchLanguage(r.Header.Get("Accept-Language")), nil
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Errorf("failed to encode response: %v", err)
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, msg string) {
+	h := w.Header()
+	h.Set("Cache-Control", "no-store")
+	writeJSON(w, status, map[string]string{"error": msg})
+}
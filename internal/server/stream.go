@@ -0,0 +1,200 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"reef-na/feeds"
+	"reef-na/internal/logger"
+)
+
+// streamKeepAlive is how often an idle stream sends a comment so proxies
+// keep the connection open
+const streamKeepAlive = 15 * time.Second
+
+// streamEvent is the data of a stream event: the requested feeds of one
+// location, "weather" included
+type streamEvent struct {
+	Location  string            `json:"location"`
+	Country   string            `json:"country,omitempty"`
+	Latitude  float64           `json:"latitude"`
+	Longitude float64           `json:"longitude"`
+	Feeds     map[string]any    `json:"feeds"`
+	Errors    map[string]string `json:"errors,omitempty"`
+	FetchedAt time.Time         `json:"fetchedAt"`
+}
+
+// handleStream serves Server-Sent Events of feed updates. The feeds and
+// countries query parameters limit the stream to comma-separated feed names
+// and country codes; all are streamed when omitted. Each tracked location's
+// current snapshot is sent first as a "snapshot" event, then every refresh
+// that changes a requested feed as an "update" event carrying the changed
+// feeds only.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	names := splitList(r.URL.Query().Get("feeds"), false)
+	countries := splitList(r.URL.Query().Get("countries"), true)
+	if status, err := s.checkSelection(names, countries); err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+
+	var filters []feeds.ChangeFilter
+	if len(names) > 0 {
+		filters = append(filters, feeds.OnFeedChange(names...))
+	}
+	changes, stop := s.sched.Watch(filters...)
+	defer stop()
+
+	rc := http.NewResponseController(w)
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", (5 * time.Second).Milliseconds()); err != nil {
+		return
+	}
+
+	send := func(event string, loc string, snap *feeds.Snapshot, feedNames []string) error {
+		data, err := json.Marshal(newStreamEvent(loc, snap, feedNames))
+		if err != nil {
+			logger.Errorf("failed to encode %s event for %s: %v", event, loc, err)
+			return nil
+		}
+		id := strconv.FormatInt(snap.FetchedAt.UnixNano(), 36)
+		if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, event, data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	snaps := s.sched.Snapshots()
+	for _, key := range slices.Sorted(maps.Keys(snaps)) {
+		snap := snaps[key]
+		if !wantCountry(countries, snap.Country) {
+			continue
+		}
+		if err := send("snapshot", key, snap, names); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		logger.Warnf("stream cannot be flushed: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.closing:
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case c := <-changes:
+			if !wantCountry(countries, c.Current.Country) {
+				continue
+			}
+			changed := c.Feeds
+			if len(names) > 0 {
+				changed = slices.DeleteFunc(slices.Clone(changed), func(n string) bool {
+					return !slices.Contains(names, n)
+				})
+			}
+			if err := send("update", c.Location, c.Current, changed); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// checkSelection checks that feed names and country codes selected by a
+// client are served, returning the status to reject them with
+func (s *Server) checkSelection(names, countries []string) (int, error) {
+	for _, name := range names {
+		if s.sched.Interval(name) == 0 {
+			return http.StatusBadRequest, fmt.Errorf("unknown feed %q", name)
+		}
+	}
+	for _, c := range countries {
+		if !s.sched.Tracked(feeds.Location{Country: c}) {
+			return http.StatusNotFound, fmt.Errorf("country %q is not served", c)
+		}
+	}
+	return 0, nil
+}
+
+// newStreamEvent picks the named feeds, or all when names is empty, out of
+// a snapshot
+func newStreamEvent(loc string, snap *feeds.Snapshot, names []string) streamEvent {
+	ev := streamEvent{
+		Location:  loc,
+		Country:   snap.Country,
+		Latitude:  snap.Latitude,
+		Longitude: snap.Longitude,
+		Feeds:     make(map[string]any),
+		FetchedAt: snap.FetchedAt,
+	}
+	want := func(name string) bool {
+		return len(names) == 0 || slices.Contains(names, name)
+	}
+	if snap.Weather != nil && want("weather") {
+		ev.Feeds["weather"] = snap.Weather
+	}
+	for name, v := range snap.Feeds {
+		if want(name) {
+			ev.Feeds[name] = v
+		}
+	}
+	for name, msg := range snap.Errors {
+		if want(name) {
+			if ev.Errors == nil {
+				ev.Errors = make(map[string]string)
+			}
+			ev.Errors[name] = msg
+		}
+	}
+	return ev
+}
+
+// wantCountry reports whether a location's country is selected; an empty
+// selection matches every location
+func wantCountry(countries []string, country string) bool {
+	return len(countries) == 0 || slices.Contains(countries, strings.ToUpper(country))
+}
+
+// splitList splits a comma-separated query parameter with normalizeList
+func splitList(s string, upper bool) []string {
+	return normalizeList(strings.Split(s, ","), upper)
+}
+
+// normalizeList trims and drops blank feed names or country codes, and
+// lowercases names or uppercases codes
+func normalizeList(vals []string, upper bool) []string {
+	var out []string
+	for _, v := range vals {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		if upper {
+			v = strings.ToUpper(v)
+		} else {
+			v = strings.ToLower(v)
+		}
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"reef-na/internal/logger"
+	"reef-na/internal/websocket"
+)
+
+// WebSocket keepalive: the server pings every wsPingInterval and drops
+// connections that send nothing, pongs included, for wsPongWait
+const (
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 2 * wsPingInterval
+)
+
+// wsRequest is a message from a WebSocket client. "subscribe" replaces the
+// connection's subscription with the given feeds and countries, where an
+// empty list selects all of them; "unsubscribe" stops all messages.
+type wsRequest struct {
+	Type      string   `json:"type"`
+	Feeds     []string `json:"feeds,omitempty"`
+	Countries []string `json:"countries,omitempty"`
+}
+
+// wsMessage is a message to a WebSocket client: "snapshot" with the
+// subscribed feeds of a location, sent for every location on subscribing,
+// "delta" with just the feeds a refresh changed, "subscribed" and
+// "unsubscribed" acknowledging requests, and "error"
+type wsMessage struct {
+	Type      string       `json:"type"`
+	Data      *streamEvent `json:"data,omitempty"`
+	Feeds     []string     `json:"feeds,omitempty"`
+	Countries []string     `json:"countries,omitempty"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// wsSubscription is what a connection receives; nil lists select all
+type wsSubscription struct {
+	feeds, countries []string
+}
+
+// handleWebSocket serves snapshots and deltas over a WebSocket. Clients
+// subscribe with wsRequest messages, or with the feeds and countries query
+// parameters at connect time; until then nothing is sent.
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	var sub *wsSubscription
+	q := r.URL.Query()
+	if q.Has("feeds") || q.Has("countries") {
+		sub = &wsSubscription{
+			feeds:     splitList(q.Get("feeds"), false),
+			countries: splitList(q.Get("countries"), true),
+		}
+		if status, err := s.checkSelection(sub.feeds, sub.countries); err != nil {
+			writeError(w, status, err.Error())
+			return
+		}
+	}
+
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		logger.Debugf("websocket upgrade from %s failed: %v", r.RemoteAddr, err)
+		return
+	}
+	defer conn.Close(websocket.CloseNormal, "")
+
+	changes, stop := s.sched.Watch()
+	defer stop()
+
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func([]byte) {
+		_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	reqs := make(chan wsRequest)
+	quit := make(chan struct{})
+	defer close(quit)
+	go s.readWebSocket(conn, reqs, quit)
+
+	if sub != nil && s.sendSnapshots(conn, sub) != nil {
+		return
+	}
+
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			_ = conn.Close(websocket.CloseGoingAway, "server shutting down")
+			return
+		case <-ticker.C:
+			if err := conn.WritePing(nil); err != nil {
+				return
+			}
+		case req, ok := <-reqs:
+			if !ok {
+				return
+			}
+			var err error
+			sub, err = s.handleWebSocketRequest(conn, sub, req)
+			if err != nil {
+				return
+			}
+		case c := <-changes:
+			if sub == nil || !wantCountry(sub.countries, c.Current.Country) {
+				continue
+			}
+			changed := c.Feeds
+			if sub.feeds != nil {
+				changed = slices.DeleteFunc(slices.Clone(changed), func(n string) bool {
+					return !slices.Contains(sub.feeds, n)
+				})
+			}
+			if len(changed) == 0 {
+				continue
+			}
+			ev := newStreamEvent(c.Location, c.Current, changed)
+			if err := writeWebSocketJSON(conn, wsMessage{Type: "delta", Data: &ev}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readWebSocket reads client requests until the connection fails, then
+// closes reqs
+func (s *Server) readWebSocket(conn *websocket.Conn, reqs chan<- wsRequest, quit <-chan struct{}) {
+	defer close(reqs)
+	for {
+		op, data, err := conn.ReadMessage()
+		if err != nil {
+			logger.Debugf("websocket %s closed: %v", conn.RemoteAddr(), err)
+			return
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		if op != websocket.OpText {
+			_ = conn.Close(websocket.CloseUnsupported, "only text messages are accepted")
+			return
+		}
+		var req wsRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			if writeWebSocketJSON(conn, wsMessage{Type: "error", Error: "invalid request: " + err.Error()}) != nil {
+				return
+			}
+			continue
+		}
+		select {
+		case reqs <- req:
+		case <-quit:
+			return
+		}
+	}
+}
+
+// handleWebSocketRequest applies a client request and returns the
+// connection's new subscription
+func (s *Server) handleWebSocketRequest(conn *websocket.Conn, sub *wsSubscription, req wsRequest) (*wsSubscription, error) {
+	switch req.Type {
+	case "subscribe":
+		next := &wsSubscription{
+			feeds:     normalizeList(req.Feeds, false),
+			countries: normalizeList(req.Countries, true),
+		}
+		if _, err := s.checkSelection(next.feeds, next.countries); err != nil {
+			return sub, writeWebSocketJSON(conn, wsMessage{Type: "error", Error: err.Error()})
+		}
+		ack := wsMessage{Type: "subscribed", Feeds: next.feeds, Countries: next.countries}
+		if err := writeWebSocketJSON(conn, ack); err != nil {
+			return next, err
+		}
+		return next, s.sendSnapshots(conn, next)
+	case "unsubscribe":
+		return nil, writeWebSocketJSON(conn, wsMessage{Type: "unsubscribed"})
+	default:
+		return sub, writeWebSocketJSON(conn, wsMessage{Type: "error", Error: "unknown request type " + req.Type})
+	}
+}
+
+// sendSnapshots sends the subscribed feeds of every matching location
+func (s *Server) sendSnapshots(conn *websocket.Conn, sub *wsSubscription) error {
+	snaps := s.sched.Snapshots()
+	for _, key := range slices.Sorted(maps.Keys(snaps)) {
+		snap := snaps[key]
+		if !wantCountry(sub.countries, snap.Country) {
+			continue
+		}
+		ev := newStreamEvent(key, snap, sub.feeds)
+		if err := writeWebSocketJSON(conn, wsMessage{Type: "snapshot", Data: &ev}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeWebSocketJSON sends v as a text message
+func writeWebSocketJSON(conn *websocket.Conn, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logger.Errorf("failed to encode websocket message: %v", err)
+		return nil
+	}
+	return conn.WriteMessage(websocket.OpText, data)
+}
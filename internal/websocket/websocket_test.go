@@ -0,0 +1,345 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcceptKey(t *testing.T) {
+	// the example of RFC 6455 section 1.3
+	if got := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("acceptKey = %s, want s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", got)
+	}
+}
+
+// echoServer upgrades every request, applies setup to the connection and
+// echoes each message back. The error that ended each connection is sent to
+// the returned channel.
+func echoServer(t *testing.T, setup func(*Conn)) (*httptest.Server, <-chan error) {
+	t.Helper()
+	errs := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		if setup != nil {
+			setup(c)
+		}
+		for {
+			op, data, err := c.ReadMessage()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if err := c.WriteMessage(op, data); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, errs
+}
+
+// client is the client side of a test connection
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+// dial opens a WebSocket connection to srv and checks the handshake
+func dial(t *testing.T, srv *httptest.Server) *client {
+	t.Helper()
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req := "GET / HTTP/1.1\r\nHost: reef\r\nConnection: keep-alive, Upgrade\r\nUpgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"
+	if _, err := io.WriteString(conn, req); err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("handshake: %s, accept %q", resp.Status, resp.Header.Get("Sec-WebSocket-Accept"))
+	}
+	return &client{t: t, conn: conn, br: br}
+}
+
+// send writes a frame, masked with a fixed key unless unmasked is set
+func (c *client) send(fin bool, op int, payload []byte, unmasked bool) {
+	c.t.Helper()
+	b0 := byte(op)
+	if fin {
+		b0 |= 0x80
+	}
+	buf := []byte{b0}
+	var maskBit byte = 0x80
+	if unmasked {
+		maskBit = 0
+	}
+	switch n := len(payload); {
+	case n <= 125:
+		buf = append(buf, maskBit|byte(n))
+	case n <= 0xFFFF:
+		buf = append(buf, maskBit|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, maskBit|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+	if unmasked {
+		buf = append(buf, payload...)
+	} else {
+		mask := [4]byte{0x37, 0xfa, 0x21, 0x3d}
+		buf = append(buf, mask[:]...)
+		for i, b := range payload {
+			buf = append(buf, b^mask[i%4])
+		}
+	}
+	if _, err := c.conn.Write(buf); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// receive reads a frame from the server, which must be final and unmasked
+func (c *client) receive() (op int, payload []byte) {
+	c.t.Helper()
+	var hdr [2]byte
+	if _, err := io.ReadFull(c.br, hdr[:]); err != nil {
+		c.t.Fatal(err)
+	}
+	if hdr[0]&0xF0 != 0x80 || hdr[1]&0x80 != 0 {
+		c.t.Fatalf("server frame header % x, want FIN set, no reserved bits and no mask", hdr)
+	}
+	n := uint64(hdr[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			c.t.Fatal(err)
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			c.t.Fatal(err)
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		c.t.Fatal(err)
+	}
+	return int(hdr[0] & 0x0F), payload
+}
+
+// expectClose reads a close frame and checks its code
+func (c *client) expectClose(code int) {
+	c.t.Helper()
+	op, payload := c.receive()
+	if op != OpClose || len(payload) < 2 {
+		c.t.Fatalf("got opcode %d % x, want a close frame", op, payload)
+	}
+	if got := int(binary.BigEndian.Uint16(payload)); got != code {
+		c.t.Errorf("closed with %d %q, want %d", got, payload[2:], code)
+	}
+}
+
+// closeError waits for the error that ended the server's connection and
+// checks it is a CloseError with code
+func closeError(t *testing.T, errs <-chan error, code int) {
+	t.Helper()
+	select {
+	case err := <-errs:
+		var ce *CloseError
+		if !errors.As(err, &ce) || ce.Code != code {
+			t.Errorf("ReadMessage error = %v, want close code %d", err, code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server connection did not end")
+	}
+}
+
+func TestEcho(t *testing.T) {
+	srv, errs := echoServer(t, func(c *Conn) { c.MaxMessageSize = 1 << 20 })
+	c := dial(t, srv)
+	for _, size := range []int{0, 5, 125, 126, 200, 0xFFFF, 0x10000, 70000} {
+		msg := bytes.Repeat([]byte{'a' + byte(size%26)}, size)
+		c.send(true, OpBinary, msg, false)
+		if op, got := c.receive(); op != OpBinary || !bytes.Equal(got, msg) {
+			t.Errorf("%d bytes: echoed opcode %d with %d bytes", size, op, len(got))
+		}
+	}
+
+	c.send(true, OpClose, append([]byte{0x03, 0xe8}, "bye"...), false)
+	c.expectClose(CloseNormal)
+	select {
+	case err := <-errs:
+		var ce *CloseError
+		if !errors.As(err, &ce) || ce.Code != CloseNormal || ce.Reason != "bye" {
+			t.Errorf("ReadMessage error = %v, want close 1000 bye", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server connection did not end")
+	}
+}
+
+func TestFragmentedMessage(t *testing.T) {
+	pongs := make(chan string, 1)
+	srv, _ := echoServer(t, func(c *Conn) {
+		c.SetPongHandler(func(data []byte) { pongs <- string(data) })
+	})
+	c := dial(t, srv)
+
+	// a ping and a pong between the fragments do not interrupt the message
+	c.send(false, OpText, []byte("Hel"), false)
+	c.send(true, OpPing, []byte("are you there"), false)
+	c.send(false, opContinuation, []byte("lo, "), false)
+	c.send(true, OpPong, []byte("here"), false)
+	c.send(false, opContinuation, nil, false)
+	c.send(true, opContinuation, []byte("world"), false)
+
+	if op, payload := c.receive(); op != OpPong || string(payload) != "are you there" {
+		t.Errorf("got opcode %d %q, want the ping answered", op, payload)
+	}
+	if op, payload := c.receive(); op != OpText || string(payload) != "Hello, world" {
+		t.Errorf("got opcode %d %q, want text \"Hello, world\"", op, payload)
+	}
+	select {
+	case got := <-pongs:
+		if got != "here" {
+			t.Errorf("pong handler got %q, want \"here\"", got)
+		}
+	case <-time.After(time.Second):
+		t.Error("pong handler not called")
+	}
+}
+
+func TestProtocolErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames func(c *client)
+	}{
+		{"unmasked frame", func(c *client) {
+			c.send(true, OpText, []byte("hi"), true)
+		}},
+		{"continuation without a message", func(c *client) {
+			c.send(true, opContinuation, []byte("hi"), false)
+		}},
+		{"new message before the last one ended", func(c *client) {
+			c.send(false, OpText, []byte("a"), false)
+			c.send(true, OpText, []byte("b"), false)
+		}},
+		{"fragmented ping", func(c *client) {
+			c.send(false, OpPing, []byte("a"), false)
+		}},
+		{"long ping", func(c *client) {
+			c.send(true, OpPing, bytes.Repeat([]byte("a"), 126), false)
+		}},
+		{"unknown opcode", func(c *client) {
+			c.send(true, 0x3, []byte("a"), false)
+		}},
+		{"reserved bit", func(c *client) {
+			c.send(true, 0x40|OpText, []byte("a"), false)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, errs := echoServer(t, nil)
+			c := dial(t, srv)
+			tt.frames(c)
+			c.expectClose(CloseProtocolError)
+			closeError(t, errs, CloseProtocolError)
+		})
+	}
+}
+
+func TestMaxMessageSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames func(c *client)
+	}{
+		{"one frame", func(c *client) {
+			c.send(true, OpText, []byte(strings.Repeat("x", 17)), false)
+		}},
+		{"fragments", func(c *client) {
+			c.send(false, OpText, []byte(strings.Repeat("x", 10)), false)
+			c.send(true, opContinuation, []byte(strings.Repeat("x", 10)), false)
+		}},
+		{"64-bit length", func(c *client) {
+			// only the header is sent: the length alone is rejected
+			c.conn.Write([]byte{0x82, 0xFF, 0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, errs := echoServer(t, func(c *Conn) { c.MaxMessageSize = 16 })
+			c := dial(t, srv)
+			c.send(true, OpText, []byte(strings.Repeat("x", 16)), false)
+			if op, payload := c.receive(); op != OpText || len(payload) != 16 {
+				t.Fatalf("message at the limit: got opcode %d with %d bytes", op, len(payload))
+			}
+			tt.frames(c)
+			c.expectClose(CloseTooBig)
+			closeError(t, errs, CloseTooBig)
+		})
+	}
+}
+
+func TestBadHandshake(t *testing.T) {
+	srv, _ := echoServer(t, nil)
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		status  int
+	}{
+		{"not an upgrade", http.MethodGet, map[string]string{"Sec-WebSocket-Version": "13"}, http.StatusBadRequest},
+		{"POST", http.MethodPost, nil, http.StatusBadRequest},
+		{"old version", http.MethodGet, map[string]string{"Sec-WebSocket-Version": "8"}, http.StatusUpgradeRequired},
+		{"missing key", http.MethodGet, map[string]string{"Sec-WebSocket-Key": ""}, http.StatusBadRequest},
+		{"short key", http.MethodGet, map[string]string{"Sec-WebSocket-Key": "c2hvcnQ="}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.name != "not an upgrade" {
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", "websocket")
+		}
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: got %s, want %d", tt.name, resp.Status, tt.status)
+		}
+	}
+}
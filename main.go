@@ -10,7 +10,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"reef-na/internal/featureflags"
-	"reef-na/internal/feeds"
+	"reef-na/feeds"
 	mw "reef-na/internal/http/middleware"
 	"reef-na/internal/logger"
 )
@@ -117,7 +117,7 @@ func main() {
 		logger.Infof("serving NA regional feeds for country: %s", country)
 
 		// Fetch real weather data from Open-Meteo API
-		weather, err := feeds.FetchWeather(country)
+		weather, err := feeds.FetchWeatherContext(r.Context(), country)
 		if err != nil {
 			logger.Warnf("failed to fetch weather for %s: %v (using fallback)", country, err)
 			// Fallback to stub data if API fails
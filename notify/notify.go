@@ -0,0 +1,294 @@
+// Package notify pushes severe weather alerts, daily digests and rule
+// firings to destinations such as email, Slack, Discord or any webhook.
+// Each destination is a Sink with its own templates and filters; failed
+// deliveries are retried with backoff.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"time"
+
+	"reef-na/digest"
+	"reef-na/feeds"
+	"reef-na/rules"
+)
+
+// Notification kinds
+const (
+	KindAlert  = "alert"
+	KindDigest = "digest"
+	KindRule   = "rule"
+)
+
+// defaultRetryPolicy tries a delivery three times, starting at 2s
+var defaultRetryPolicy = feeds.RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   2 * time.Second,
+	MaxDelay:    30 * time.Second,
+	Jitter:      0.5,
+}
+
+// Notification is something to tell the sinks about: a new weather alert,
+// a digest or a rule firing
+type Notification struct {
+	Kind     string              `json:"kind"`     // KindAlert, KindDigest or KindRule
+	Location string              `json:"location"` // country code, or "lat,lon"
+	Subject  string              `json:"subject"`
+	Alert    *feeds.WeatherAlert `json:"alert,omitempty"`
+	Digest   *digest.Digest      `json:"digest,omitempty"`
+	Rule     *rules.Firing       `json:"rule,omitempty"`
+	Time     time.Time           `json:"time"`
+}
+
+// AlertNotification returns the Notification of a new alert at a location,
+// keyed like Change.Location
+func AlertNotification(location string, a feeds.WeatherAlert) Notification {
+	return Notification{
+		Kind:     KindAlert,
+		Location: location,
+		Subject:  fmt.Sprintf("%s for %s", a.Event, location),
+		Alert:    &a,
+		Time:     time.Now(),
+	}
+}
+
+// DigestNotification returns the Notification of a digest
+func DigestNotification(d *digest.Digest) Notification {
+	location := d.Country
+	if location == "" {
+		location = fmt.Sprintf("%.4f,%.4f", d.Latitude, d.Longitude)
+	}
+	return Notification{
+		Kind:     KindDigest,
+		Location: location,
+		Subject:  fmt.Sprintf("%s digest for %s", location, d.Date.Format("Monday, January 2")),
+		Digest:   d,
+		Time:     d.GeneratedAt,
+	}
+}
+
+// RuleNotification returns the Notification of a rule firing
+func RuleNotification(f rules.Firing) Notification {
+	return Notification{
+		Kind:     KindRule,
+		Location: f.Location,
+		Subject:  fmt.Sprintf("%s at %s", f.Rule, f.Location),
+		Rule:     &f,
+		Time:     f.Time,
+	}
+}
+
+// Text renders the notification as plain text
+func (n Notification) Text() string {
+	switch {
+	case n.Alert != nil:
+		a := n.Alert
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s for %s (%s)", a.Event, n.Location, a.Severity)
+		if a.Headline != "" {
+			fmt.Fprintf(&b, ": %s", a.Headline)
+		}
+		if !a.Expires.IsZero() {
+			fmt.Fprintf(&b, "\nUntil %s", a.Expires.Format("Mon Jan 2 15:04 MST"))
+		}
+		return b.String()
+	case n.Rule != nil:
+		return fmt.Sprintf("%s at %s: %s", n.Rule.Rule, n.Location, n.Rule.Message)
+	case n.Digest != nil:
+		var b bytes.Buffer
+		if err := n.Digest.WriteText(&b); err != nil {
+			return n.Subject
+		}
+		return b.String()
+	}
+	return n.Subject
+}
+
+// HTML renders the notification as an HTML fragment
+func (n Notification) HTML() string {
+	if n.Digest != nil {
+		var b bytes.Buffer
+		if err := n.Digest.WriteHTML(&b); err == nil {
+			return b.String()
+		}
+	}
+	return "<p>" + strings.ReplaceAll(html.EscapeString(n.Text()), "\n", "<br>\n") + "</p>"
+}
+
+// Sink is a destination for notifications
+type Sink interface {
+	// Name identifies the sink in errors, e.g. "slack"
+	Name() string
+	// Send delivers one notification. Errors wrapping ErrPermanent are not retried.
+	Send(ctx context.Context, n Notification) error
+}
+
+// ErrPermanent marks delivery errors that retrying cannot fix, such as a
+// rejected webhook URL or recipient
+var ErrPermanent = errors.New("permanent delivery failure")
+
+// Filter selects the notifications a sink receives
+type Filter func(Notification) bool
+
+// OnKinds matches notifications of any of the kinds
+func OnKinds(kinds ...string) Filter {
+	return func(n Notification) bool {
+		for _, k := range kinds {
+			if n.Kind == k {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// alertSeverityRank orders alert severities, unknown lowest
+var alertSeverityRank = map[string]int{
+	feeds.AlertSeverityMinor:    1,
+	feeds.AlertSeverityModerate: 2,
+	feeds.AlertSeveritySevere:   3,
+	feeds.AlertSeverityExtreme:  4,
+}
+
+// MinAlertSeverity matches alerts at least as severe as severity, one of
+// the feeds.AlertSeverity constants in any case, and every other kind of
+// notification
+func MinAlertSeverity(severity string) Filter {
+	least := alertSeverityRank[strings.ToLower(severity)]
+	return func(n Notification) bool {
+		return n.Alert == nil || alertSeverityRank[n.Alert.Severity] >= least
+	}
+}
+
+// ValidAlertSeverity reports whether s names an alert severity a filter can use
+func ValidAlertSeverity(s string) bool {
+	_, ok := alertSeverityRank[strings.ToLower(s)]
+	return ok
+}
+
+// Option configures a Notifier
+type Option func(*Notifier)
+
+// WithSink adds a sink receiving the notifications every filter matches
+func WithSink(s Sink, filters ...Filter) Option {
+	return func(n *Notifier) {
+		n.routes = append(n.routes, route{sink: s, filters: filters})
+	}
+}
+
+// WithRetry sets how failed deliveries are retried; the default is 3
+// attempts with 2s–30s backoff
+func WithRetry(policy feeds.RetryPolicy) Option {
+	return func(n *Notifier) {
+		n.retry = policy
+	}
+}
+
+// WithOnError sets a function called when a sink finally fails to deliver
+// a notification, after any retries
+func WithOnError(fn func(sink string, n Notification, err error)) Option {
+	return func(n *Notifier) {
+		n.onError = fn
+	}
+}
+
+// route is a sink and the filters of what it receives
+type route struct {
+	sink    Sink
+	filters []Filter
+}
+
+// matches reports whether every filter matches n
+func (r route) matches(n Notification) bool {
+	for _, f := range r.filters {
+		if !f(n) {
+			return false
+		}
+	}
+	return true
+}
+
+// Notifier delivers notifications to its sinks
+type Notifier struct {
+	routes  []route
+	retry   feeds.RetryPolicy
+	onError func(string, Notification, error)
+}
+
+// New creates a Notifier
+func New(options ...Option) *Notifier {
+	n := &Notifier{retry: defaultRetryPolicy}
+	for _, opt := range options {
+		opt(n)
+	}
+	return n
+}
+
+// Notify delivers a notification to every sink that wants it, concurrently,
+// retrying failures, and returns the sinks' errors joined
+func (n *Notifier) Notify(ctx context.Context, note Notification) error {
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for _, r := range n.routes {
+		if !r.matches(note) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := n.deliver(ctx, r.sink, note); err != nil {
+				if n.onError != nil {
+					n.onError(r.sink.Name(), note, err)
+				}
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", r.sink.Name(), err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// deliver sends note to one sink, retrying transient failures
+func (n *Notifier) deliver(ctx context.Context, s Sink, note Notification) error {
+	attempts := max(n.retry.MaxAttempts, 1)
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = s.Send(ctx, note); err == nil || errors.Is(err, ErrPermanent) || attempt == attempts {
+			return err
+		}
+		timer := time.NewTimer(n.retry.Delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// WatchAlerts notifies of every new weather alert the Scheduler sees until
+// ctx is done, and returns ctx's error. A location's first refresh counts
+// all of its active alerts as new.
+func (n *Notifier) WatchAlerts(ctx context.Context, sched *feeds.Scheduler) error {
+	changes, stop := sched.Watch(feeds.OnNewAlerts())
+	defer stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case c := <-changes:
+			for _, a := range c.NewAlerts() {
+				_ = n.Notify(ctx, AlertNotification(c.Location, a)) // reported through WithOnError
+			}
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// smtpTimeout bounds a whole delivery, from dialing to QUIT
+const smtpTimeout = 30 * time.Second
+
+// SMTPSink emails notifications
+type SMTPSink struct {
+	addr string // host:port
+	from *mail.Address
+	to   []*mail.Address
+	cfg  *sinkConfig
+}
+
+// NewSMTPSink returns a sink that emails each notification from from to
+// every address in to through the SMTP server at addr ("host:port"),
+// upgrading to TLS when the server offers STARTTLS. Emails carry both a
+// plain text and an HTML part; with WithTemplate, only the rendered text.
+func NewSMTPSink(addr, from string, to []string, options ...SinkOption) (*SMTPSink, error) {
+	cfg, err := newSinkConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("invalid SMTP server %q: %w", addr, err)
+	}
+	s := &SMTPSink{addr: addr, cfg: cfg}
+	if s.from, err = mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	if len(to) == 0 {
+		return nil, errors.New("no recipients")
+	}
+	for _, addr := range to {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", addr, err)
+		}
+		s.to = append(s.to, a)
+	}
+	return s, nil
+}
+
+// Name returns "smtp"
+func (s *SMTPSink) Name() string {
+	return "smtp"
+}
+
+// Send emails n
+func (s *SMTPSink) Send(ctx context.Context, n Notification) error {
+	msg, err := s.message(n)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", s.addr, err)
+	}
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+	host, _, _ := net.SplitHostPort(s.addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return smtpError(err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return smtpError(err)
+		}
+	}
+	if s.cfg.username != "" {
+		// PlainAuth refuses to send the password unencrypted except to localhost
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.username, s.cfg.password, host)); err != nil {
+			return smtpError(err)
+		}
+	}
+	if err := c.Mail(s.from.Address); err != nil {
+		return smtpError(err)
+	}
+	for _, rcpt := range s.to {
+		if err := c.Rcpt(rcpt.Address); err != nil {
+			return smtpError(err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpError(err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return smtpError(err)
+	}
+	if err := w.Close(); err != nil {
+		return smtpError(err)
+	}
+	_ = c.Quit() // the message is accepted; retrying would send it twice
+	return nil
+}
+
+// smtpError marks the server's permanent (5xx) replies as ErrPermanent
+func smtpError(err error) error {
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return nil
+}
+
+// message builds the email for n
+func (s *SMTPSink) message(n Notification) ([]byte, error) {
+	subject, err := render(s.cfg.subject, n, n.Subject)
+	if err != nil {
+		return nil, err
+	}
+	text, err := render(s.cfg.body, n, n.Text())
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&b, "%s: %s\r\n", key, value)
+	}
+	to := make([]string, len(s.to))
+	for i, a := range s.to {
+		to[i] = a.String()
+	}
+	header("From", s.from.String())
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", strings.ReplaceAll(subject, "\n", " ")))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(s.from.Address))
+	header("MIME-Version", "1.0")
+
+	if s.cfg.body != nil {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		b.WriteString("\r\n")
+		writeQuotedPrintable(&b, text)
+		return b.Bytes(), nil
+	}
+
+	boundary := randomHex(12)
+	header("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+	b.WriteString("\r\n")
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", n.HTML()},
+	} {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		header("Content-Type", part.contentType)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		b.WriteString("\r\n")
+		writeQuotedPrintable(&b, part.body)
+		b.WriteString("\r\n")
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return b.Bytes(), nil
+}
+
+// writeQuotedPrintable writes text quoted-printable, with CRLF line endings
+func writeQuotedPrintable(b *bytes.Buffer, text string) {
+	w := quotedprintable.NewWriter(b)
+	_, _ = w.Write([]byte(text))
+	_ = w.Close()
+}
+
+// messageID returns a unique Message-ID in the sender's domain
+func messageID(from string) string {
+	domain := "localhost"
+	if _, d, ok := strings.Cut(from, "@"); ok {
+		domain = d
+	}
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), randomHex(8), domain)
+}
+
+// randomHex returns n random bytes in hex
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
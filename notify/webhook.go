@@ -0,0 +1,229 @@
+package notify
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// discordMessageLimit is the most characters a Discord message may hold
+const discordMessageLimit = 2000
+
+// SinkOption configures a sink
+type SinkOption func(*sinkConfig)
+
+// sinkConfig holds the options every sink accepts; each sink uses those
+// that apply to it
+type sinkConfig struct {
+	body     *template.Template
+	subject  *template.Template
+	headers  http.Header
+	client   *http.Client
+	username string
+	password string
+	err      error // from parsing a template, reported by the constructor
+}
+
+// WithTemplate sets a text/template rendering the message body from the
+// Notification, e.g. "{{.Subject}}: {{.Alert.Headline}}". Without one, a
+// webhook posts the Notification as JSON, and chat and email sinks use
+// Notification.Text (and Notification.HTML for email).
+func WithTemplate(text string) SinkOption {
+	return func(c *sinkConfig) {
+		c.body, c.err = parseTemplate("body", text, c.err)
+	}
+}
+
+// WithSubjectTemplate sets a text/template rendering an email's subject
+// from the Notification; the default is Notification.Subject
+func WithSubjectTemplate(text string) SinkOption {
+	return func(c *sinkConfig) {
+		c.subject, c.err = parseTemplate("subject", text, c.err)
+	}
+}
+
+// WithHeader adds a header to a webhook's requests, such as Authorization
+func WithHeader(key, value string) SinkOption {
+	return func(c *sinkConfig) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithHTTPClient sets the HTTP client a webhook posts with
+func WithHTTPClient(client *http.Client) SinkOption {
+	return func(c *sinkConfig) {
+		c.client = client
+	}
+}
+
+// WithSMTPAuth sets the username and password an email sink authenticates with
+func WithSMTPAuth(username, password string) SinkOption {
+	return func(c *sinkConfig) {
+		c.username, c.password = username, password
+	}
+}
+
+// parseTemplate parses text, keeping the first error seen
+func parseTemplate(name, text string, prev error) (*template.Template, error) {
+	t, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, cmp.Or(prev, fmt.Errorf("invalid %s template: %w", name, err))
+	}
+	return t, prev
+}
+
+// newSinkConfig applies options over the defaults
+func newSinkConfig(options []SinkOption) (*sinkConfig, error) {
+	c := &sinkConfig{
+		headers: make(http.Header),
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c, c.err
+}
+
+// render executes t over n, or returns fallback when t is nil
+func render(t *template.Template, n Notification, fallback string) (string, error) {
+	if t == nil {
+		return fallback, nil
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, n); err != nil {
+		return "", fmt.Errorf("%w: rendering %s template: %v", ErrPermanent, t.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// StatusError is a webhook's reply with an unsuccessful HTTP status
+type StatusError struct {
+	Code int
+	Body string // the start of the response body
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("webhook returned %d %s", e.Code, http.StatusText(e.Code))
+	}
+	return fmt.Sprintf("webhook returned %d %s: %s", e.Code, http.StatusText(e.Code), e.Body)
+}
+
+// Is makes client errors other than 408 and 429 match ErrPermanent; server
+// errors are worth retrying
+func (e *StatusError) Is(target error) bool {
+	return target == ErrPermanent && e.Code >= 400 && e.Code < 500 &&
+		e.Code != http.StatusRequestTimeout && e.Code != http.StatusTooManyRequests
+}
+
+// WebhookSink posts notifications to a URL
+type WebhookSink struct {
+	name    string
+	url     string
+	cfg     *sinkConfig
+	payload func(Notification) (any, error) // nil to post the rendered body as is
+}
+
+// NewWebhookSink returns a sink that POSTs each Notification as JSON to url,
+// or, with WithTemplate, the rendered template as text
+func NewWebhookSink(url string, options ...SinkOption) (*WebhookSink, error) {
+	cfg, err := newSinkConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	s := &WebhookSink{name: "webhook", url: url, cfg: cfg}
+	if cfg.body == nil {
+		s.payload = func(n Notification) (any, error) { return n, nil }
+	}
+	return s, nil
+}
+
+// NewSlackSink returns a sink that posts each notification's text to a
+// Slack incoming webhook URL
+func NewSlackSink(url string, options ...SinkOption) (*WebhookSink, error) {
+	return newChatSink("slack", url, "text", 0, options)
+}
+
+// NewDiscordSink returns a sink that posts each notification's text to a
+// Discord webhook URL, cut to Discord's 2000 character limit
+func NewDiscordSink(url string, options ...SinkOption) (*WebhookSink, error) {
+	return newChatSink("discord", url, "content", discordMessageLimit, options)
+}
+
+// newChatSink returns a webhook posting {field: text}, with text cut to
+// limit characters when limit is positive
+func newChatSink(name, url, field string, limit int, options []SinkOption) (*WebhookSink, error) {
+	cfg, err := newSinkConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookSink{
+		name: name,
+		url:  url,
+		cfg:  cfg,
+		payload: func(n Notification) (any, error) {
+			text, err := render(cfg.body, n, n.Text())
+			if err != nil {
+				return nil, err
+			}
+			if r := []rune(text); limit > 0 && len(r) > limit {
+				text = string(r[:limit-1]) + "…"
+			}
+			return map[string]string{field: text}, nil
+		},
+	}, nil
+}
+
+// Name returns "webhook", "slack" or "discord"
+func (s *WebhookSink) Name() string {
+	return s.name
+}
+
+// Send posts n
+func (s *WebhookSink) Send(ctx context.Context, n Notification) error {
+	var body []byte
+	contentType := "application/json"
+	if s.payload != nil {
+		v, err := s.payload(n)
+		if err != nil {
+			return err
+		}
+		if body, err = json.Marshal(v); err != nil {
+			return fmt.Errorf("%w: encoding notification: %v", ErrPermanent, err)
+		}
+	} else {
+		text, err := render(s.cfg.body, n, "")
+		if err != nil {
+			return err
+		}
+		body, contentType = []byte(text), "text/plain; charset=utf-8"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "reef-na/notify")
+	for key, values := range s.cfg.headers {
+		req.Header[key] = values
+	}
+	resp, err := s.cfg.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return &StatusError{Code: resp.StatusCode, Body: strings.TrimSpace(string(snippet))}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"unicode/utf8"
+
+	"reef-na/feeds"
+)
+
+func TestStatusErrorIs(t *testing.T) {
+	tests := map[int]bool{
+		http.StatusBadRequest:          true,
+		http.StatusUnauthorized:        true,
+		http.StatusNotFound:            true,
+		http.StatusGone:                true,
+		http.StatusRequestTimeout:      false,
+		http.StatusTooManyRequests:     false,
+		http.StatusInternalServerError: false,
+		http.StatusBadGateway:          false,
+		http.StatusServiceUnavailable:  false,
+		http.StatusMultipleChoices:     false,
+	}
+	for code, permanent := range tests {
+		if got := errors.Is(&StatusError{Code: code}, ErrPermanent); got != permanent {
+			t.Errorf("StatusError %d matches ErrPermanent = %v, want %v", code, got, permanent)
+		}
+	}
+}
+
+// statusServer replies to each request with the next of codes, repeating the
+// last, and counts the requests
+func statusServer(t *testing.T, codes ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var n atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(n.Add(1)) - 1
+		w.WriteHeader(codes[min(i, len(codes)-1)])
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &n
+}
+
+func TestNotifyRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		codes     []int
+		requests  int32
+		code      int // of the final StatusError, 0 for none
+		permanent bool
+	}{
+		{"delivered", []int{http.StatusNoContent}, 1, 0, false},
+		{"delivered after server errors", []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}, 3, 0, false},
+		{"rate limited", []int{http.StatusTooManyRequests, http.StatusOK}, 2, 0, false},
+		{"server errors until out of attempts", []int{http.StatusInternalServerError}, 3, http.StatusInternalServerError, false},
+		{"client error is not retried", []int{http.StatusNotFound}, 1, http.StatusNotFound, true},
+		{"client error after a server error", []int{http.StatusBadGateway, http.StatusForbidden}, 2, http.StatusForbidden, true},
+	}
+	for _, tt := range tests {
+		srv, requests := statusServer(t, tt.codes...)
+		sink, err := NewWebhookSink(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var onError error
+		n := New(WithSink(sink), WithRetry(feeds.RetryPolicy{MaxAttempts: 3}),
+			WithOnError(func(_ string, _ Notification, err error) { onError = err }))
+
+		err = n.Notify(context.Background(), Notification{Kind: KindRule, Subject: "test"})
+		if got := requests.Load(); got != tt.requests {
+			t.Errorf("%s: made %d requests, want %d", tt.name, got, tt.requests)
+		}
+		var se *StatusError
+		if tt.code == 0 {
+			if err != nil || onError != nil {
+				t.Errorf("%s: error = %v, want none", tt.name, err)
+			}
+			continue
+		}
+		if !errors.As(err, &se) || se.Code != tt.code {
+			t.Errorf("%s: error = %v, want a %d StatusError", tt.name, err, tt.code)
+		}
+		if errors.Is(err, ErrPermanent) != tt.permanent {
+			t.Errorf("%s: error %v matches ErrPermanent = %v, want %v", tt.name, err, !tt.permanent, tt.permanent)
+		}
+		if !errors.Is(onError, se) {
+			t.Errorf("%s: WithOnError got %v", tt.name, onError)
+		}
+	}
+}
+
+func TestDiscordMessageLimit(t *testing.T) {
+	var content atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Content string }
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		content.Store(body.Content)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	sink, err := NewDiscordSink(srv.URL, WithTemplate("{{.Subject}}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		subject string
+		want    string
+	}{
+		{"short", "Gale warning", "Gale warning"},
+		{"at the limit", strings.Repeat("é", discordMessageLimit), strings.Repeat("é", discordMessageLimit)},
+		{"over the limit", strings.Repeat("é", discordMessageLimit+1), strings.Repeat("é", discordMessageLimit-1) + "…"},
+	}
+	for _, tt := range tests {
+		if err := sink.Send(context.Background(), Notification{Subject: tt.subject}); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got, _ := content.Load().(string)
+		if got != tt.want {
+			t.Errorf("%s: posted %d characters, want %d", tt.name, utf8.RuneCountInString(got), utf8.RuneCountInString(tt.want))
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"reef-na/feeds"
+)
+
+// defaultCooldown is the least time between firings of a rule at a location
+const defaultCooldown = time.Hour
+
+// Firing is a rule's condition starting to hold at a location
+type Firing struct {
+	Rule      string    `json:"rule"`
+	Condition string    `json:"condition"`
+	Location  string    `json:"location"` // the Scheduler's key: country code, or "lat,lon"
+	Values    []float64 `json:"values"`   // the value of each comparison's metric, in its unit
+	Message   string    `json:"message"`  // e.g. "wind gust 92 km/h (wind gust > 80 km/h)"
+	Time      time.Time `json:"time"`
+}
+
+// Option configures an Engine
+type Option func(*Engine)
+
+// WithCooldown sets the least time between firings of a rule at one
+// location for rules without their own; the default is an hour
+func WithCooldown(d time.Duration) Option {
+	return func(e *Engine) {
+		if d > 0 {
+			e.cooldown = d
+		}
+	}
+}
+
+// WithOnFire sets a function called with every Firing, from the goroutine
+// evaluating the rules
+func WithOnFire(fn func(Firing)) Option {
+	return func(e *Engine) {
+		e.onFire = fn
+	}
+}
+
+// ruleState is what the Engine remembers of a rule at a location
+type ruleState struct {
+	notified  bool // fired since the condition last started to hold
+	lastFired time.Time
+}
+
+// Engine evaluates rules against snapshots, firing once each time a rule's
+// condition starts to hold at a location. While the condition keeps holding
+// the rule does not fire again, and after it fires it stays quiet for its
+// cooldown even if the condition clears and holds again; a condition still
+// holding once the cooldown is over fires at the next evaluation.
+type Engine struct {
+	rules    []Rule
+	cooldown time.Duration
+	onFire   func(Firing)
+
+	mu    sync.Mutex
+	state map[string]*ruleState // by rule name and location
+}
+
+// NewEngine creates an Engine evaluating rules, whose names must be unique
+func NewEngine(rules []Rule, options ...Option) *Engine {
+	e := &Engine{
+		rules:    rules,
+		cooldown: defaultCooldown,
+		state:    make(map[string]*ruleState),
+	}
+	for _, opt := range options {
+		opt(e)
+	}
+	return e
+}
+
+// Rules returns the Engine's rules
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+// Evaluate checks every rule against a location's snapshot at now and
+// returns the rules that fire, also passing each to the WithOnFire function.
+// Rules needing a value the snapshot lacks are skipped and keep their state.
+func (e *Engine) Evaluate(location string, snap *feeds.Snapshot, now time.Time) []Firing {
+	var fired []Firing
+	e.mu.Lock()
+	for _, r := range e.rules {
+		match, values, ok := r.Evaluate(snap)
+		if !ok {
+			continue
+		}
+		key := r.Name + "\x00" + location
+		st := e.state[key]
+		if st == nil {
+			st = &ruleState{}
+			e.state[key] = st
+		}
+		if !match {
+			st.notified = false
+			continue
+		}
+		cooldown := r.Cooldown
+		if cooldown <= 0 {
+			cooldown = e.cooldown
+		}
+		if st.notified || (!st.lastFired.IsZero() && now.Sub(st.lastFired) < cooldown) {
+			continue
+		}
+		st.notified, st.lastFired = true, now
+		fired = append(fired, Firing{
+			Rule:      r.Name,
+			Condition: r.Condition,
+			Location:  location,
+			Values:    values,
+			Message:   message(r, values),
+			Time:      now,
+		})
+	}
+	e.mu.Unlock()
+
+	if e.onFire != nil {
+		for _, f := range fired {
+			e.onFire(f)
+		}
+	}
+	return fired
+}
+
+// message describes the values that made r fire
+func message(r Rule, values []float64) string {
+	parts := make([]string, len(r.Comparisons))
+	for i, c := range r.Comparisons {
+		parts[i] = fmt.Sprintf("%s %s (%s)", c.Metric, formatValue(values[i], c.Unit), c)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Run evaluates the rules against every snapshot the Scheduler refreshes
+// until ctx is done, and returns ctx's error. Like Scheduler.Watch,
+// refreshes that change no data are not evaluated.
+func (e *Engine) Run(ctx context.Context, sched *feeds.Scheduler) error {
+	changes, stop := sched.Watch()
+	defer stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case c := <-changes:
+			e.Evaluate(c.Location, c.Current, time.Now())
+		}
+	}
+}
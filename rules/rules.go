@@ -0,0 +1,305 @@
+// Package rules evaluates user-defined threshold conditions over feed data,
+// such as "temperature < -20", "aqi > 150" or "wind gust > 80 km/h", against
+// each refreshed snapshot, and fires when a condition starts to hold.
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"reef-na/feeds"
+)
+
+// metric is a value a condition can test, and how to read it from a snapshot
+type metric struct {
+	name  string // as shown in messages
+	unit  *unit  // base unit, nil for unitless values
+	value func(*feeds.Snapshot) (float64, bool)
+}
+
+// unit is a unit of measure and how to convert its metric's base unit to it
+type unit struct {
+	symbol   string
+	base     string // symbol of the base unit it converts from
+	fromBase func(float64) float64
+}
+
+// identity is the conversion of a base unit to itself
+func identity(v float64) float64 { return v }
+
+// Base units, in which thresholds without a unit are given
+var (
+	celsius     = &unit{symbol: "°C", base: "°C", fromBase: identity}
+	kmh         = &unit{symbol: "km/h", base: "km/h", fromBase: identity}
+	hectopascal = &unit{symbol: "hPa", base: "hPa", fromBase: identity}
+	millimeter  = &unit{symbol: "mm", base: "mm", fromBase: identity}
+	centimeter  = &unit{symbol: "cm", base: "cm", fromBase: identity}
+	percent     = &unit{symbol: "%", base: "%", fromBase: identity}
+)
+
+// units maps the lowercase spellings of every unit to it
+var units = map[string]*unit{
+	"c": celsius, "°c": celsius, "celsius": celsius,
+	"f":   {symbol: "°F", base: "°C", fromBase: feeds.CelsiusToFahrenheit},
+	"°f":  {symbol: "°F", base: "°C", fromBase: feeds.CelsiusToFahrenheit},
+	"kmh": kmh, "km/h": kmh, "kph": kmh,
+	"mph": {symbol: "mph", base: "km/h", fromBase: feeds.KmhToMph},
+	"m/s": {symbol: "m/s", base: "km/h", fromBase: func(v float64) float64 { return v / 3.6 }},
+	"kn":  {symbol: "kn", base: "km/h", fromBase: func(v float64) float64 { return v / 1.852 }},
+	"kt":  {symbol: "kn", base: "km/h", fromBase: func(v float64) float64 { return v / 1.852 }},
+	"hpa": hectopascal, "mb": hectopascal, "mbar": hectopascal,
+	"inhg": {symbol: "inHg", base: "hPa", fromBase: feeds.HectopascalsToInchesOfMercury},
+	"mm":   millimeter,
+	"cm":   centimeter,
+	"in":   {symbol: "in", base: "mm", fromBase: feeds.MillimetersToInches},
+	"%":    percent,
+}
+
+// convertible returns u as a unit of a metric whose base unit is base. A
+// length unit converts between millimeters and centimeters as needed.
+func (u *unit) convertible(base *unit) (*unit, bool) {
+	switch {
+	case u.base == base.symbol:
+		return u, true
+	case base == centimeter && u.base == "mm":
+		return &unit{symbol: u.symbol, base: "cm",
+			fromBase: func(v float64) float64 { return u.fromBase(v * 10) },
+		}, true
+	case base == millimeter && u == centimeter:
+		return &unit{symbol: "cm", base: "mm",
+			fromBase: func(v float64) float64 { return v / 10 },
+		}, true
+	}
+	return nil, false
+}
+
+// weather reads a current-conditions value
+func weather(fn func(*feeds.WeatherData) (float64, bool)) func(*feeds.Snapshot) (float64, bool) {
+	return func(s *feeds.Snapshot) (float64, bool) {
+		if s.Weather == nil {
+			return 0, false
+		}
+		return fn(s.Weather)
+	}
+}
+
+// airQuality reads an air quality value
+func airQuality(fn func(*feeds.AirQualityData) float64) func(*feeds.Snapshot) (float64, bool) {
+	return func(s *feeds.Snapshot) (float64, bool) {
+		aq, ok := s.Feeds["airQuality"].(*feeds.AirQualityData)
+		if !ok || aq == nil {
+			return 0, false
+		}
+		return fn(aq), true
+	}
+}
+
+var (
+	temperature   = &metric{name: "temperature", unit: celsius, value: weather(func(w *feeds.WeatherData) (float64, bool) { return w.TemperatureC, true })}
+	feelsLike     = &metric{name: "feels like", unit: celsius, value: weather(func(w *feeds.WeatherData) (float64, bool) { return w.FeelsLikeC, true })}
+	humidity      = &metric{name: "humidity", unit: percent, value: weather(func(w *feeds.WeatherData) (float64, bool) { return w.HumidityPct, true })}
+	cloudCover    = &metric{name: "cloud cover", unit: percent, value: weather(func(w *feeds.WeatherData) (float64, bool) { return w.CloudCoverPct, true })}
+	windSpeed     = &metric{name: "wind speed", unit: kmh, value: weather(func(w *feeds.WeatherData) (float64, bool) { return w.WindSpeedKmh, true })}
+	windGust      = &metric{name: "wind gust", unit: kmh, value: weather(func(w *feeds.WeatherData) (float64, bool) { return w.WindGustsKmh, true })}
+	pressure      = &metric{name: "pressure", unit: hectopascal, value: weather(func(w *feeds.WeatherData) (float64, bool) { return w.PressureHPa, w.PressureHPa > 0 })}
+	precipitation = &metric{name: "precipitation", unit: millimeter, value: weather(func(w *feeds.WeatherData) (float64, bool) { return w.PrecipitationMM, true })}
+	rain          = &metric{name: "rain", unit: millimeter, value: weather(func(w *feeds.WeatherData) (float64, bool) { return w.RainMM, true })}
+	snowfall      = &metric{name: "snowfall", unit: centimeter, value: weather(func(w *feeds.WeatherData) (float64, bool) { return w.SnowfallCM, true })}
+	uvIndex       = &metric{name: "UV index", value: weather(func(w *feeds.WeatherData) (float64, bool) {
+		if w.UVIndex == nil {
+			return 0, false
+		}
+		return *w.UVIndex, true
+	})}
+	aqi   = &metric{name: "AQI", value: airQuality(func(aq *feeds.AirQualityData) float64 { return float64(aq.AQI) })}
+	pm25  = &metric{name: "PM2.5", value: airQuality(func(aq *feeds.AirQualityData) float64 { return aq.PM25 })}
+	pm10  = &metric{name: "PM10", value: airQuality(func(aq *feeds.AirQualityData) float64 { return aq.PM10 })}
+	ozone = &metric{name: "ozone", value: airQuality(func(aq *feeds.AirQualityData) float64 { return aq.Ozone })}
+)
+
+// metrics maps metric names, lowercase without spaces, dashes, underscores
+// or dots, to the metric
+var metrics = map[string]*metric{
+	"temperature": temperature, "temp": temperature,
+	"feelslike": feelsLike, "apparenttemperature": feelsLike,
+	"humidity":   humidity,
+	"cloudcover": cloudCover, "clouds": cloudCover,
+	"wind": windSpeed, "windspeed": windSpeed,
+	"windgust": windGust, "windgusts": windGust, "gust": windGust, "gusts": windGust,
+	"pressure":      pressure,
+	"precipitation": precipitation, "precip": precipitation,
+	"rain":     rain,
+	"snowfall": snowfall, "snow": snowfall,
+	"uv": uvIndex, "uvindex": uvIndex,
+	"aqi": aqi, "airquality": aqi,
+	"pm25":  pm25,
+	"pm10":  pm10,
+	"ozone": ozone,
+}
+
+// metricKey normalizes a metric name for looking it up in metrics
+var metricKey = strings.NewReplacer(" ", "", "-", "", "_", "", ".", "")
+
+// comparisonPattern splits "wind gust > 80 km/h" into metric, operator,
+// number and unit
+var comparisonPattern = regexp.MustCompile(`^(.+?)\s*(<=|>=|==|!=|<|>)\s*([-+]?\d+(?:\.\d+)?)\s*(\S*)$`)
+
+// andPattern separates the comparisons of a condition
+var andPattern = regexp.MustCompile(`(?i)\s+and\s+|\s*&&\s*`)
+
+// Comparison is one test of a metric against a threshold, such as
+// "wind gust > 80 km/h"
+type Comparison struct {
+	Metric    string  // the metric's display name, e.g. "wind gust"
+	Op        string  // <, <=, >, >=, == or !=
+	Threshold float64 // as written, in Unit
+	Unit      string  // as normalized, e.g. "km/h"; empty for unitless metrics
+
+	metric *metric
+	unit   *unit
+}
+
+// String returns the comparison in normalized form
+func (c Comparison) String() string {
+	return fmt.Sprintf("%s %s %s", c.Metric, c.Op, formatValue(c.Threshold, c.Unit))
+}
+
+// value returns the snapshot's value of the metric in the comparison's unit
+func (c Comparison) value(s *feeds.Snapshot) (float64, bool) {
+	v, ok := c.metric.value(s)
+	if !ok {
+		return 0, false
+	}
+	if c.unit != nil {
+		v = c.unit.fromBase(v)
+	}
+	return v, true
+}
+
+// holds reports whether v passes the comparison
+func (c Comparison) holds(v float64) bool {
+	switch c.Op {
+	case "<":
+		return v < c.Threshold
+	case "<=":
+		return v <= c.Threshold
+	case ">":
+		return v > c.Threshold
+	case ">=":
+		return v >= c.Threshold
+	case "==":
+		return v == c.Threshold
+	}
+	return v != c.Threshold
+}
+
+// formatValue formats a value with up to one decimal and its unit
+func formatValue(v float64, unit string) string {
+	s := strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+	switch {
+	case unit == "":
+		return s
+	case strings.HasPrefix(unit, "°") || unit == "%":
+		return s + unit
+	}
+	return s + " " + unit
+}
+
+// parseComparison parses one comparison
+func parseComparison(s string) (Comparison, error) {
+	m := comparisonPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Comparison{}, fmt.Errorf("%q is not a comparison such as \"temperature < -20\"", s)
+	}
+	met, ok := metrics[metricKey.Replace(strings.ToLower(m[1]))]
+	if !ok {
+		return Comparison{}, fmt.Errorf("unknown metric %q", m[1])
+	}
+	threshold, err := strconv.ParseFloat(m[3], 64)
+	if err != nil {
+		return Comparison{}, fmt.Errorf("invalid threshold %q", m[3])
+	}
+	c := Comparison{Metric: met.name, Op: m[2], Threshold: threshold, metric: met}
+	switch {
+	case m[4] == "" && met.unit != nil:
+		c.unit = met.unit
+	case m[4] != "" && met.unit == nil:
+		return Comparison{}, fmt.Errorf("%s takes no unit, got %q", met.name, m[4])
+	case m[4] != "":
+		u, ok := units[strings.ToLower(m[4])]
+		if ok {
+			u, ok = u.convertible(met.unit)
+		}
+		if !ok {
+			return Comparison{}, fmt.Errorf("unit %q does not apply to %s", m[4], met.name)
+		}
+		c.unit = u
+	}
+	if c.unit != nil {
+		c.Unit = c.unit.symbol
+	}
+	return c, nil
+}
+
+// Rule is a named condition: one or more comparisons joined by "and", all
+// of which must hold
+type Rule struct {
+	Name        string
+	Condition   string // as written
+	Comparisons []Comparison
+	// Cooldown is the least time between firings of the rule at one
+	// location, the Engine's default when zero
+	Cooldown time.Duration
+}
+
+// ParseRule parses a rule's condition, such as "temperature < -20",
+// "aqi > 150" or "wind gust > 80 km/h and temperature < 0 °C". Metrics are
+// temperature, feels like, humidity, cloud cover, wind speed, wind gust,
+// pressure, precipitation, rain, snowfall, uv index, aqi, pm2.5, pm10 and
+// ozone. Thresholds without a unit are in °C, km/h, hPa, mm (cm for
+// snowfall) or %; °F, mph, m/s, kn, inHg and in are accepted too.
+func ParseRule(name, condition string) (Rule, error) {
+	if strings.TrimSpace(condition) == "" {
+		return Rule{}, errors.New("empty condition")
+	}
+	r := Rule{Name: name, Condition: condition}
+	for _, part := range andPattern.Split(strings.TrimSpace(condition), -1) {
+		c, err := parseComparison(part)
+		if err != nil {
+			return Rule{}, err
+		}
+		r.Comparisons = append(r.Comparisons, c)
+	}
+	return r, nil
+}
+
+// MustParseRule is ParseRule for conditions known to be valid; it panics on
+// an error
+func MustParseRule(name, condition string) Rule {
+	r, err := ParseRule(name, condition)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Evaluate reports whether every comparison holds for the snapshot, with
+// the values it found, in each comparison's unit. ok is false when the
+// snapshot lacks a value the rule needs.
+func (r Rule) Evaluate(s *feeds.Snapshot) (match bool, values []float64, ok bool) {
+	match = true
+	for _, c := range r.Comparisons {
+		v, found := c.value(s)
+		if !found {
+			return false, nil, false
+		}
+		values = append(values, v)
+		match = match && c.holds(v)
+	}
+	return match, values, true
+}
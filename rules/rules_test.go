@@ -0,0 +1,151 @@
+package rules
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"reef-na/feeds"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		condition string
+		want      []string // each comparison in normalized form
+	}{
+		{"temperature < -20", []string{"temperature < -20°C"}},
+		{"Temp <= -20 °C", []string{"temperature <= -20°C"}},
+		{"aqi > 150", []string{"AQI > 150"}},
+		{"PM2.5 >= 35.5", []string{"PM2.5 >= 35.5"}},
+		{"wind gust > 80 km/h", []string{"wind gust > 80 km/h"}},
+		{"wind_gusts>50mph", []string{"wind gust > 50 mph"}},
+		{"pressure < 29.5 inHg", []string{"pressure < 29.5 inHg"}},
+		{"snow > 2 in", []string{"snowfall > 2 in"}},
+		{"humidity != 100", []string{"humidity != 100%"}},
+		{"wind gust > 80 km/h and temperature < 0 C", []string{"wind gust > 80 km/h", "temperature < 0°C"}},
+		{"uv >= 8 && cloud cover < 20 %", []string{"UV index >= 8", "cloud cover < 20%"}},
+	}
+	for _, tt := range tests {
+		r, err := ParseRule("test", tt.condition)
+		if err != nil {
+			t.Errorf("ParseRule(%q): %v", tt.condition, err)
+			continue
+		}
+		var got []string
+		for _, c := range r.Comparisons {
+			got = append(got, c.String())
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseRule(%q) = %q, want %q", tt.condition, got, tt.want)
+		}
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	for _, condition := range []string{
+		"",
+		"   ",
+		"temperature",
+		"temperature < cold",
+		"visibility > 10",
+		"aqi > 150 ppm",
+		"humidity > 50 km/h",
+		"snowfall > 1 mph",
+		"temperature < 0 K",
+		"wind > 80 and",
+	} {
+		if _, err := ParseRule("test", condition); err == nil {
+			t.Errorf("ParseRule(%q) accepted an invalid condition", condition)
+		}
+	}
+}
+
+func TestComparisonUnits(t *testing.T) {
+	snap := &feeds.Snapshot{Weather: &feeds.WeatherData{
+		TemperatureC:    0,
+		WindGustsKmh:    80.4672,
+		WindSpeedKmh:    74.08,
+		PrecipitationMM: 12,
+		SnowfallCM:      2.54,
+	}}
+	tests := []struct {
+		comparison string
+		want       float64
+	}{
+		{"snowfall > 1", 2.54},
+		{"snowfall > 1 cm", 2.54},
+		{"snowfall > 10 mm", 25.4},
+		{"snowfall > 1 in", 1},
+		{"precipitation > 1", 12},
+		{"precipitation > 1 cm", 1.2},
+		{"precipitation > 1 in", 12 / 25.4},
+		{"temperature < 32 F", 32},
+		{"temperature < 32 °f", 32},
+		{"wind gust > 50 mph", 50},
+		{"wind speed > 40 kn", 40},
+		{"wind speed > 40 kt", 40},
+		{"wind speed > 20 m/s", 74.08 / 3.6},
+	}
+	for _, tt := range tests {
+		c, err := parseComparison(tt.comparison)
+		if err != nil {
+			t.Errorf("parseComparison(%q): %v", tt.comparison, err)
+			continue
+		}
+		if got, ok := c.value(snap); !ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%q: value = %v, %v, want %v", tt.comparison, got, ok, tt.want)
+		}
+	}
+}
+
+func TestEngineEvaluate(t *testing.T) {
+	var fires []Firing
+	e := NewEngine([]Rule{MustParseRule("gale", "wind gust > 80")},
+		WithCooldown(time.Hour), WithOnFire(func(f Firing) { fires = append(fires, f) }))
+	gusts := func(kmh float64) *feeds.Snapshot {
+		return &feeds.Snapshot{Weather: &feeds.WeatherData{WindGustsKmh: kmh}}
+	}
+	start := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	steps := []struct {
+		name  string
+		gusts float64
+		after time.Duration
+		fires bool
+	}{
+		{"starts to hold", 92, 0, true},
+		{"keeps holding", 95, 10 * time.Minute, false},
+		{"clears", 60, 20 * time.Minute, false},
+		{"holds within the cooldown", 90, 30 * time.Minute, false},
+		{"holds after the cooldown", 90, 61 * time.Minute, true},
+		{"keeps holding after firing", 90, 70 * time.Minute, false},
+	}
+	for _, s := range steps {
+		fired := e.Evaluate("US", gusts(s.gusts), start.Add(s.after))
+		if got := len(fired) == 1; got != s.fires || len(fired) > 1 {
+			t.Errorf("%s: fired %v, want %v", s.name, fired, s.fires)
+		}
+	}
+	if len(fires) != 2 {
+		t.Fatalf("WithOnFire got %d firings, want 2", len(fires))
+	}
+	want := Firing{
+		Rule:      "gale",
+		Condition: "wind gust > 80",
+		Location:  "US",
+		Values:    []float64{92},
+		Message:   "wind gust 92 km/h (wind gust > 80 km/h)",
+		Time:      start,
+	}
+	if f := fires[0]; f.Rule != want.Rule || f.Condition != want.Condition || f.Location != want.Location ||
+		!slices.Equal(f.Values, want.Values) || f.Message != want.Message || !f.Time.Equal(want.Time) {
+		t.Errorf("first firing = %+v, want %+v", f, want)
+	}
+
+	if fired := e.Evaluate("US", &feeds.Snapshot{}, start.Add(2*time.Hour)); len(fired) != 0 {
+		t.Errorf("snapshot without weather fired %v", fired)
+	}
+	if fired := e.Evaluate("CA", gusts(90), start.Add(30*time.Minute)); len(fired) != 1 {
+		t.Errorf("another location fired %v, want its own firing", fired)
+	}
+}